@@ -1,5 +1,15 @@
 package main
 
-func main() {
+import (
+	"os"
+
+	"github.com/strayca7/siam/internal/siamctl"
+	"github.com/strayca7/siam/pkg/app"
+	namev1 "github.com/strayca7/siam/staging/src/api/name/v1"
+)
 
+func main() {
+	application := siamctl.NewApp(namev1.SIAMCtl)
+	code := app.Run(application)
+	os.Exit(code)
 }
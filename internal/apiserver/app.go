@@ -5,7 +5,34 @@ import (
 	"github.com/strayca7/siam/pkg/app"
 )
 
+const commandDesc = `The SIAM API server validates and configures data
+for the api objects which include users, secrets and policies.
+The API Server services REST operations to do the api objects management.`
+
+// NewApp creates an App object with default parameters.
 func NewApp(basename string) *app.App {
 	opts := options.NewOptions()
+	application := app.NewApp("SIAM API Server",
+		basename,
+		app.WithOptions(opts),
+		app.WithDescription(commandDesc),
+		app.WithDefaultValidArgs(),
+		// TODO: remove it after the version flag is implemented in component-base.
+		app.WithNoVersion(),
+		app.WithRunFunc(run(opts)),
+		app.WithCommands(newMigrateCommand()),
+	)
+
+	return application
+}
+
+func run(opts *options.Options) app.RunFunc {
+	return func(basename string) error {
+		server, err := createAPIServer(opts)
+		if err != nil {
+			return err
+		}
 
+		return server.Run()
+	}
 }
@@ -0,0 +1,219 @@
+// Package audit records who changed which user, secret or policy and every authorization decision.
+//
+// The Middleware records an event for every request which is not a read. The handlers describe
+// the change of the resource with Before and After, and the authorization decisions with Decide,
+// then the event is written to a Sink after the response.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/authz"
+	"github.com/strayca7/siam/pkg/logger"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// Keys of the annotations in gin context.
+const (
+	beforeKey    = "audit.before"
+	afterKey     = "audit.after"
+	decisionsKey = "audit.decisions"
+)
+
+// ignoredFields are changed by every update, they are left out of the diff.
+var ignoredFields = []string{"updatedAt", "resourceVersion"}
+
+// Sink writes the audit events.
+type Sink interface {
+	Write(ctx context.Context, event *apiv1.AuditEvent) error
+}
+
+// Before records the resource before the change, it must be called before the resource is modified.
+func Before(c *gin.Context, obj any) {
+	c.Set(beforeKey, snapshot(obj))
+}
+
+// After records the resource after the change.
+func After(c *gin.Context, obj any) {
+	c.Set(afterKey, snapshot(obj))
+}
+
+// Decide records the authorization decisions of the requests, in the same order.
+func Decide(c *gin.Context, requests []*authz.Request, decisions []*authz.Decision) {
+	recorded := make([]apiv1.AuditDecision, 0, len(decisions))
+	for i, d := range decisions {
+		recorded = append(recorded, apiv1.AuditDecision{
+			Subject:  requests[i].Subject,
+			Action:   requests[i].Action,
+			Resource: requests[i].Resource,
+			Allowed:  d.Allowed,
+		})
+	}
+	c.Set(decisionsKey, recorded)
+}
+
+// Middleware records the requests which are not reads to the sink, the failures of the sink
+// are logged without failing the requests.
+func Middleware(sink Sink) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		// the unknown routes change nothing
+		if c.FullPath() == "" {
+			return
+		}
+
+		ctx := c.Request.Context()
+		event := newEvent(c)
+		// the event is written even if the client has gone
+		if err := sink.Write(context.WithoutCancel(ctx), event); err != nil {
+			logger.L().Error("Failed to write audit event",
+				zap.String("trace_id", event.TraceID),
+				zap.String("action", event.Action),
+				zap.String("resource", event.Resource),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// newEvent builds the event of the finished request with its annotations.
+func newEvent(c *gin.Context) *apiv1.AuditEvent {
+	ctx := c.Request.Context()
+	actor, _ := middleware.UsernameFromContext(ctx)
+	event := &apiv1.AuditEvent{
+		Tenant:    store.TenantFromContext(ctx),
+		Actor:     actor,
+		Action:    actionOf(c.Request.Method, c.FullPath()),
+		Resource:  resourceOf(c.Request.URL.Path),
+		Status:    c.Writer.Status(),
+		TraceID:   logger.TraceID(ctx),
+		ClientIP:  c.ClientIP(),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	before, _ := c.Get(beforeKey)
+	after, _ := c.Get(afterKey)
+	event.Diff = diff(toFields(before), toFields(after))
+	if decisions, ok := c.Get(decisionsKey); ok {
+		event.Decisions, _ = decisions.([]apiv1.AuditDecision)
+	}
+	return event
+}
+
+// verbs maps the methods to the actions on the resources.
+var verbs = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// actionOf derives the action from the route, e.g. `PUT /v1/users/:name` is `user:update`,
+// `POST /v1/users/:name/secrets/:accessKey/rotate` is `secret:rotate` and `POST /v1/authz` is `authz`.
+// The tenant scoped routes have the same actions, e.g. `POST /v1/tenants/:tenant/authz` is `authz`.
+func actionOf(method, route string) string {
+	// the first segment is the API version
+	segments := strings.Split(strings.Trim(route, "/"), "/")[1:]
+	if len(segments) > 2 && segments[0] == "tenants" && segments[1] == ":tenant" {
+		segments = segments[2:]
+	}
+
+	var statics []string
+	for _, s := range segments {
+		if !strings.HasPrefix(s, ":") && !strings.HasPrefix(s, "*") {
+			statics = append(statics, s)
+		}
+	}
+	if len(statics) == 0 {
+		return strings.ToLower(method)
+	}
+
+	last := statics[len(statics)-1]
+	isCollection := strings.HasSuffix(last, "s")
+	switch {
+	case isCollection:
+		return singular(last) + ":" + verbs[method]
+	case len(statics) == 1:
+		return last
+	default:
+		return singular(statics[len(statics)-2]) + ":" + last
+	}
+}
+
+// singular returns the singular form of the collection name.
+func singular(collection string) string {
+	if s, ok := strings.CutSuffix(collection, "ies"); ok {
+		return s + "y"
+	}
+	return strings.TrimSuffix(collection, "s")
+}
+
+// resourceOf returns the resource path without the API version, and without the tenant prefix
+// of the tenant scoped paths, e.g. `/v1/tenants/acme/users/alice` is `users/alice`.
+func resourceOf(path string) string {
+	path = strings.Trim(path, "/")
+	if _, rest, ok := strings.Cut(path, "/"); ok {
+		path = rest
+	}
+	if rest, ok := strings.CutPrefix(path, "tenants/"); ok {
+		if _, resource, ok := strings.Cut(rest, "/"); ok {
+			return resource
+		}
+	}
+	return path
+}
+
+// snapshot copies the JSON fields of the object, so that the later modifications do not change it.
+func snapshot(obj any) map[string]any {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	for _, f := range ignoredFields {
+		delete(fields, f)
+	}
+	return fields
+}
+
+func toFields(v any) map[string]any {
+	fields, _ := v.(map[string]any)
+	return fields
+}
+
+// diff returns the fields which are different between before and after, nil if there is none.
+func diff(before, after map[string]any) map[string]apiv1.AuditChange {
+	changes := map[string]apiv1.AuditChange{}
+	for k, b := range before {
+		if a, ok := after[k]; !ok || !reflect.DeepEqual(a, b) {
+			changes[k] = apiv1.AuditChange{Before: b, After: after[k]}
+		}
+	}
+	for k, a := range after {
+		if _, ok := before[k]; !ok {
+			changes[k] = apiv1.AuditChange{After: a}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// storeSink writes the events to the store, it is the only sink which can be queried.
+type storeSink struct {
+	store store.Factory
+}
+
+// NewStoreSink creates a Sink which writes the events to the audit_events of the store.
+func NewStoreSink(store store.Factory) Sink {
+	return &storeSink{store: store}
+}
+
+func (s *storeSink) Write(ctx context.Context, event *apiv1.AuditEvent) error {
+	return s.store.AuditEvents().Create(ctx, event)
+}
+
+// FileSink appends the events to a file as JSON lines.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink creates a FileSink which appends to the file at path, the file is created if it does not exist.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+	return &FileSink{file: f}, nil
+}
+
+func (s *FileSink) Write(_ context.Context, event *apiv1.AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(line)
+	return err
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// loggerSink writes the events to the logger.
+type loggerSink struct {
+	log *zap.Logger
+}
+
+// NewLoggerSink creates a Sink which writes the events to the logger at info level.
+func NewLoggerSink(log *zap.Logger) Sink {
+	return &loggerSink{log: log}
+}
+
+func (s *loggerSink) Write(_ context.Context, event *apiv1.AuditEvent) error {
+	s.log.Info("Audit event",
+		zap.String("actor", event.Actor),
+		zap.String("action", event.Action),
+		zap.String("resource", event.Resource),
+		zap.Int("status", event.Status),
+		zap.Any("diff", event.Diff),
+		zap.Any("decisions", event.Decisions),
+		zap.String("trace_id", event.TraceID),
+		zap.String("client_ip", event.ClientIP),
+		zap.Time("time", event.CreatedAt),
+	)
+	return nil
+}
@@ -0,0 +1,120 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/auth"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// secretKeyFunc returns a sign.KeyFunc which looks up the unexpired secrets in the store.
+// The signing key of a secret is sealed by the server key at rest, see auth.KeyCipher.
+func secretKeyFunc(s store.Factory, keys *auth.KeyCipher) sign.KeyFunc {
+	return func(ctx context.Context, accessKey string) ([]byte, string, error) {
+		secret, err := s.Secrets().GetByAccessKey(ctx, accessKey)
+		if err != nil {
+			if serrors.IsCode(err, code.ErrSecretNotFound) {
+				return nil, "", serrors.WithCodef(code.ErrSignatureInvalid, "access key %q not found", accessKey)
+			}
+			return nil, "", err
+		}
+		if secret.Expired(time.Now()) {
+			return nil, "", serrors.WithCodef(code.ErrSignatureInvalid, "access key %q is expired", accessKey)
+		}
+
+		key, err := keys.Open(secret.SigningKey, []byte(secret.AccessKey))
+		if err != nil {
+			return nil, "", serrors.WrapC(err, code.ErrUnknown, "open signing key of %q", accessKey)
+		}
+		return key, middleware.SignatureOwner(secret.Tenant, secret.Username), nil
+	}
+}
+
+// tokenClaimsFunc returns a middleware.ClaimsFunc which rejects the tokens of the users deleted after the tokens
+// were issued, including the users registered again under the same names, and the sessions of such roles.
+// A session is also rejected if the user who assumed it is gone.
+func tokenClaimsFunc(s store.Factory) middleware.ClaimsFunc {
+	return func(ctx context.Context, claims *auth.Claims) error {
+		if claims.IssuedAt == nil {
+			return serrors.WithCode(code.ErrTokenInvalid, "token has no issue time")
+		}
+		// the issue time is in seconds
+		issuedAt := claims.IssuedAt.Time
+		tenant := claims.Tenant
+		if tenant == "" {
+			tenant = model.DefaultTenant
+		}
+		ctx = store.WithTenant(ctx, tenant)
+
+		username := claims.Subject
+		if role, ok := model.RoleFromSubject(claims.Subject); ok {
+			r, err := s.Roles().Get(ctx, role)
+			if err != nil {
+				if serrors.IsCode(err, code.ErrRoleNotFound) {
+					return serrors.WrapC(err, code.ErrTokenInvalid, "role %q of the session no longer exists", role)
+				}
+				return err
+			}
+			if r.CreatedAt.Truncate(time.Second).After(issuedAt) {
+				return serrors.WithCodef(code.ErrTokenInvalid, "session was issued before role %q was created", role)
+			}
+			username = claims.AssumedBy
+		}
+
+		user, err := s.Users().Get(ctx, username)
+		if err != nil {
+			if serrors.IsCode(err, code.ErrUserNotFound) {
+				return serrors.WrapC(err, code.ErrTokenInvalid, "user %q of the token no longer exists", username)
+			}
+			return err
+		}
+		if user.CreatedAt.Truncate(time.Second).After(issuedAt) {
+			return serrors.WithCodef(code.ErrTokenInvalid, "token was issued before user %q was created", username)
+		}
+		return nil
+	}
+}
+
+// sealLegacySigningKeys seals the signing keys of the secrets created by the earlier versions, which stored
+// the unsealed digests of the secret keys, and clears the digests. It is idempotent.
+func sealLegacySigningKeys(ctx context.Context, db *gorm.DB, keys *auth.KeyCipher) error {
+	var secrets []*apiv1.Secret
+	ctx = pkgdatabase.WithPrimary(ctx)
+	if err := db.WithContext(ctx).Where("signing_key = ? AND secret_key_hash <> ?", "", "").
+		Find(&secrets).Error; err != nil {
+		return fmt.Errorf("list legacy secrets: %w", err)
+	}
+	for _, secret := range secrets {
+		key, err := hex.DecodeString(secret.SecretKeyHash)
+		if err != nil {
+			return fmt.Errorf("decode secret key hash of %q: %w", secret.AccessKey, err)
+		}
+		sealed, err := keys.Seal(key, []byte(secret.AccessKey))
+		if err != nil {
+			return fmt.Errorf("seal signing key of %q: %w", secret.AccessKey, err)
+		}
+		// the columns are internal, so neither the update time nor the resource version changes
+		if err := db.WithContext(ctx).Model(secret).
+			UpdateColumns(map[string]any{"signing_key": sealed, "secret_key_hash": ""}).Error; err != nil {
+			return fmt.Errorf("update signing key of %q: %w", secret.AccessKey, err)
+		}
+	}
+	if len(secrets) > 0 {
+		logger.L().Info("Sealed the signing keys of the legacy secrets", zap.Int("count", len(secrets)))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package attachment implements the handlers of the policies attached to the users, the groups and the roles.
+package attachment
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// AttachmentController creates a policy attachment handler of the principals of a single kind.
+type AttachmentController struct {
+	store store.Factory
+	// kind is one of apiv1.PrincipalUser, apiv1.PrincipalGroup and apiv1.PrincipalRole.
+	kind string
+}
+
+// NewAttachmentController creates a policy attachment handler of the principals of the kind,
+// the principal is named by the `name` parameter of the route.
+func NewAttachmentController(store store.Factory, kind string) *AttachmentController {
+	return &AttachmentController{store: store, kind: kind}
+}
+
+func (a *AttachmentController) principal(c *gin.Context) model.Principal {
+	return model.Principal{Kind: a.kind, Name: c.Param("name")}
+}
+
+// checkPrincipal checks the principal exists.
+func (a *AttachmentController) checkPrincipal(ctx context.Context, tx store.Factory, name string) error {
+	var err error
+	switch a.kind {
+	case apiv1.PrincipalUser:
+		_, err = tx.Users().Get(ctx, name)
+	case apiv1.PrincipalGroup:
+		_, err = tx.Groups().Get(ctx, name)
+	case apiv1.PrincipalRole:
+		_, err = tx.Roles().Get(ctx, name)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package attachment
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// CreateAttachmentRequest defines the request body of the policy attachment,
+// the policy is identified by its owner and name.
+type CreateAttachmentRequest struct {
+	PolicyOwner string `json:"policyOwner" binding:"required"`
+	PolicyName  string `json:"policyName"  binding:"required"`
+}
+
+// Create attach a policy to the principal.
+func (a *AttachmentController) Create(c *gin.Context) {
+	var r CreateAttachmentRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// the admins attach the policies of any user, the others only their own policies
+	ctx := c.Request.Context()
+	username, _ := middleware.UsernameFromContext(ctx)
+	if r.PolicyOwner != username && !middleware.AdminFromContext(ctx) {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied,
+			"user %q is not allowed to attach policy %q of user %q", username, r.PolicyName, r.PolicyOwner), nil)
+		return
+	}
+
+	principal := a.principal(c)
+	attachment := &apiv1.PolicyAttachment{
+		PrincipalKind: principal.Kind,
+		PrincipalName: principal.Name,
+		PolicyOwner:   r.PolicyOwner,
+		PolicyName:    r.PolicyName,
+	}
+	err := a.store.Tx(ctx, func(tx store.Factory) error {
+		if err := a.checkPrincipal(ctx, tx, principal.Name); err != nil {
+			return err
+		}
+		if _, err := tx.Policies().Get(ctx, r.PolicyOwner, r.PolicyName); err != nil {
+			return err
+		}
+		return tx.PolicyAttachments().Create(ctx, attachment)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, attachment)
+
+	core.WriteResponse(c, nil, attachment)
+}
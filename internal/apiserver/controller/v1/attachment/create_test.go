@@ -0,0 +1,86 @@
+package attachment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store/memory"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/policy"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "attachment")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+	gin.SetMode(gin.TestMode)
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestCreatePolicyOwner(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	for _, name := range []string{"alice", "bob"} {
+		if err := s.Users().Create(ctx, &apiv1.User{ObjectMeta: metav1.ObjectMeta{Name: name}}); err != nil {
+			t.Fatalf("create user %q: %v", name, err)
+		}
+		p := &apiv1.Policy{
+			ObjectMeta: metav1.ObjectMeta{Name: "everything"},
+			Username:   name,
+			Document: policy.Document{Version: policy.Version20251001, Statements: []policy.Statement{
+				{Effect: policy.Allow, Actions: []string{"*"}, Resources: []string{"*"}},
+			}},
+		}
+		if err := s.Policies().Create(ctx, p); err != nil {
+			t.Fatalf("create policy of %q: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		caller string
+		admin  bool
+		owner  string
+		want   int
+	}{
+		{"own policy", "alice", false, "alice", http.StatusOK},
+		{"policy of another user", "alice", false, "bob", http.StatusForbidden},
+		{"admin attaches a policy of another user", "root", true, "bob", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			engine.POST("/users/:name/attachments", func(c *gin.Context) {
+				ctx := middleware.ContextWithUsername(c.Request.Context(), tt.caller)
+				c.Request = c.Request.WithContext(middleware.ContextWithAdmin(ctx, tt.admin))
+			}, NewAttachmentController(s, apiv1.PrincipalUser).Create)
+
+			body := `{"policyOwner":"` + tt.owner + `","policyName":"everything"}`
+			r := httptest.NewRequest(http.MethodPost, "/users/alice/attachments", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("Create() = %d %s, want %d", w.Code, w.Body, tt.want)
+			}
+		})
+	}
+}
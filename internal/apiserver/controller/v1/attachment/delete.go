@@ -0,0 +1,18 @@
+package attachment
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Delete detach a policy from the principal, the policy itself is kept.
+func (a *AttachmentController) Delete(c *gin.Context) {
+	err := a.store.PolicyAttachments().Delete(c.Request.Context(), a.principal(c), c.Param("owner"), c.Param("policy"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
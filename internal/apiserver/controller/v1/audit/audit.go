@@ -0,0 +1,95 @@
+// Package audit implements the audit event handlers of siam-apiserver.
+package audit
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+const defaultListLimit = 20
+
+// AuditController creates an audit handler used to query the audit events in the store.
+type AuditController struct {
+	store store.Factory
+	// queryable is false if the audit events are not written to the store.
+	queryable bool
+}
+
+// NewAuditController creates an audit handler, the queries fail if queryable is false.
+func NewAuditController(store store.Factory, queryable bool) *AuditController {
+	return &AuditController{store: store, queryable: queryable}
+}
+
+// ListAuditEventRequest defines the filter query parameters of the audit event list,
+// the pagination and the selectors are bound to metav1.ListOptions.
+type ListAuditEventRequest struct {
+	Actor string `form:"actor"`
+	// Resource selects the resource and its sub resources, e.g. `users/alice`.
+	Resource string `form:"resource"`
+	// Since and Until select the events created in [since, until), they are RFC 3339 times.
+	Since time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// List list the audit events of the tenant matching the filter, the latest events first.
+// Only the admins of the tenant and the system admins are allowed.
+func (a *AuditController) List(c *gin.Context) {
+	if !a.queryable {
+		core.WriteResponse(c, serrors.WithCode(code.ErrAuditQueryUnsupported,
+			"audit events are not written to the store"), nil)
+		return
+	}
+
+	var r ListAuditEventRequest
+	if err := bind.Query(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	var page metav1.ListOptions
+	if err := bind.List(c, &page); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if page.Limit == 0 {
+		page.Limit = defaultListLimit
+	}
+	opts, err := store.NewListOptions(&page, new(apiv1.AuditEvent).Fields())
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// the user is an admin of the tenant of the events, or a system admin of the default tenant
+	username, _ := middleware.UsernameFromContext(c.Request.Context())
+	tenant, _ := middleware.TenantFromContext(c.Request.Context())
+	user, err := a.store.Users().Get(store.WithTenant(c.Request.Context(), tenant), username)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if !user.IsAdmin {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied,
+			"user %q is not allowed to query audit events", username), nil)
+		return
+	}
+
+	filter := store.AuditFilter{Actor: r.Actor, Resource: r.Resource, Since: r.Since, Until: r.Until}
+	list, err := a.store.AuditEvents().List(c.Request.Context(), filter, opts)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	list.SetContinue(page.Offset, len(list.Items))
+
+	core.WriteResponse(c, nil, list)
+}
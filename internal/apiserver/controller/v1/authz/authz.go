@@ -0,0 +1,111 @@
+// Package authz implements the authorization decision handlers of siam-apiserver.
+package authz
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/authz"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// AuthzController creates an authorization handler used to make decisions with the stored policies.
+type AuthzController struct {
+	authorizer *authz.Authorizer
+}
+
+// NewAuthzController creates an authorization handler.
+func NewAuthzController(store store.Factory) *AuthzController {
+	return &AuthzController{authorizer: authz.NewAuthorizer(&policyGetter{store: store})}
+}
+
+// AuthorizeRequest defines the request body of the authorization, it carries a batch of requests.
+type AuthorizeRequest struct {
+	Requests []*authz.Request `json:"requests" binding:"required,min=1,max=100,dive"`
+}
+
+// AuthorizeResponse defines the response body of the authorization,
+// Decisions are in the same order as the requests.
+type AuthorizeResponse struct {
+	Decisions []*authz.Decision `json:"decisions"`
+}
+
+// Authorize makes the allow or deny decisions for a batch of requests.
+// The sourceIp context value of each request defaults to the IP of the client.
+func (a *AuthzController) Authorize(c *gin.Context) {
+	var r AuthorizeRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	for _, req := range r.Requests {
+		if _, ok := req.Context[authz.ContextKeySourceIP]; !ok {
+			if req.Context == nil {
+				req.Context = map[string]string{}
+			}
+			req.Context[authz.ContextKeySourceIP] = c.ClientIP()
+		}
+	}
+
+	decisions, err := a.authorizer.AuthorizeBatch(c.Request.Context(), r.Requests)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Decide(c, r.Requests, decisions)
+
+	core.WriteResponse(c, nil, &AuthorizeResponse{Decisions: decisions})
+}
+
+// policyGetter resolves the effective policies of the subject from the store. A user gets the policies it owns
+// and the policies attached to the user and to its groups, while a role session, whose subject is `role:<name>`,
+// gets only the policies attached to the role. The attached policies are named `<owner>/<name>` in the decisions.
+type policyGetter struct {
+	store store.Factory
+}
+
+func (g *policyGetter) GetPolicies(ctx context.Context, subject string) ([]authz.Policy, error) {
+	var owned []*apiv1.Policy
+	var principals []model.Principal
+	if role, ok := model.RoleFromSubject(subject); ok {
+		principals = append(principals, model.Principal{Kind: apiv1.PrincipalRole, Name: role})
+	} else {
+		list, err := g.store.Policies().List(ctx, subject, store.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		owned = list.Items
+
+		groups, err := g.store.GroupMembers().ListGroups(ctx, subject)
+		if err != nil {
+			return nil, err
+		}
+		principals = append(principals, model.Principal{Kind: apiv1.PrincipalUser, Name: subject})
+		for _, group := range groups {
+			principals = append(principals, model.Principal{Kind: apiv1.PrincipalGroup, Name: group})
+		}
+	}
+	attached, err := g.store.PolicyAttachments().ListPolicies(ctx, principals)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]authz.Policy, 0, len(owned)+len(attached))
+	for _, p := range owned {
+		policies = append(policies, authz.Policy{Name: p.Name, Document: p.Document})
+	}
+	for _, p := range attached {
+		// the owned policies attached to the owner are already there
+		if p.Username == subject {
+			continue
+		}
+		policies = append(policies, authz.Policy{Name: p.Username + "/" + p.Name, Document: p.Document})
+	}
+	return policies, nil
+}
@@ -0,0 +1,44 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// AddMemberRequest defines the request body of adding a member to the group.
+type AddMemberRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// AddMember add a user to the group, the user gets the policies attached to the group.
+func (g *GroupController) AddMember(c *gin.Context) {
+	var r AddMemberRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	member := &apiv1.GroupMember{Group: c.Param("name"), Username: r.Username}
+	err := g.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		if _, err := tx.Groups().Get(ctx, member.Group); err != nil {
+			return err
+		}
+		if _, err := tx.Users().Get(ctx, member.Username); err != nil {
+			return err
+		}
+		return tx.GroupMembers().Create(ctx, member)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, member)
+
+	core.WriteResponse(c, nil, member)
+}
@@ -0,0 +1,34 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// CreateGroupRequest defines the request body of the group creation.
+type CreateGroupRequest struct {
+	Name        string `json:"name"        binding:"required,alphanum,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// Create add new group to the storage.
+func (g *GroupController) Create(c *gin.Context) {
+	var r CreateGroupRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	group := &apiv1.Group{Name: r.Name, Description: r.Description}
+	if err := g.store.Groups().Create(c.Request.Context(), group); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, group)
+
+	core.WriteResponse(c, nil, group)
+}
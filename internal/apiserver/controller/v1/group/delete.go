@@ -0,0 +1,42 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// Delete delete a group by the group identifier, the memberships and the attached policies of the group
+// are deleted too, and the group is no longer trusted by any role.
+func (g *GroupController) Delete(c *gin.Context) {
+	name := c.Param("name")
+	err := g.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		group, err := tx.Groups().Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := tx.Groups().Delete(ctx, name); err != nil {
+			return err
+		}
+		audit.Before(c, group)
+		if err := tx.GroupMembers().DeleteCollection(ctx, name); err != nil {
+			return err
+		}
+		principal := model.Principal{Kind: apiv1.PrincipalGroup, Name: name}
+		if err := tx.PolicyAttachments().DeleteCollection(ctx, principal); err != nil {
+			return err
+		}
+		return tx.Roles().Untrust(ctx, principal)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
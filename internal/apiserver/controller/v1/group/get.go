@@ -0,0 +1,18 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get a group by the group identifier.
+func (g *GroupController) Get(c *gin.Context) {
+	group, err := g.store.Groups().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, group)
+}
@@ -0,0 +1,16 @@
+// Package group implements the group and the group membership handlers of siam-apiserver.
+package group
+
+import (
+	"github.com/strayca7/siam/internal/apiserver/store"
+)
+
+// GroupController creates a group handler used to handle request for group resource.
+type GroupController struct {
+	store store.Factory
+}
+
+// NewGroupController creates a group handler.
+func NewGroupController(store store.Factory) *GroupController {
+	return &GroupController{store: store}
+}
@@ -0,0 +1,39 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+const defaultListLimit = 20
+
+// List list the groups in the storage ordered by id.
+func (g *GroupController) List(c *gin.Context) {
+	var r metav1.ListOptions
+	if err := bind.List(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.Limit == 0 {
+		r.Limit = defaultListLimit
+	}
+	opts, err := store.NewListOptions(&r, new(apiv1.Group).Fields())
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	list, err := g.store.Groups().List(c.Request.Context(), opts)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	list.SetContinue(r.Offset, len(list.Items))
+
+	core.WriteResponse(c, nil, list)
+}
@@ -0,0 +1,17 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// RemoveMember remove a user from the group.
+func (g *GroupController) RemoveMember(c *gin.Context) {
+	if err := g.store.GroupMembers().Delete(c.Request.Context(), c.Param("name"), c.Param("username")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
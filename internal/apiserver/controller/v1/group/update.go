@@ -0,0 +1,42 @@
+package group
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// UpdateGroupRequest defines the request body of the group update.
+// Only the non-nil fields are updated.
+type UpdateGroupRequest struct {
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// Update update a group by the group identifier.
+func (g *GroupController) Update(c *gin.Context) {
+	var r UpdateGroupRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	group, err := g.store.Groups().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, group)
+	if r.Description != nil {
+		group.Description = *r.Description
+	}
+
+	if err := g.store.Groups().Update(c.Request.Context(), group); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, group)
+
+	core.WriteResponse(c, nil, group)
+}
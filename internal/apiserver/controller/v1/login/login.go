@@ -0,0 +1,69 @@
+// Package login implements the login handler of siam-apiserver which exchanges the password for a token.
+package login
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// dummyPassword is a bcrypt hash of the default cost which the password of an unknown user is compared with,
+// so the login takes as long as for a known user and its timing does not reveal whether the user exists.
+const dummyPassword = "$2a$10$bdBsRvsSOiA9I/.laXzQwursSJUn43DiGVpDJfY4ASJE4ypAZKfkq"
+
+// LoginController creates a login handler used to issue the tokens.
+type LoginController struct {
+	store store.Factory
+	jwt   *auth.JWT
+}
+
+// NewLoginController creates a login handler.
+func NewLoginController(store store.Factory, jwt *auth.JWT) *LoginController {
+	return &LoginController{store: store, jwt: jwt}
+}
+
+// LoginRequest defines the request body of the login.
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// Login verifies the password of the user and issues a bearer token.
+// An unknown user is reported as an incorrect password to avoid leaking which users exist.
+func (l *LoginController) Login(c *gin.Context) {
+	var r LoginRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	user, err := l.store.Users().Get(c.Request.Context(), r.Username)
+	if err != nil {
+		if serrors.IsCode(err, code.ErrUserNotFound) {
+			_ = auth.Compare(dummyPassword, r.Password)
+			core.WriteResponse(c, serrors.WithCodef(code.ErrPasswordIncorrect, "user %q not found", r.Username), nil)
+			return
+		}
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if err := auth.Compare(user.Password, r.Password); err != nil {
+		core.WriteResponse(c, serrors.WrapC(err, code.ErrPasswordIncorrect, "verify password of user %q", user.Name), nil)
+		return
+	}
+
+	token, expiresAt, err := l.jwt.Sign(user.Tenant, user.Name)
+	if err != nil {
+		core.WriteResponse(c, serrors.WrapC(err, code.ErrUnknown, "sign token for user %q", user.Name), nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, &apiv1.Token{Token: token, ExpiresAt: expiresAt})
+}
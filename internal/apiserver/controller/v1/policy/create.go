@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/policy"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// CreatePolicyRequest defines the request body of the policy creation.
+type CreatePolicyRequest struct {
+	Name        string          `json:"name"        binding:"required,alphanum,max=64"`
+	Description string          `json:"description" binding:"max=255"`
+	Document    policy.Document `json:"document"`
+
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Create add new policy of the user to the storage.
+func (p *PolicyController) Create(c *gin.Context) {
+	var r CreatePolicyRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := validateDocument(&r.Document); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	username := c.Param("name")
+	pol := &apiv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			InstanceID:  metav1.NewInstanceID("policy-"),
+			Name:        r.Name,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		},
+		Username:    username,
+		Description: r.Description,
+		Document:    r.Document,
+	}
+	err := p.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		if _, err := tx.Users().Get(ctx, username); err != nil {
+			return err
+		}
+		return tx.Policies().Create(ctx, pol)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, pol)
+
+	etag.Set(c, &pol.ObjectMeta)
+	core.WriteResponse(c, nil, pol)
+}
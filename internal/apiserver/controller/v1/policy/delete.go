@@ -0,0 +1,31 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Delete delete a policy by the policy identifier, the policy is detached from all of the principals too.
+func (p *PolicyController) Delete(c *gin.Context) {
+	err := p.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		pol, err := tx.Policies().Get(ctx, c.Param("name"), c.Param("policy"))
+		if err != nil {
+			return err
+		}
+		if err := tx.Policies().Delete(ctx, pol.Username, pol.Name); err != nil {
+			return err
+		}
+		audit.Before(c, pol)
+		return tx.PolicyAttachments().DeletePolicy(ctx, pol.Username, pol.Name)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
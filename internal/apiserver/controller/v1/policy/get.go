@@ -0,0 +1,20 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get a policy by the policy identifier.
+func (p *PolicyController) Get(c *gin.Context) {
+	pol, err := p.store.Policies().Get(c.Request.Context(), c.Param("name"), c.Param("policy"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	etag.Set(c, &pol.ObjectMeta)
+	core.WriteResponse(c, nil, pol)
+}
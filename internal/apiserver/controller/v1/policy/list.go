@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+const defaultListLimit = 20
+
+// List list all the policies of the user ordered by id.
+func (p *PolicyController) List(c *gin.Context) {
+	var r metav1.ListOptions
+	if err := bind.List(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.Limit == 0 {
+		r.Limit = defaultListLimit
+	}
+	opts, err := store.NewListOptions(&r, new(apiv1.Policy).Fields())
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	list, err := p.store.Policies().List(c.Request.Context(), c.Param("name"), opts)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	list.SetContinue(r.Offset, len(list.Items))
+
+	core.WriteResponse(c, nil, list)
+}
@@ -0,0 +1,32 @@
+// Package policy implements the policy handlers of siam-apiserver.
+package policy
+
+import (
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/policy"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// PolicyController creates a policy handler used to handle request for policy resource.
+type PolicyController struct {
+	store store.Factory
+}
+
+// NewPolicyController creates a policy handler.
+func NewPolicyController(store store.Factory) *PolicyController {
+	return &PolicyController{store: store}
+}
+
+// validateDocument validates the policy document against the schema, all of the violations are reported
+// as the field violations of the request.
+func validateDocument(doc *policy.Document) error {
+	err := doc.Validate()
+	if err == nil {
+		return nil
+	}
+	for _, v := range serrors.DetailsOf[*serrors.FieldViolation](err) {
+		v.Field = "document." + v.Field
+	}
+	return serrors.WrapC(err, code.ErrValidation, "invalid policy document: %s", err.Error())
+}
@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/policy"
+)
+
+// UpdatePolicyRequest defines the request body of the policy update.
+// Only the non-nil fields are updated, the document is replaced as a whole.
+type UpdatePolicyRequest struct {
+	Description *string          `json:"description" binding:"omitempty,max=255"`
+	Document    *policy.Document `json:"document"`
+
+	// Labels and Annotations replace the existing ones as a whole.
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+
+	// ResourceVersion is the version of the policy the update is based on, the update fails with a conflict
+	// if the policy has been changed since. It is optional like the If-Match header.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// Update update a policy by the policy identifier.
+func (p *PolicyController) Update(c *gin.Context) {
+	var r UpdatePolicyRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.Document != nil {
+		if err := validateDocument(r.Document); err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+	}
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	pol, err := p.store.Policies().Get(c.Request.Context(), c.Param("name"), c.Param("policy"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := etag.Check(c, &pol.ObjectMeta, r.ResourceVersion); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, pol)
+	if r.Description != nil {
+		pol.Description = *r.Description
+	}
+	if r.Document != nil {
+		pol.Document = *r.Document
+	}
+	if r.Labels != nil {
+		pol.Labels = r.Labels
+	}
+	if r.Annotations != nil {
+		pol.Annotations = r.Annotations
+	}
+
+	if err := p.store.Policies().Update(c.Request.Context(), pol); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, pol)
+
+	etag.Set(c, &pol.ObjectMeta)
+	core.WriteResponse(c, nil, pol)
+}
@@ -0,0 +1,74 @@
+package role
+
+import (
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// AssumeRoleRequest defines the request body of the role assumption.
+type AssumeRoleRequest struct {
+	// DurationSeconds is the duration of the session, it defaults to the configured session duration.
+	DurationSeconds int `json:"durationSeconds" binding:"min=0"`
+}
+
+// Assume issues a short-lived bearer token of a session of the role to the authenticated user.
+// The user must be trusted by the role directly or through one of the groups, and the authorization
+// requests of the session are made only with the policies attached to the role. The sessions never reach
+// the route, so they do not assume the other roles.
+func (r *RoleController) Assume(c *gin.Context) {
+	var req AssumeRoleRequest
+	if err := bind.JSON(c, &req); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	duration := r.opts.SessionDuration
+	if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+	if duration > r.opts.MaxSessionDuration {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrValidation, "durationSeconds must not exceed %d",
+			int(r.opts.MaxSessionDuration.Seconds())), nil)
+		return
+	}
+
+	ctx := c.Request.Context()
+	username, _ := middleware.UsernameFromContext(ctx)
+	role, err := r.store.Roles().Get(ctx, c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	trusted := slices.Contains(role.TrustedUsers, username)
+	if !trusted {
+		groups, err := r.store.GroupMembers().ListGroups(ctx, username)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+		trusted = slices.ContainsFunc(groups, func(g string) bool { return slices.Contains(role.TrustedGroups, g) })
+	}
+	if !trusted {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrRoleNotTrusted,
+			"user %q is not trusted by role %q", username, role.Name), nil)
+		return
+	}
+
+	subject := model.RoleSubject(role.Name)
+	token, expiresAt, err := r.jwt.SignSession(role.Tenant, subject, username, duration)
+	if err != nil {
+		core.WriteResponse(c, serrors.WrapC(err, code.ErrUnknown, "sign token for role %q", role.Name), nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, &apiv1.RoleSession{Subject: subject, Token: token, ExpiresAt: expiresAt})
+}
@@ -0,0 +1,50 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// CreateRoleRequest defines the request body of the role creation.
+type CreateRoleRequest struct {
+	Name        string `json:"name"        binding:"required,alphanum,max=64"`
+	Description string `json:"description" binding:"max=255"`
+	// TrustedUsers and TrustedGroups are allowed to assume the role.
+	TrustedUsers  []string `json:"trustedUsers"  binding:"max=100,unique"`
+	TrustedGroups []string `json:"trustedGroups" binding:"max=100,unique"`
+}
+
+// Create add new role to the storage.
+func (r *RoleController) Create(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := bind.JSON(c, &req); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	role := &apiv1.Role{
+		Name:          req.Name,
+		Description:   req.Description,
+		TrustedUsers:  orEmpty(req.TrustedUsers),
+		TrustedGroups: orEmpty(req.TrustedGroups),
+	}
+	err := r.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		if err := checkTrusted(ctx, tx, role.TrustedUsers, role.TrustedGroups); err != nil {
+			return err
+		}
+		return tx.Roles().Create(ctx, role)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, role)
+
+	core.WriteResponse(c, nil, role)
+}
@@ -0,0 +1,35 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// Delete delete a role by the role identifier, the attached policies of the role are deleted too,
+// so the sessions of the role are left without any permission until they expire.
+func (r *RoleController) Delete(c *gin.Context) {
+	name := c.Param("name")
+	err := r.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		role, err := tx.Roles().Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := tx.Roles().Delete(ctx, name); err != nil {
+			return err
+		}
+		audit.Before(c, role)
+		return tx.PolicyAttachments().DeleteCollection(ctx, model.Principal{Kind: apiv1.PrincipalRole, Name: name})
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,18 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get a role by the role identifier.
+func (r *RoleController) Get(c *gin.Context) {
+	role, err := r.store.Roles().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, role)
+}
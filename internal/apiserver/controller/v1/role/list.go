@@ -0,0 +1,39 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+const defaultListLimit = 20
+
+// List list the roles in the storage ordered by id.
+func (r *RoleController) List(c *gin.Context) {
+	var req metav1.ListOptions
+	if err := bind.List(c, &req); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultListLimit
+	}
+	opts, err := store.NewListOptions(&req, new(apiv1.Role).Fields())
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	list, err := r.store.Roles().List(c.Request.Context(), opts)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	list.SetContinue(req.Offset, len(list.Items))
+
+	core.WriteResponse(c, nil, list)
+}
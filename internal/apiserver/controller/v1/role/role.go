@@ -0,0 +1,45 @@
+// Package role implements the role handlers of siam-apiserver, including the role assumption.
+package role
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/pkg/auth"
+)
+
+// RoleController creates a role handler used to handle request for role resource.
+type RoleController struct {
+	store store.Factory
+	jwt   *auth.JWT
+	opts  *options.RoleOptions
+}
+
+// NewRoleController creates a role handler, the tokens of the role sessions are issued by jwt.
+func NewRoleController(store store.Factory, jwt *auth.JWT, opts *options.RoleOptions) *RoleController {
+	return &RoleController{store: store, jwt: jwt, opts: opts}
+}
+
+// checkTrusted checks the trusted users and groups of the role exist.
+func checkTrusted(ctx context.Context, tx store.Factory, users, groups []string) error {
+	for _, name := range users {
+		if _, err := tx.Users().Get(ctx, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range groups {
+		if _, err := tx.Groups().Get(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orEmpty returns an empty slice instead of nil, so that the trusted principals are never null.
+func orEmpty(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
@@ -0,0 +1,58 @@
+package role
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// UpdateRoleRequest defines the request body of the role update.
+// Only the non-nil fields are updated, the trusted principals are replaced as a whole.
+type UpdateRoleRequest struct {
+	Description   *string   `json:"description"   binding:"omitempty,max=255"`
+	TrustedUsers  *[]string `json:"trustedUsers"  binding:"omitempty,max=100,unique"`
+	TrustedGroups *[]string `json:"trustedGroups" binding:"omitempty,max=100,unique"`
+}
+
+// Update update a role by the role identifier.
+func (r *RoleController) Update(c *gin.Context) {
+	var req UpdateRoleRequest
+	if err := bind.JSON(c, &req); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	var role *apiv1.Role
+	err := r.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		var err error
+		if role, err = tx.Roles().Get(ctx, c.Param("name")); err != nil {
+			return err
+		}
+		audit.Before(c, role)
+		if req.Description != nil {
+			role.Description = *req.Description
+		}
+		if req.TrustedUsers != nil {
+			role.TrustedUsers = orEmpty(*req.TrustedUsers)
+		}
+		if req.TrustedGroups != nil {
+			role.TrustedGroups = orEmpty(*req.TrustedGroups)
+		}
+		if err := checkTrusted(ctx, tx, role.TrustedUsers, role.TrustedGroups); err != nil {
+			return err
+		}
+		return tx.Roles().Update(ctx, role)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, role)
+
+	core.WriteResponse(c, nil, role)
+}
@@ -0,0 +1,94 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// CreateSecretRequest defines the request body of the secret creation.
+type CreateSecretRequest struct {
+	// Name is optional, the secret is identified by its access key.
+	Name        string `json:"name"        binding:"omitempty,alphanum,max=64"`
+	Description string `json:"description" binding:"max=255"`
+	// ExpiresAt is optional, the secret never expires if it is not set.
+	ExpiresAt *time.Time `json:"expiresAt"`
+
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Create add new secret key pair to the storage.
+// The plain secret key is returned only once in the response.
+func (s *SecretController) Create(c *gin.Context) {
+	var r CreateSecretRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		core.WriteResponse(c, serrors.WithCode(code.ErrValidation, "expiresAt must be in the future"), nil)
+		return
+	}
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	username := c.Param("name")
+	secret := &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			InstanceID:  metav1.NewInstanceID("secret-"),
+			Name:        r.Name,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		},
+		Username:    username,
+		AccessKey:   auth.NewAccessKey(),
+		Description: r.Description,
+		ExpiresAt:   r.ExpiresAt,
+	}
+	resp, err := s.generateKey(secret)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	err = s.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		// lock the owner to serialize the quota check of the concurrent creations
+		if _, err := tx.Users().Lock(ctx, username); err != nil {
+			return err
+		}
+
+		list, err := tx.Secrets().List(ctx, username, store.ListOptions{Limit: 1})
+		if err != nil {
+			return err
+		}
+		if list.TotalCount >= int64(s.opts.MaxCount) {
+			return serrors.WithCodef(code.ErrReachMaxCount, "user %q already owns %d secrets", username, list.TotalCount)
+		}
+
+		return tx.Secrets().Create(ctx, secret)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	// the plain secret key must not be recorded
+	audit.After(c, secret)
+
+	etag.Set(c, &resp.ObjectMeta)
+	core.WriteResponse(c, nil, resp)
+}
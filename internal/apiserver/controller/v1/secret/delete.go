@@ -0,0 +1,24 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Delete delete a secret by the access key.
+func (s *SecretController) Delete(c *gin.Context) {
+	secret, err := s.store.Secrets().Get(c.Request.Context(), c.Param("name"), c.Param("accessKey"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := s.store.Secrets().Delete(c.Request.Context(), secret.Username, secret.AccessKey); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, secret)
+
+	core.WriteResponse(c, nil, nil)
+}
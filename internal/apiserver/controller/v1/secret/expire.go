@@ -0,0 +1,32 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Expire expires a secret immediately by the access key, the secret is kept for auditing.
+func (s *SecretController) Expire(c *gin.Context) {
+	secret, err := s.store.Secrets().Get(c.Request.Context(), c.Param("name"), c.Param("accessKey"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, secret)
+
+	now := time.Now()
+	if !secret.Expired(now) {
+		secret.ExpiresAt = &now
+		if err := s.store.Secrets().Update(c.Request.Context(), secret); err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+	}
+	audit.After(c, secret)
+
+	core.WriteResponse(c, nil, secret)
+}
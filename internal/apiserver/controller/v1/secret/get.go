@@ -0,0 +1,20 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get a secret by the access key.
+func (s *SecretController) Get(c *gin.Context) {
+	secret, err := s.store.Secrets().Get(c.Request.Context(), c.Param("name"), c.Param("accessKey"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	etag.Set(c, &secret.ObjectMeta)
+	core.WriteResponse(c, nil, secret)
+}
@@ -0,0 +1,32 @@
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Rotate replaces the secret key of a secret and keeps its access key.
+// The new plain secret key is returned only once in the response, the old one stops working immediately.
+func (s *SecretController) Rotate(c *gin.Context) {
+	secret, err := s.store.Secrets().Get(c.Request.Context(), c.Param("name"), c.Param("accessKey"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, secret)
+
+	resp, err := s.generateKey(secret)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := s.store.Secrets().Update(c.Request.Context(), secret); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, secret)
+
+	core.WriteResponse(c, nil, resp)
+}
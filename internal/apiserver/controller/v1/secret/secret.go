@@ -0,0 +1,41 @@
+// Package secret implements the secret handlers of siam-apiserver.
+package secret
+
+import (
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// SecretController creates a secret handler used to handle request for secret resource.
+type SecretController struct {
+	store store.Factory
+	opts  *options.SecretOptions
+	// keys seals the signing keys of the secrets.
+	keys *auth.KeyCipher
+}
+
+// NewSecretController creates a secret handler.
+func NewSecretController(store store.Factory, opts *options.SecretOptions, keys *auth.KeyCipher) *SecretController {
+	return &SecretController{store: store, opts: opts, keys: keys}
+}
+
+// generateKey fills the sealed signing key of a new secret key into the secret and returns it with
+// the plain secret key. The sealed key is bound to the access key, which must be set before.
+func (s *SecretController) generateKey(secret *apiv1.Secret) (*apiv1.SecretWithKey, error) {
+	secretKey, err := auth.NewSecretKey()
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrEncrypt, "generate secret key")
+	}
+	sealed, err := s.keys.Seal(sign.SigningKey(secretKey), []byte(secret.AccessKey))
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrEncrypt, "seal signing key")
+	}
+	secret.SigningKey = sealed
+	secret.SecretKeyHash = ""
+	return &apiv1.SecretWithKey{Secret: secret, SecretKey: secretKey}, nil
+}
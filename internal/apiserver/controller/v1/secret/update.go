@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// UpdateSecretRequest defines the request body of the secret update.
+// Only the non-nil fields are updated.
+type UpdateSecretRequest struct {
+	Description *string    `json:"description" binding:"omitempty,max=255"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+
+	// Labels and Annotations replace the existing ones as a whole.
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+
+	// ResourceVersion is the version of the secret the update is based on, the update fails with a conflict
+	// if the secret has been changed since. It is optional like the If-Match header.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// Update update the description, the expiration or the metadata of a secret by the access key.
+func (s *SecretController) Update(c *gin.Context) {
+	var r UpdateSecretRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		core.WriteResponse(c, serrors.WithCode(code.ErrValidation, "expiresAt must be in the future"), nil)
+		return
+	}
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	secret, err := s.store.Secrets().Get(c.Request.Context(), c.Param("name"), c.Param("accessKey"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := etag.Check(c, &secret.ObjectMeta, r.ResourceVersion); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, secret)
+	if r.Description != nil {
+		secret.Description = *r.Description
+	}
+	if r.ExpiresAt != nil {
+		secret.ExpiresAt = r.ExpiresAt
+	}
+	if r.Labels != nil {
+		secret.Labels = r.Labels
+	}
+	if r.Annotations != nil {
+		secret.Annotations = r.Annotations
+	}
+
+	if err := s.store.Secrets().Update(c.Request.Context(), secret); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, secret)
+
+	etag.Set(c, &secret.ObjectMeta)
+	core.WriteResponse(c, nil, secret)
+}
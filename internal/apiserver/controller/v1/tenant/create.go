@@ -0,0 +1,42 @@
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// CreateTenantRequest defines the request body of the tenant creation.
+type CreateTenantRequest struct {
+	Name        string `json:"name"        binding:"required,alphanum,max=64"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// Create add new tenant to the storage.
+func (t *TenantController) Create(c *gin.Context) {
+	var r CreateTenantRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	// the default tenant is never stored
+	if r.Name == model.DefaultTenant {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrTenantAlreadyExists, "tenant %q already exists", r.Name), nil)
+		return
+	}
+
+	tenant := &apiv1.Tenant{Name: r.Name, Description: r.Description}
+	if err := t.store.Tenants().Create(c.Request.Context(), tenant); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, tenant)
+
+	core.WriteResponse(c, nil, tenant)
+}
@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// Delete delete a tenant by the tenant identifier. Only the empty tenants are deleted,
+// the users, the groups and the roles of the tenant must be deleted first.
+func (t *TenantController) Delete(c *gin.Context) {
+	name := c.Param("tenant")
+	err := t.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		tenant, err := tx.Tenants().Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := checkEmpty(store.WithTenant(ctx, name), tx); err != nil {
+			return err
+		}
+		if err := tx.Tenants().Delete(ctx, name); err != nil {
+			return err
+		}
+		audit.Before(c, tenant)
+		return nil
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// checkEmpty returns ErrTenantNotEmpty if the tenant the stores are scoped to has any users, groups or roles.
+func checkEmpty(ctx context.Context, tx store.Factory) error {
+	tenant := store.TenantFromContext(ctx)
+	first := store.ListOptions{Limit: 1}
+	users, err := tx.Users().List(ctx, first)
+	if err != nil {
+		return err
+	}
+	groups, err := tx.Groups().List(ctx, first)
+	if err != nil {
+		return err
+	}
+	roles, err := tx.Roles().List(ctx, first)
+	if err != nil {
+		return err
+	}
+	if users.TotalCount+groups.TotalCount+roles.TotalCount > 0 {
+		return serrors.WithCodef(code.ErrTenantNotEmpty, "tenant %q still has %d users, %d groups and %d roles",
+			tenant, users.TotalCount, groups.TotalCount, roles.TotalCount)
+	}
+	return nil
+}
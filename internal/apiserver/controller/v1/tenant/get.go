@@ -0,0 +1,18 @@
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get a tenant by the tenant identifier.
+func (t *TenantController) Get(c *gin.Context) {
+	tenant, err := t.store.Tenants().Get(c.Request.Context(), c.Param("tenant"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, tenant)
+}
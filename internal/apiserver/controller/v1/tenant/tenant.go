@@ -0,0 +1,16 @@
+// Package tenant implements the tenant handlers of siam-apiserver, they are allowed to the system admins only.
+package tenant
+
+import (
+	"github.com/strayca7/siam/internal/apiserver/store"
+)
+
+// TenantController creates a tenant handler used to handle request for tenant resource.
+type TenantController struct {
+	store store.Factory
+}
+
+// NewTenantController creates a tenant handler.
+func NewTenantController(store store.Factory) *TenantController {
+	return &TenantController{store: store}
+}
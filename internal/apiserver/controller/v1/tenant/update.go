@@ -0,0 +1,42 @@
+package tenant
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// UpdateTenantRequest defines the request body of the tenant update.
+// Only the non-nil fields are updated.
+type UpdateTenantRequest struct {
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// Update update a tenant by the tenant identifier.
+func (t *TenantController) Update(c *gin.Context) {
+	var r UpdateTenantRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	tenant, err := t.store.Tenants().Get(c.Request.Context(), c.Param("tenant"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.Before(c, tenant)
+	if r.Description != nil {
+		tenant.Description = *r.Description
+	}
+
+	if err := t.store.Tenants().Update(c.Request.Context(), tenant); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, tenant)
+
+	core.WriteResponse(c, nil, tenant)
+}
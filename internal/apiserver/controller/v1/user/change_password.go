@@ -0,0 +1,54 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// ChangePasswordRequest defines the request body of the password change.
+type ChangePasswordRequest struct {
+	// Old password.
+	OldPassword string `json:"oldPassword" binding:"required"`
+
+	// New password.
+	NewPassword string `json:"newPassword" binding:"required,min=8,max=64"`
+}
+
+// ChangePassword change the user's password by the user identifier.
+func (u *UserController) ChangePassword(c *gin.Context) {
+	var r ChangePasswordRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	user, err := u.store.Users().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if err := auth.Compare(user.Password, r.OldPassword); err != nil {
+		core.WriteResponse(c, serrors.WithCode(code.ErrPasswordIncorrect, "old password is incorrect"), nil)
+		return
+	}
+
+	hashed, err := auth.Encrypt(r.NewPassword)
+	if err != nil {
+		core.WriteResponse(c, serrors.WrapC(err, code.ErrEncrypt, "encrypt password"), nil)
+		return
+	}
+	user.Password = hashed
+
+	if err := u.store.Users().Update(c.Request.Context(), user); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// CreateUserRequest defines the request body of the user registration, the registered users are never admins.
+type CreateUserRequest struct {
+	Name     string `json:"name"     binding:"required,alphanum,max=64"`
+	Nickname string `json:"nickname" binding:"max=64"`
+	Password string `json:"password" binding:"required,min=8,max=64"`
+	Email    string `json:"email"    binding:"omitempty,email,max=255"`
+	Phone    string `json:"phone"    binding:"omitempty,e164"`
+
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// AdminCreateUserRequest defines the request body of the user creation by the admins of the tenant,
+// who may create the other admins.
+type AdminCreateUserRequest struct {
+	CreateUserRequest
+	IsAdmin bool `json:"isAdmin"`
+}
+
+// Create registers a new user, which is not an admin.
+func (u *UserController) Create(c *gin.Context) {
+	var r CreateUserRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	u.create(c, &r, false)
+}
+
+// AdminCreate adds a new user by an admin of the tenant, the route must be limited to the admins.
+func (u *UserController) AdminCreate(c *gin.Context) {
+	var r AdminCreateUserRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	u.create(c, &r.CreateUserRequest, r.IsAdmin)
+}
+
+func (u *UserController) create(c *gin.Context, r *CreateUserRequest, isAdmin bool) {
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	hashed, err := auth.Encrypt(r.Password)
+	if err != nil {
+		core.WriteResponse(c, serrors.WrapC(err, code.ErrEncrypt, "encrypt password"), nil)
+		return
+	}
+
+	user := &apiv1.User{
+		ObjectMeta: metav1.ObjectMeta{
+			InstanceID:  metav1.NewInstanceID("user-"),
+			Name:        r.Name,
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+		},
+		Nickname: r.Nickname,
+		Password: hashed,
+		Email:    r.Email,
+		Phone:    r.Phone,
+		IsAdmin:  isAdmin,
+	}
+	if err := u.store.Users().Create(c.Request.Context(), user); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, user)
+
+	etag.Set(c, &user.ObjectMeta)
+	core.WriteResponse(c, nil, user)
+}
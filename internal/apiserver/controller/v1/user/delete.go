@@ -0,0 +1,52 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/pkg/core"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// Delete delete an user by the user identifier, the secrets and policies owned by the user are deleted too,
+// and so are the group memberships and the policy attachments of the user and of its policies.
+// The user is no longer trusted by any role.
+func (u *UserController) Delete(c *gin.Context) {
+	name := c.Param("name")
+	err := u.store.Tx(c.Request.Context(), func(tx store.Factory) error {
+		ctx := c.Request.Context()
+		user, err := tx.Users().Get(ctx, name)
+		if err != nil {
+			return err
+		}
+		if err := tx.Users().Delete(ctx, name); err != nil {
+			return err
+		}
+		audit.Before(c, user)
+		if err := tx.Secrets().DeleteCollection(ctx, name); err != nil {
+			return err
+		}
+		if err := tx.Policies().DeleteCollection(ctx, name); err != nil {
+			return err
+		}
+		if err := tx.PolicyAttachments().DeletePolicy(ctx, name, ""); err != nil {
+			return err
+		}
+		if err := tx.GroupMembers().DeleteUser(ctx, name); err != nil {
+			return err
+		}
+		principal := model.Principal{Kind: apiv1.PrincipalUser, Name: name}
+		if err := tx.PolicyAttachments().DeleteCollection(ctx, principal); err != nil {
+			return err
+		}
+		return tx.Roles().Untrust(ctx, principal)
+	})
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,20 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/pkg/core"
+)
+
+// Get get an user by the user identifier.
+func (u *UserController) Get(c *gin.Context) {
+	user, err := u.store.Users().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	etag.Set(c, &user.ObjectMeta)
+	core.WriteResponse(c, nil, user)
+}
@@ -0,0 +1,88 @@
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/etag"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// UpdateUserRequest defines the request body of the user update.
+// Only the non-nil fields are updated.
+type UpdateUserRequest struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=64"`
+	Email    *string `json:"email"    binding:"omitempty,email,max=255"`
+	Phone    *string `json:"phone"    binding:"omitempty,e164"`
+	// IsAdmin is changed by the admins of the tenant only.
+	IsAdmin *bool `json:"isAdmin"`
+
+	// Labels and Annotations replace the existing ones as a whole.
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+
+	// ResourceVersion is the version of the user the update is based on, the update fails with a conflict
+	// if the user has been changed since. It is optional like the If-Match header.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// Update update a user info by the user identifier.
+func (u *UserController) Update(c *gin.Context) {
+	var r UpdateUserRequest
+	if err := bind.JSON(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := bind.Meta(r.Labels, r.Annotations); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	user, err := u.store.Users().Get(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if err := etag.Check(c, &user.ObjectMeta, r.ResourceVersion); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.IsAdmin != nil && *r.IsAdmin != user.IsAdmin && !middleware.AdminFromContext(c.Request.Context()) {
+		core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied,
+			"only the admins are allowed to change the admin flag of user %q", user.Name), nil)
+		return
+	}
+	audit.Before(c, user)
+
+	if r.Nickname != nil {
+		user.Nickname = *r.Nickname
+	}
+	if r.Email != nil {
+		user.Email = *r.Email
+	}
+	if r.Phone != nil {
+		user.Phone = *r.Phone
+	}
+	if r.IsAdmin != nil {
+		user.IsAdmin = *r.IsAdmin
+	}
+	if r.Labels != nil {
+		user.Labels = r.Labels
+	}
+	if r.Annotations != nil {
+		user.Annotations = r.Annotations
+	}
+
+	if err := u.store.Users().Update(c.Request.Context(), user); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	audit.After(c, user)
+
+	etag.Set(c, &user.ObjectMeta)
+	core.WriteResponse(c, nil, user)
+}
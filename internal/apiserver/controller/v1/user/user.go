@@ -0,0 +1,16 @@
+// Package user implements the user handlers of siam-apiserver.
+package user
+
+import (
+	"github.com/strayca7/siam/internal/apiserver/store"
+)
+
+// UserController creates a user handler used to handle request for user resource.
+type UserController struct {
+	store store.Factory
+}
+
+// NewUserController creates a user handler.
+func NewUserController(store store.Factory) *UserController {
+	return &UserController{store: store}
+}
@@ -0,0 +1,277 @@
+// Package watch implements the watch handlers of siam-apiserver, which stream the changes of the policies
+// and the secrets of a tenant as the Server-Sent Events.
+//
+// Every event is a metav1.WatchEvent in the data field, its type is also the event field and its resource
+// version is the id field, so the clients like EventSource resume the watch with the Last-Event-ID header.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/bind"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/core"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+	"github.com/strayca7/siam/staging/src/apimachinery/selector"
+)
+
+const (
+	// bookmarkInterval is the interval of the Bookmark events.
+	bookmarkInterval = 30 * time.Second
+	// batchSize is the maximal number of the events read from the store at once.
+	batchSize = 100
+)
+
+// WatchController creates a watch handler used to stream the changes of the watchable resources.
+type WatchController struct {
+	store store.Factory
+	// stopping is closed when the server shuts down, which ends all of the watches.
+	stopping <-chan struct{}
+}
+
+// NewWatchController creates a watch handler, the watches end when stopping is closed.
+func NewWatchController(store store.Factory, stopping <-chan struct{}) *WatchController {
+	return &WatchController{store: store, stopping: stopping}
+}
+
+// kind is a watchable kind of the objects.
+type kind struct {
+	name string
+	// fields are the fields of the objects which can be selected.
+	fields selector.Set
+	// list lists the objects selected by the options and returns the resource version of the list.
+	list func(ctx context.Context, opts store.ListOptions) ([]any, uint64, error)
+	// decode decodes the object of an event and returns its labels and fields.
+	decode func(data json.RawMessage) (map[string]string, selector.Set, error)
+}
+
+// Policies watches the policies of all of the users in the tenant.
+func (w *WatchController) Policies(c *gin.Context) {
+	w.watch(c, kind{
+		name:   model.KindPolicy,
+		fields: new(apiv1.Policy).Fields(),
+		list: func(ctx context.Context, opts store.ListOptions) ([]any, uint64, error) {
+			list, err := w.store.Policies().List(ctx, "", opts)
+			if err != nil {
+				return nil, 0, err
+			}
+			objects := make([]any, len(list.Items))
+			for i, pol := range list.Items {
+				objects[i] = pol
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		decode: func(data json.RawMessage) (map[string]string, selector.Set, error) {
+			var pol apiv1.Policy
+			if err := json.Unmarshal(data, &pol); err != nil {
+				return nil, nil, err
+			}
+			return pol.Labels, pol.Fields(), nil
+		},
+	})
+}
+
+// Secrets watches the secrets of all of the users in the tenant.
+func (w *WatchController) Secrets(c *gin.Context) {
+	w.watch(c, kind{
+		name:   model.KindSecret,
+		fields: new(apiv1.Secret).Fields(),
+		list: func(ctx context.Context, opts store.ListOptions) ([]any, uint64, error) {
+			list, err := w.store.Secrets().List(ctx, "", opts)
+			if err != nil {
+				return nil, 0, err
+			}
+			objects := make([]any, len(list.Items))
+			for i, secret := range list.Items {
+				objects[i] = secret
+			}
+			return objects, list.ResourceVersion, nil
+		},
+		decode: func(data json.RawMessage) (map[string]string, selector.Set, error) {
+			var secret apiv1.Secret
+			if err := json.Unmarshal(data, &secret); err != nil {
+				return nil, nil, err
+			}
+			return secret.Labels, secret.Fields(), nil
+		},
+	})
+}
+
+// watch streams the events of the kind. The errors before the stream starts are the normal error responses,
+// the later ones end the stream with an Error event.
+func (w *WatchController) watch(c *gin.Context, k kind) {
+	var r metav1.WatchOptions
+	if err := bind.Query(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+	if r.ResourceVersion == "" {
+		r.ResourceVersion = c.GetHeader("Last-Event-ID")
+	}
+	selectors := metav1.ListOptions{LabelSelector: r.LabelSelector, FieldSelector: r.FieldSelector}
+	opts, err := store.NewListOptions(&selectors, k.fields)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// the events are read from the primary, the replicas may not have them yet when notified
+	ctx := pkgdatabase.WithPrimary(c.Request.Context())
+	// subscribe before the first read, so that no notification is missed after it
+	notified, unsubscribe := w.store.WatchEvents().Subscribe()
+	defer unsubscribe()
+
+	var (
+		initial []any
+		version uint64
+	)
+	if r.ResourceVersion == "" {
+		if initial, version, err = k.list(ctx, opts); err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+	} else {
+		if version, err = strconv.ParseUint(r.ResourceVersion, 10, 64); err != nil {
+			err = serrors.WithCodef(code.ErrValidation, "resource version %q is invalid", r.ResourceVersion)
+			core.WriteResponse(c, err, nil)
+			return
+		}
+		// fail with the status 410 instead of the Error event if the version is too old
+		if _, err := w.store.WatchEvents().List(ctx, k.name, version, 1); err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	s := &stream{c: c}
+	for _, obj := range initial {
+		s.send(metav1.Added, 0, obj)
+	}
+	if r.ResourceVersion == "" {
+		// tells the end of the existing objects
+		s.bookmark(version)
+	}
+
+	ticker := time.NewTicker(bookmarkInterval)
+	defer ticker.Stop()
+	// the changes since the version are sent before waiting for the new ones
+	version, err = w.sync(ctx, s, k, opts, version)
+	for err == nil && s.err == nil {
+		s.flush()
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopping:
+			return
+		case _, ok := <-notified:
+			if !ok {
+				return
+			}
+			version, err = w.sync(ctx, s, k, opts, version)
+		case <-ticker.C:
+			if version, err = w.sync(ctx, s, k, opts, version); err == nil {
+				s.bookmark(version)
+			}
+		}
+	}
+	if err != nil {
+		logger.L().Error("watch failed", zap.String("kind", k.name), zap.String("error", fmt.Sprintf("%#+v", err)))
+		s.fail(err)
+	}
+}
+
+// sync sends the events of the selected objects after the version, and returns the version the watch has seen.
+func (w *WatchController) sync(ctx context.Context, s *stream, k kind, opts store.ListOptions, version uint64,
+) (uint64, error) {
+	// all of the changes up to the latest version are visible once it is read
+	latest, err := w.store.WatchEvents().Latest(ctx)
+	if err != nil {
+		return version, err
+	}
+	for {
+		events, err := w.store.WatchEvents().List(ctx, k.name, version, batchSize)
+		if err != nil {
+			return version, err
+		}
+		for _, event := range events {
+			labels, fields, err := k.decode(event.Object)
+			if err != nil {
+				return version, serrors.WrapC(err, code.ErrUnknown, "decode watch event %d", event.ResourceVersion)
+			}
+			if opts.Matches(labels, fields) {
+				s.send(event.Type, event.ResourceVersion, event.Object)
+			}
+			version = event.ResourceVersion
+		}
+		if len(events) < batchSize || s.err != nil {
+			return max(version, latest), nil
+		}
+	}
+}
+
+// stream writes the Server-Sent Events, it stops writing after the first failure.
+type stream struct {
+	c   *gin.Context
+	err error
+}
+
+// send sends the event of the object, the event has no id if version is 0.
+func (s *stream) send(typ metav1.EventType, version uint64, obj any) {
+	if s.err != nil {
+		return
+	}
+	object, ok := obj.(json.RawMessage)
+	if !ok {
+		if object, s.err = json.Marshal(obj); s.err != nil {
+			return
+		}
+	}
+	data, err := json.Marshal(metav1.WatchEvent{Type: typ, Object: object})
+	if err != nil {
+		s.err = err
+		return
+	}
+	if version > 0 {
+		if _, s.err = fmt.Fprintf(s.c.Writer, "id: %d\n", version); s.err != nil {
+			return
+		}
+	}
+	_, s.err = fmt.Fprintf(s.c.Writer, "event: %s\ndata: %s\n\n", typ, data)
+}
+
+// bookmark sends the Bookmark event of the version.
+func (s *stream) bookmark(version uint64) {
+	s.send(metav1.Bookmark, version, map[string]string{"resourceVersion": strconv.FormatUint(version, 10)})
+}
+
+// fail sends the Error event of the error.
+func (s *stream) fail(err error) {
+	_, resp := core.ErrorResponse(s.c, err)
+	s.send(metav1.Error, 0, resp)
+	s.flush()
+}
+
+func (s *stream) flush() {
+	if s.err == nil {
+		s.c.Writer.Flush()
+	}
+}
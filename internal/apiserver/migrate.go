@@ -0,0 +1,123 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/migrations"
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/pkg/app"
+	"github.com/strayca7/siam/pkg/database/migrate"
+	"github.com/strayca7/siam/pkg/logger"
+)
+
+// newMigrator creates a migrator of the database with the embedded migrations of the driver.
+func newMigrator(db *gorm.DB, driver string) (*migrate.Migrator, error) {
+	sqldb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	dialect, err := migrate.LookupDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := migrations.Load(driver)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqldb, dialect, ms), nil
+}
+
+// newMigrateCommand creates the migrate command which manages the database schema.
+func newMigrateCommand() *app.Command {
+	cmd := app.NewCommand("migrate", "Manage the database schema migrations.")
+	cmd.AddCommand(
+		newMigrateSubCommand("up", "Apply all of the pending migrations.", 0,
+			func(ctx context.Context, m *migrate.Migrator, _ []string) error {
+				return m.Up(ctx)
+			}),
+		newMigrateSubCommand("down [STEPS]", "Roll back the last STEPS migrations, 1 by default.", 1,
+			func(ctx context.Context, m *migrate.Migrator, args []string) error {
+				steps := 1
+				if len(args) > 0 {
+					n, err := strconv.Atoi(args[0])
+					if err != nil || n < 1 {
+						return fmt.Errorf("steps %q must be a positive integer", args[0])
+					}
+					steps = n
+				}
+				return m.Down(ctx, steps)
+			}),
+		newMigrateSubCommand("to VERSION", "Migrate up or down to VERSION, 0 rolls back all of the migrations.", 1,
+			func(ctx context.Context, m *migrate.Migrator, args []string) error {
+				if len(args) != 1 {
+					return fmt.Errorf("exactly one VERSION is required")
+				}
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("version %q must be a non-negative integer", args[0])
+				}
+				return m.To(ctx, version)
+			}),
+		newMigrateSubCommand("status", "Show the status of the migrations.", 0, printMigrateStatus),
+	)
+	return cmd
+}
+
+// newMigrateSubCommand creates a sub command of migrate which accepts at most maxArgs arguments.
+func newMigrateSubCommand(usage, desc string, maxArgs int,
+	run func(ctx context.Context, m *migrate.Migrator, args []string) error,
+) *app.Command {
+	opts := options.NewMigrateOptions()
+	return app.NewCommand(usage, desc,
+		app.WithCommandOptions(opts),
+		app.WithCommandConfig(),
+		app.WithCommandRunFunc(func(args []string) error {
+			if len(args) > maxArgs {
+				return fmt.Errorf("too many arguments %q", args)
+			}
+			// the migrations only run on the primary
+			opts.Database.Replicas = nil
+			db, err := opts.Database.NewDatabaseCli(logger.L())
+			if err != nil {
+				return err
+			}
+			if sqldb, err := db.DB(); err == nil {
+				defer sqldb.Close()
+			}
+
+			m, err := newMigrator(db, opts.Database.Driver)
+			if err != nil {
+				return err
+			}
+			return run(context.Background(), m, args)
+		}),
+	)
+}
+
+func printMigrateStatus(ctx context.Context, m *migrate.Migrator, _ []string) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		status, appliedAt := "pending", "-"
+		if s.Applied {
+			status, appliedAt = "applied", s.AppliedAt.Local().Format(time.DateTime)
+			if s.Modified {
+				status = "modified"
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,30 @@
+// Package migrations embeds the SQL migrations of the siam-apiserver database, see package migrate.
+// The migrations of every database driver live in the directory named after the driver,
+// and they must keep the same versions and names.
+//
+// The first migrations use `IF NOT EXISTS`, so that the databases created by the gorm auto migration
+// of the earlier versions are adopted as they are.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/strayca7/siam/pkg/database/migrate"
+)
+
+//go:embed postgres mysql sqlite
+var fsys embed.FS
+
+// Load returns the migrations of the database driver sorted by version.
+func Load(driver string) ([]*migrate.Migration, error) {
+	if _, err := fs.Stat(fsys, driver); err != nil {
+		return nil, fmt.Errorf("no migrations of database driver %q", driver)
+	}
+	sub, err := fs.Sub(fsys, driver)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.Load(sub)
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name       VARCHAR(64)  NOT NULL,
+    nickname   VARCHAR(64),
+    password   VARCHAR(255) NOT NULL,
+    email      VARCHAR(255),
+    phone      VARCHAR(32),
+    is_admin   BOOLEAN      NOT NULL DEFAULT false,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    UNIQUE INDEX idx_users_name (name)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS secrets (
+    id              BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    username        VARCHAR(64) NOT NULL,
+    access_key      VARCHAR(64) NOT NULL,
+    secret_key_hash VARCHAR(64) NOT NULL,
+    description     VARCHAR(255),
+    expires_at      DATETIME(3),
+    created_at      DATETIME(3),
+    updated_at      DATETIME(3),
+    INDEX idx_secrets_username (username),
+    UNIQUE INDEX idx_secrets_access_key (access_key)
+) DEFAULT CHARSET = utf8mb4;
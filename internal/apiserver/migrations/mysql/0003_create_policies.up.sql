@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS policies (
+    id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    username    VARCHAR(64) NOT NULL,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    document    JSON        NOT NULL,
+    created_at  DATETIME(3),
+    updated_at  DATETIME(3),
+    UNIQUE INDEX idx_policies_username_name (username, name)
+) DEFAULT CHARSET = utf8mb4;
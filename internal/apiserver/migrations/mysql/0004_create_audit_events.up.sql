@@ -0,0 +1,15 @@
+CREATE TABLE audit_events (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    actor      VARCHAR(64),
+    action     VARCHAR(64)  NOT NULL,
+    resource   VARCHAR(255) NOT NULL,
+    status     INT          NOT NULL,
+    diff       JSON,
+    decisions  JSON,
+    trace_id   VARCHAR(32),
+    client_ip  VARCHAR(64),
+    created_at DATETIME(3),
+    INDEX idx_audit_events_actor (actor),
+    INDEX idx_audit_events_resource (resource),
+    INDEX idx_audit_events_created_at (created_at)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,8 @@
+CREATE TABLE `groups` (
+    id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    created_at  DATETIME(3),
+    updated_at  DATETIME(3),
+    UNIQUE INDEX idx_groups_name (name)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,8 @@
+CREATE TABLE group_members (
+    id         BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    group_name VARCHAR(64) NOT NULL,
+    username   VARCHAR(64) NOT NULL,
+    created_at DATETIME(3),
+    UNIQUE INDEX idx_group_members_group_username (group_name, username),
+    INDEX idx_group_members_username (username)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,10 @@
+CREATE TABLE roles (
+    id             BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name           VARCHAR(64) NOT NULL,
+    description    VARCHAR(255),
+    trusted_users  JSON        NOT NULL,
+    trusted_groups JSON        NOT NULL,
+    created_at     DATETIME(3),
+    updated_at     DATETIME(3),
+    UNIQUE INDEX idx_roles_name (name)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,10 @@
+CREATE TABLE policy_attachments (
+    id             BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    principal_kind VARCHAR(16) NOT NULL,
+    principal_name VARCHAR(64) NOT NULL,
+    policy_owner   VARCHAR(64) NOT NULL,
+    policy_name    VARCHAR(64) NOT NULL,
+    created_at     DATETIME(3),
+    UNIQUE INDEX idx_policy_attachments (principal_kind, principal_name, policy_owner, policy_name),
+    INDEX idx_policy_attachments_policy (policy_owner, policy_name)
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP INDEX idx_users_tenant_name,
+    ADD UNIQUE INDEX idx_users_name (name),
+    DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_users_name,
+    ADD UNIQUE INDEX idx_users_tenant_name (tenant, name);
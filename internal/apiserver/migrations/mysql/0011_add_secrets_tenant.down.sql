@@ -0,0 +1,4 @@
+ALTER TABLE secrets
+    DROP INDEX idx_secrets_tenant_username,
+    ADD INDEX idx_secrets_username (username),
+    DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE secrets
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_secrets_username,
+    ADD INDEX idx_secrets_tenant_username (tenant, username);
@@ -0,0 +1,4 @@
+ALTER TABLE policies
+    DROP INDEX idx_policies_tenant_username_name,
+    ADD UNIQUE INDEX idx_policies_username_name (username, name),
+    DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE policies
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_policies_username_name,
+    ADD UNIQUE INDEX idx_policies_tenant_username_name (tenant, username, name);
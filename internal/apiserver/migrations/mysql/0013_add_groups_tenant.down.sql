@@ -0,0 +1,4 @@
+ALTER TABLE `groups`
+    DROP INDEX idx_groups_tenant_name,
+    ADD UNIQUE INDEX idx_groups_name (name),
+    DROP COLUMN tenant;
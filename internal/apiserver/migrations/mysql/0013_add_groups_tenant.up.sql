@@ -0,0 +1,4 @@
+ALTER TABLE `groups`
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_groups_name,
+    ADD UNIQUE INDEX idx_groups_tenant_name (tenant, name);
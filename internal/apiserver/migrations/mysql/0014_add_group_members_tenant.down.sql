@@ -0,0 +1,4 @@
+ALTER TABLE group_members
+    DROP INDEX idx_group_members_tenant_group_username,
+    ADD UNIQUE INDEX idx_group_members_group_username (group_name, username),
+    DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE group_members
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_group_members_group_username,
+    ADD UNIQUE INDEX idx_group_members_tenant_group_username (tenant, group_name, username);
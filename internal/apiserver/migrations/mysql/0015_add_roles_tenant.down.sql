@@ -0,0 +1,4 @@
+ALTER TABLE roles
+    DROP INDEX idx_roles_tenant_name,
+    ADD UNIQUE INDEX idx_roles_name (name),
+    DROP COLUMN tenant;
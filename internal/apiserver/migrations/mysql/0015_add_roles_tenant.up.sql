@@ -0,0 +1,4 @@
+ALTER TABLE roles
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_roles_name,
+    ADD UNIQUE INDEX idx_roles_tenant_name (tenant, name);
@@ -0,0 +1,4 @@
+ALTER TABLE policy_attachments
+    DROP INDEX idx_policy_attachments,
+    ADD UNIQUE INDEX idx_policy_attachments (principal_kind, principal_name, policy_owner, policy_name),
+    DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE policy_attachments
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX idx_policy_attachments,
+    ADD UNIQUE INDEX idx_policy_attachments (tenant, principal_kind, principal_name, policy_owner, policy_name);
@@ -0,0 +1,3 @@
+ALTER TABLE audit_events
+    DROP INDEX idx_audit_events_tenant,
+    DROP COLUMN tenant;
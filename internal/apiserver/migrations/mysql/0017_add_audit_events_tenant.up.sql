@@ -0,0 +1,3 @@
+ALTER TABLE audit_events
+    ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default' AFTER id,
+    ADD INDEX idx_audit_events_tenant (tenant);
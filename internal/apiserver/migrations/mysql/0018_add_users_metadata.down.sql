@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP INDEX idx_users_instance_id,
+    DROP COLUMN annotations,
+    DROP COLUMN labels,
+    DROP COLUMN instance_id;
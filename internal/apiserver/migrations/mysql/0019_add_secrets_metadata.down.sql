@@ -0,0 +1,6 @@
+ALTER TABLE secrets
+    DROP INDEX idx_secrets_instance_id,
+    DROP COLUMN annotations,
+    DROP COLUMN labels,
+    DROP COLUMN name,
+    DROP COLUMN instance_id;
@@ -0,0 +1,6 @@
+ALTER TABLE secrets
+    ADD COLUMN instance_id VARCHAR(64) AFTER id,
+    ADD COLUMN name VARCHAR(64) NOT NULL DEFAULT '' AFTER tenant,
+    ADD COLUMN labels JSON,
+    ADD COLUMN annotations JSON,
+    ADD UNIQUE INDEX idx_secrets_instance_id (instance_id);
@@ -0,0 +1,5 @@
+ALTER TABLE policies
+    DROP INDEX idx_policies_instance_id,
+    DROP COLUMN annotations,
+    DROP COLUMN labels,
+    DROP COLUMN instance_id;
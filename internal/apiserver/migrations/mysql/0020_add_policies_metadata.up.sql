@@ -0,0 +1,5 @@
+ALTER TABLE policies
+    ADD COLUMN instance_id VARCHAR(64) AFTER id,
+    ADD COLUMN labels JSON,
+    ADD COLUMN annotations JSON,
+    ADD UNIQUE INDEX idx_policies_instance_id (instance_id);
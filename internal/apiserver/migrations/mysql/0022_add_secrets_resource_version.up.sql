@@ -0,0 +1 @@
+ALTER TABLE secrets ADD COLUMN resource_version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER annotations;
@@ -0,0 +1 @@
+ALTER TABLE policies ADD COLUMN resource_version BIGINT UNSIGNED NOT NULL DEFAULT 1 AFTER annotations;
@@ -0,0 +1,4 @@
+CREATE TABLE resource_versions (
+    id      INT PRIMARY KEY,
+    version BIGINT UNSIGNED NOT NULL
+) DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,9 @@
+CREATE TABLE watch_events (
+    resource_version BIGINT UNSIGNED PRIMARY KEY,
+    tenant           VARCHAR(64) NOT NULL,
+    kind             VARCHAR(32) NOT NULL,
+    type             VARCHAR(16) NOT NULL,
+    object           JSON        NOT NULL,
+    created_at       DATETIME(3),
+    INDEX idx_watch_events_tenant_kind (tenant, kind)
+) DEFAULT CHARSET = utf8mb4;
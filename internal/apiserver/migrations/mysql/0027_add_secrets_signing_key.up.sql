@@ -0,0 +1 @@
+ALTER TABLE secrets ADD COLUMN signing_key VARCHAR(128) NOT NULL DEFAULT '' AFTER secret_key_hash;
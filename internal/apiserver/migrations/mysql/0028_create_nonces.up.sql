@@ -0,0 +1,5 @@
+CREATE TABLE nonces (
+    nonce      VARCHAR(192) PRIMARY KEY,
+    expires_at DATETIME(3)  NOT NULL,
+    INDEX idx_nonces_expires_at (expires_at)
+) DEFAULT CHARSET = utf8mb4;
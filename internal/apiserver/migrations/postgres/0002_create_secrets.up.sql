@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS secrets (
+    id              BIGSERIAL PRIMARY KEY,
+    username        VARCHAR(64) NOT NULL,
+    access_key      VARCHAR(64) NOT NULL,
+    secret_key_hash VARCHAR(64) NOT NULL,
+    description     VARCHAR(255),
+    expires_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_username ON secrets (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_secrets_access_key ON secrets (access_key);
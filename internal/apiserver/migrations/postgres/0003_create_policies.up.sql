@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS policies (
+    id          BIGSERIAL PRIMARY KEY,
+    username    VARCHAR(64) NOT NULL,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    document    JSONB       NOT NULL,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policies_username_name ON policies (username, name);
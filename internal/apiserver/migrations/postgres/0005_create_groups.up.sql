@@ -0,0 +1,9 @@
+CREATE TABLE groups (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_groups_name ON groups (name);
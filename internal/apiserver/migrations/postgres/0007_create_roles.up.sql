@@ -0,0 +1,11 @@
+CREATE TABLE roles (
+    id             BIGSERIAL PRIMARY KEY,
+    name           VARCHAR(64) NOT NULL,
+    description    VARCHAR(255),
+    trusted_users  JSONB       NOT NULL,
+    trusted_groups JSONB       NOT NULL,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_roles_name ON roles (name);
@@ -0,0 +1,4 @@
+ALTER TABLE secrets ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_secrets_username;
+CREATE INDEX idx_secrets_tenant_username ON secrets (tenant, username);
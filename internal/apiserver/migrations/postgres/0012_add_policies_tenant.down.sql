@@ -0,0 +1,4 @@
+DROP INDEX idx_policies_tenant_username_name;
+CREATE UNIQUE INDEX idx_policies_username_name ON policies (username, name);
+
+ALTER TABLE policies DROP COLUMN tenant;
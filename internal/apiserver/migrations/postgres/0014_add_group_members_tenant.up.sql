@@ -0,0 +1,4 @@
+ALTER TABLE group_members ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_group_members_group_username;
+CREATE UNIQUE INDEX idx_group_members_tenant_group_username ON group_members (tenant, group_name, username);
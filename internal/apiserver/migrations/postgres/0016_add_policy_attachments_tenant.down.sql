@@ -0,0 +1,4 @@
+DROP INDEX idx_policy_attachments;
+CREATE UNIQUE INDEX idx_policy_attachments ON policy_attachments (principal_kind, principal_name, policy_owner, policy_name);
+
+ALTER TABLE policy_attachments DROP COLUMN tenant;
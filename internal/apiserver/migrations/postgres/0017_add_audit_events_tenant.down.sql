@@ -0,0 +1,3 @@
+DROP INDEX idx_audit_events_tenant;
+
+ALTER TABLE audit_events DROP COLUMN tenant;
@@ -0,0 +1,5 @@
+DROP INDEX idx_users_instance_id;
+
+ALTER TABLE users DROP COLUMN annotations;
+ALTER TABLE users DROP COLUMN labels;
+ALTER TABLE users DROP COLUMN instance_id;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN instance_id VARCHAR(64);
+ALTER TABLE users ADD COLUMN labels JSONB;
+ALTER TABLE users ADD COLUMN annotations JSONB;
+
+CREATE UNIQUE INDEX idx_users_instance_id ON users (instance_id);
@@ -0,0 +1,6 @@
+DROP INDEX idx_secrets_instance_id;
+
+ALTER TABLE secrets DROP COLUMN annotations;
+ALTER TABLE secrets DROP COLUMN labels;
+ALTER TABLE secrets DROP COLUMN name;
+ALTER TABLE secrets DROP COLUMN instance_id;
@@ -0,0 +1,6 @@
+ALTER TABLE secrets ADD COLUMN instance_id VARCHAR(64);
+ALTER TABLE secrets ADD COLUMN name VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE secrets ADD COLUMN labels JSONB;
+ALTER TABLE secrets ADD COLUMN annotations JSONB;
+
+CREATE UNIQUE INDEX idx_secrets_instance_id ON secrets (instance_id);
@@ -0,0 +1,5 @@
+DROP INDEX idx_policies_instance_id;
+
+ALTER TABLE policies DROP COLUMN annotations;
+ALTER TABLE policies DROP COLUMN labels;
+ALTER TABLE policies DROP COLUMN instance_id;
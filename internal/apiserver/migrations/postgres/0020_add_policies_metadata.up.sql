@@ -0,0 +1,5 @@
+ALTER TABLE policies ADD COLUMN instance_id VARCHAR(64);
+ALTER TABLE policies ADD COLUMN labels JSONB;
+ALTER TABLE policies ADD COLUMN annotations JSONB;
+
+CREATE UNIQUE INDEX idx_policies_instance_id ON policies (instance_id);
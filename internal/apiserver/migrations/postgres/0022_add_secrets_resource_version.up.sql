@@ -0,0 +1 @@
+ALTER TABLE secrets ADD COLUMN resource_version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DELETE FROM resource_versions;
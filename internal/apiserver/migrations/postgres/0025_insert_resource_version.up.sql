@@ -0,0 +1,3 @@
+INSERT INTO resource_versions (id, version)
+SELECT 1, COALESCE(MAX(resource_version), 0)
+FROM (SELECT resource_version FROM policies UNION ALL SELECT resource_version FROM secrets) AS versions;
@@ -0,0 +1,10 @@
+CREATE TABLE watch_events (
+    resource_version BIGINT PRIMARY KEY,
+    tenant           VARCHAR(64) NOT NULL,
+    kind             VARCHAR(32) NOT NULL,
+    type             VARCHAR(16) NOT NULL,
+    object           JSONB       NOT NULL,
+    created_at       TIMESTAMPTZ
+);
+
+CREATE INDEX idx_watch_events_tenant_kind ON watch_events (tenant, kind);
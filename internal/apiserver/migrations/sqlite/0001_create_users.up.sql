@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS users (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       VARCHAR(64)  NOT NULL,
+    nickname   VARCHAR(64),
+    password   VARCHAR(255) NOT NULL,
+    email      VARCHAR(255),
+    phone      VARCHAR(32),
+    is_admin   BOOLEAN      NOT NULL DEFAULT false,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_name ON users (name);
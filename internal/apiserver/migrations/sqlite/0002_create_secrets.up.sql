@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS secrets (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    username        VARCHAR(64) NOT NULL,
+    access_key      VARCHAR(64) NOT NULL,
+    secret_key_hash VARCHAR(64) NOT NULL,
+    description     VARCHAR(255),
+    expires_at      DATETIME,
+    created_at      DATETIME,
+    updated_at      DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_username ON secrets (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_secrets_access_key ON secrets (access_key);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS policies (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    username    VARCHAR(64) NOT NULL,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    document    JSON        NOT NULL,
+    created_at  DATETIME,
+    updated_at  DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policies_username_name ON policies (username, name);
@@ -0,0 +1,16 @@
+CREATE TABLE audit_events (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor      VARCHAR(64),
+    action     VARCHAR(64)  NOT NULL,
+    resource   VARCHAR(255) NOT NULL,
+    status     INTEGER      NOT NULL,
+    diff       JSON,
+    decisions  JSON,
+    trace_id   VARCHAR(32),
+    client_ip  VARCHAR(64),
+    created_at DATETIME
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events (actor);
+CREATE INDEX idx_audit_events_resource ON audit_events (resource);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
@@ -0,0 +1,9 @@
+CREATE TABLE groups (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(64) NOT NULL,
+    description VARCHAR(255),
+    created_at  DATETIME,
+    updated_at  DATETIME
+);
+
+CREATE UNIQUE INDEX idx_groups_name ON groups (name);
@@ -0,0 +1,9 @@
+CREATE TABLE group_members (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_name VARCHAR(64) NOT NULL,
+    username   VARCHAR(64) NOT NULL,
+    created_at DATETIME
+);
+
+CREATE UNIQUE INDEX idx_group_members_group_username ON group_members (group_name, username);
+CREATE INDEX idx_group_members_username ON group_members (username);
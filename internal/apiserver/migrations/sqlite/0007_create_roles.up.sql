@@ -0,0 +1,11 @@
+CREATE TABLE roles (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    name           VARCHAR(64) NOT NULL,
+    description    VARCHAR(255),
+    trusted_users  JSON        NOT NULL,
+    trusted_groups JSON        NOT NULL,
+    created_at     DATETIME,
+    updated_at     DATETIME
+);
+
+CREATE UNIQUE INDEX idx_roles_name ON roles (name);
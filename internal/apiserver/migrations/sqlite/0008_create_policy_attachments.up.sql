@@ -0,0 +1,11 @@
+CREATE TABLE policy_attachments (
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    principal_kind VARCHAR(16) NOT NULL,
+    principal_name VARCHAR(64) NOT NULL,
+    policy_owner   VARCHAR(64) NOT NULL,
+    policy_name    VARCHAR(64) NOT NULL,
+    created_at     DATETIME
+);
+
+CREATE UNIQUE INDEX idx_policy_attachments ON policy_attachments (principal_kind, principal_name, policy_owner, policy_name);
+CREATE INDEX idx_policy_attachments_policy ON policy_attachments (policy_owner, policy_name);
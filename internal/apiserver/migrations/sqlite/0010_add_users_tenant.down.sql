@@ -0,0 +1,4 @@
+DROP INDEX idx_users_tenant_name;
+CREATE UNIQUE INDEX idx_users_name ON users (name);
+
+ALTER TABLE users DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_users_name;
+CREATE UNIQUE INDEX idx_users_tenant_name ON users (tenant, name);
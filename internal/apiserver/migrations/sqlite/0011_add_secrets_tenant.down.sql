@@ -0,0 +1,4 @@
+DROP INDEX idx_secrets_tenant_username;
+CREATE INDEX idx_secrets_username ON secrets (username);
+
+ALTER TABLE secrets DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+ALTER TABLE policies ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_policies_username_name;
+CREATE UNIQUE INDEX idx_policies_tenant_username_name ON policies (tenant, username, name);
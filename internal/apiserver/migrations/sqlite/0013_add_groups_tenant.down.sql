@@ -0,0 +1,4 @@
+DROP INDEX idx_groups_tenant_name;
+CREATE UNIQUE INDEX idx_groups_name ON groups (name);
+
+ALTER TABLE groups DROP COLUMN tenant;
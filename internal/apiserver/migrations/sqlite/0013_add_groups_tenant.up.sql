@@ -0,0 +1,4 @@
+ALTER TABLE groups ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_groups_name;
+CREATE UNIQUE INDEX idx_groups_tenant_name ON groups (tenant, name);
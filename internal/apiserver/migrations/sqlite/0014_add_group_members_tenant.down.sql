@@ -0,0 +1,4 @@
+DROP INDEX idx_group_members_tenant_group_username;
+CREATE UNIQUE INDEX idx_group_members_group_username ON group_members (group_name, username);
+
+ALTER TABLE group_members DROP COLUMN tenant;
@@ -0,0 +1,4 @@
+DROP INDEX idx_roles_tenant_name;
+CREATE UNIQUE INDEX idx_roles_name ON roles (name);
+
+ALTER TABLE roles DROP COLUMN tenant;
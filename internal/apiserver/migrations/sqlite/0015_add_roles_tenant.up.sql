@@ -0,0 +1,4 @@
+ALTER TABLE roles ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_roles_name;
+CREATE UNIQUE INDEX idx_roles_tenant_name ON roles (tenant, name);
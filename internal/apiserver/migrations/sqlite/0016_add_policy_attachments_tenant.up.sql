@@ -0,0 +1,4 @@
+ALTER TABLE policy_attachments ADD COLUMN tenant VARCHAR(64) NOT NULL DEFAULT 'default';
+
+DROP INDEX idx_policy_attachments;
+CREATE UNIQUE INDEX idx_policy_attachments ON policy_attachments (tenant, principal_kind, principal_name, policy_owner, policy_name);
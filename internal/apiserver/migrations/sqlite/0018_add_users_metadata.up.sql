@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN instance_id VARCHAR(64);
+ALTER TABLE users ADD COLUMN labels JSON;
+ALTER TABLE users ADD COLUMN annotations JSON;
+
+CREATE UNIQUE INDEX idx_users_instance_id ON users (instance_id);
@@ -0,0 +1,5 @@
+ALTER TABLE policies ADD COLUMN instance_id VARCHAR(64);
+ALTER TABLE policies ADD COLUMN labels JSON;
+ALTER TABLE policies ADD COLUMN annotations JSON;
+
+CREATE UNIQUE INDEX idx_policies_instance_id ON policies (instance_id);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN resource_version BIGINT NOT NULL DEFAULT 1;
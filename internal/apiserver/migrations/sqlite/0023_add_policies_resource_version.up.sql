@@ -0,0 +1 @@
+ALTER TABLE policies ADD COLUMN resource_version BIGINT NOT NULL DEFAULT 1;
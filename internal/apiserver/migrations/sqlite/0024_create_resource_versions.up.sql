@@ -0,0 +1,4 @@
+CREATE TABLE resource_versions (
+    id      INTEGER PRIMARY KEY,
+    version BIGINT NOT NULL
+);
@@ -0,0 +1,6 @@
+CREATE TABLE nonces (
+    nonce      VARCHAR(192) PRIMARY KEY,
+    expires_at DATETIME     NOT NULL
+);
+
+CREATE INDEX idx_nonces_expires_at ON nonces (expires_at);
@@ -0,0 +1,7 @@
+package model
+
+// Principal identifies a user, a group or a role.
+type Principal struct {
+	Kind string
+	Name string
+}
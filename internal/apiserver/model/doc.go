@@ -0,0 +1,2 @@
+// Package model defines the persistent objects of siam-apiserver.
+package model
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Nonce is a nonce of the signed requests which is remembered until it expires, so that the requests replayed
+// to any instance of siam-apiserver are rejected. It is also used as gorm model.
+type Nonce struct {
+	// Nonce is the access key and the nonce of the request joined by '/'.
+	Nonce     string    `gorm:"primaryKey;size:192"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+// TableName maps to database table name.
+func (Nonce) TableName() string {
+	return "nonces"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "strings"
+
+// roleSubjectPrefix prefixes the role name in the subject of the role sessions.
+const roleSubjectPrefix = "role:"
+
+// RoleSubject returns the subject of the sessions of the role, e.g. `role:deployer`.
+// It never collides with a username, which is alphanumeric.
+func RoleSubject(role string) string {
+	return roleSubjectPrefix + role
+}
+
+// RoleFromSubject returns the role name of the subject of a role session,
+// ok is false if the subject is not a role session.
+func RoleFromSubject(subject string) (role string, ok bool) {
+	return strings.CutPrefix(subject, roleSubjectPrefix)
+}
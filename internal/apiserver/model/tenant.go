@@ -0,0 +1,5 @@
+package model
+
+// DefaultTenant is the tenant of the API paths without a tenant, it always exists and is never stored.
+// The admins of the default tenant administrate all of the tenants.
+const DefaultTenant = "default"
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// The kinds of the watchable objects.
+const (
+	KindPolicy = "policies"
+	KindSecret = "secrets"
+)
+
+// WatchEvent records a change of a watchable object, the object is its JSON after the change, or before it
+// if the object is deleted. It is also used as gorm model.
+type WatchEvent struct {
+	// ResourceVersion is the resource version of the change, the object of the Added and the Modified events
+	// has the same version.
+	ResourceVersion uint64           `json:"resourceVersion,string" gorm:"primaryKey;autoIncrement:false"`
+	Tenant          string           `json:"tenant"                 gorm:"size:64;not null"`
+	Kind            string           `json:"kind"                   gorm:"size:32;not null"`
+	Type            metav1.EventType `json:"type"                   gorm:"size:16;not null"`
+	Object          json.RawMessage  `json:"object"                 gorm:"serializer:json;not null"`
+	CreatedAt       time.Time        `json:"createdAt"`
+}
+
+// TableName maps to database table name.
+func (WatchEvent) TableName() string {
+	return "watch_events"
+}
+
+// NewWatchEvent creates the event of the change of the object in the tenant.
+func NewWatchEvent(tenant, kind string, typ metav1.EventType, version uint64, obj any) (*WatchEvent, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &WatchEvent{ResourceVersion: version, Tenant: tenant, Kind: kind, Type: typ, Object: data}, nil
+}
@@ -0,0 +1,40 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// AdminOptions defines the system admin which is created on start if it does not exist, since the users
+// registered by themselves are never admins.
+type AdminOptions struct {
+	// Username is the name of the admin in the default tenant, no admin is created if it is empty.
+	Username string `json:"username" mapstructure:"username"`
+	// Password is the initial password of the admin, which should be changed after the first login.
+	Password string `json:"password" mapstructure:"password"`
+}
+
+// NewAdminOptions creates an AdminOptions instance with default values.
+func NewAdminOptions() *AdminOptions {
+	return &AdminOptions{}
+}
+
+// Flags adds flags for the admin options to the specified FlagSet.
+func (o *AdminOptions) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Username, "admin.username", o.Username,
+		"Name of the system admin which is created on start if it does not exist, none is created if it is empty.")
+	fs.StringVar(&o.Password, "admin.password", o.Password, "Initial password of the system admin.")
+}
+
+// Validate checks the admin options and returns all of the found errors.
+func (o *AdminOptions) Validate() []error {
+	if o.Username == "" {
+		return nil
+	}
+	var errs []error
+	if len(o.Password) < 8 || len(o.Password) > 64 {
+		errs = append(errs, fmt.Errorf("admin.password must be 8 to 64 characters"))
+	}
+	return errs
+}
@@ -0,0 +1,59 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Supported audit sinks.
+const (
+	AuditSinkStore  = "store"
+	AuditSinkFile   = "file"
+	AuditSinkLogger = "logger"
+)
+
+// AuditOptions defines the configuration options for the audit log.
+type AuditOptions struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// Sink is where the events are written, only the events in the store can be queried.
+	Sink string `json:"sink" mapstructure:"sink"`
+	// File is the JSON lines file of the file sink.
+	File string `json:"file" mapstructure:"file"`
+}
+
+// NewAuditOptions creates an AuditOptions instance with default values.
+func NewAuditOptions() *AuditOptions {
+	return &AuditOptions{
+		Enabled: true,
+		Sink:    AuditSinkStore,
+		File:    "",
+	}
+}
+
+// Flags adds flags for the audit options to the specified FlagSet.
+func (o *AuditOptions) Flags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, "audit.enabled", o.Enabled,
+		"Record the requests which change the resources and the authorization decisions.")
+	fs.StringVar(&o.Sink, "audit.sink", o.Sink,
+		"Where the audit events are written, supported values: store, file, logger. Only the store can be queried.")
+	fs.StringVar(&o.File, "audit.file", o.File, "JSON lines file of the file audit sink.")
+}
+
+// Validate checks the audit options and returns all of the found errors.
+func (o *AuditOptions) Validate() []error {
+	if !o.Enabled {
+		return nil
+	}
+	switch o.Sink {
+	case AuditSinkStore, AuditSinkLogger:
+		return nil
+	case AuditSinkFile:
+		if o.File == "" {
+			return []error{fmt.Errorf("audit.file must not be empty for the file sink")}
+		}
+		return nil
+	default:
+		return []error{fmt.Errorf("audit.sink %q is not supported", o.Sink)}
+	}
+}
@@ -0,0 +1,53 @@
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	genericoptions "github.com/strayca7/siam/internal/pkg/options"
+	cliflag "github.com/strayca7/siam/staging/src/component-base/cli/flag"
+)
+
+// MigrationOptions defines the configuration options for the database migrations.
+type MigrationOptions struct {
+	// AutoMigrate applies the pending migrations when the server starts.
+	AutoMigrate bool `json:"autoMigrate" mapstructure:"autoMigrate"`
+}
+
+// NewMigrationOptions creates a MigrationOptions instance with default values.
+func NewMigrationOptions() *MigrationOptions {
+	return &MigrationOptions{}
+}
+
+// Flags adds flags for the migration options to the specified FlagSet.
+func (o *MigrationOptions) Flags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.AutoMigrate, "migration.autoMigrate", o.AutoMigrate,
+		"Apply the pending database migrations when the server starts.")
+}
+
+// Validate checks the migration options and returns all of the found errors.
+func (o *MigrationOptions) Validate() []error {
+	return nil
+}
+
+// MigrateOptions are the options of the migrate command, which only connects to the database.
+type MigrateOptions struct {
+	Database *genericoptions.Database `json:"database" mapstructure:"database"`
+}
+
+// NewMigrateOptions creates a MigrateOptions instance with default values.
+func NewMigrateOptions() *MigrateOptions {
+	return &MigrateOptions{
+		Database: genericoptions.NewDatabase(),
+	}
+}
+
+// Flags returns flags for the migrate command grouped by section name.
+func (o *MigrateOptions) Flags() (fss cliflag.NamedFlagSets) {
+	o.Database.Flags(fss.FlagSet("database"))
+	return fss
+}
+
+// Validate checks all of the options and returns the found errors.
+func (o *MigrateOptions) Validate() []error {
+	return o.Database.Validate()
+}
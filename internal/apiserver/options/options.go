@@ -1,13 +1,100 @@
 package options
 
-import genericoptions "github.com/strayca7/siam/internal/pkg/options"
+import (
+	"encoding/json"
+	"fmt"
+
+	genericoptions "github.com/strayca7/siam/internal/pkg/options"
+	cliflag "github.com/strayca7/siam/staging/src/component-base/cli/flag"
+)
 
 type Options struct {
-	Postgres *genericoptions.Postgres `json:"postgres" mapstructure:"postgres"`
+	Server    *genericoptions.Server   `json:"server"    mapstructure:"server"`
+	Store     *StoreOptions            `json:"store"     mapstructure:"store"`
+	Database  *genericoptions.Database `json:"database"  mapstructure:"database"`
+	JWT       *genericoptions.JWT      `json:"jwt"       mapstructure:"jwt"`
+	Secret    *SecretOptions           `json:"secret"    mapstructure:"secret"`
+	Migration *MigrationOptions        `json:"migration" mapstructure:"migration"`
+	Audit     *AuditOptions            `json:"audit"     mapstructure:"audit"`
+	Role      *RoleOptions             `json:"role"      mapstructure:"role"`
+	Admin     *AdminOptions            `json:"admin"     mapstructure:"admin"`
+
+	// Postgres is the former name of the database section, it is decoded only to reject the configurations
+	// which still use it, which would otherwise connect with the default database options.
+	Postgres map[string]any `json:"-" mapstructure:"postgres"`
 }
 
 func NewOptions() *Options {
 	return &Options{
-		Postgres: genericoptions.NewPostgres(),
+		Server:    genericoptions.NewServer(),
+		Store:     NewStoreOptions(),
+		Database:  genericoptions.NewDatabase(),
+		JWT:       genericoptions.NewJWT(),
+		Secret:    NewSecretOptions(),
+		Migration: NewMigrationOptions(),
+		Audit:     NewAuditOptions(),
+		Role:      NewRoleOptions(),
+		Admin:     NewAdminOptions(),
+	}
+}
+
+// Flags returns flags for the apiserver grouped by section name.
+func (o *Options) Flags() (fss cliflag.NamedFlagSets) {
+	o.Server.Flags(fss.FlagSet("server"))
+	o.Store.Flags(fss.FlagSet("store"))
+	o.Database.Flags(fss.FlagSet("database"))
+	o.JWT.Flags(fss.FlagSet("jwt"))
+	o.Secret.Flags(fss.FlagSet("secret"))
+	o.Migration.Flags(fss.FlagSet("migration"))
+	o.Audit.Flags(fss.FlagSet("audit"))
+	o.Role.Flags(fss.FlagSet("role"))
+	o.Admin.Flags(fss.FlagSet("admin"))
+	return fss
+}
+
+// Validate checks all of the options and returns the found errors.
+func (o *Options) Validate() []error {
+	var errs []error
+	errs = append(errs, o.Server.Validate()...)
+	errs = append(errs, o.Store.Validate()...)
+	if o.Postgres != nil {
+		errs = append(errs, fmt.Errorf("the postgres section of the configuration has been renamed to database"))
+	}
+	if o.Store.Type == StoreDatabase {
+		errs = append(errs, o.Database.Validate()...)
+	}
+	errs = append(errs, o.JWT.Validate()...)
+	errs = append(errs, o.Secret.Validate()...)
+	errs = append(errs, o.Migration.Validate()...)
+	errs = append(errs, o.Audit.Validate()...)
+	errs = append(errs, o.Role.Validate()...)
+	errs = append(errs, o.Admin.Validate()...)
+	return errs
+}
+
+// String returns the options in JSON format with the secrets masked.
+func (o *Options) String() string {
+	masked := *o
+	db := *o.Database
+	if db.Password != "" {
+		db.Password = "******"
+	}
+	masked.Database = &db
+	jwt := *o.JWT
+	if jwt.Key != "" {
+		jwt.Key = "******"
+	}
+	masked.JWT = &jwt
+	secret := *o.Secret
+	if secret.EncryptionKey != "" {
+		secret.EncryptionKey = "******"
+	}
+	masked.Secret = &secret
+	admin := *o.Admin
+	if admin.Password != "" {
+		admin.Password = "******"
 	}
+	masked.Admin = &admin
+	data, _ := json.Marshal(masked)
+	return string(data)
 }
@@ -0,0 +1,45 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RoleOptions defines the configuration options for the sessions of the assumed roles.
+type RoleOptions struct {
+	// SessionDuration is the duration of a role session when the client does not ask for one.
+	SessionDuration time.Duration `json:"sessionDuration"    mapstructure:"sessionDuration"`
+	// MaxSessionDuration is the maximum duration of a role session the client is allowed to ask for.
+	MaxSessionDuration time.Duration `json:"maxSessionDuration" mapstructure:"maxSessionDuration"`
+}
+
+// NewRoleOptions creates a RoleOptions instance with default values.
+func NewRoleOptions() *RoleOptions {
+	return &RoleOptions{
+		SessionDuration:    time.Hour,
+		MaxSessionDuration: 12 * time.Hour,
+	}
+}
+
+// Flags adds flags for the role options to the specified FlagSet.
+func (o *RoleOptions) Flags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.SessionDuration, "role.sessionDuration", o.SessionDuration,
+		"Duration of a role session when the client does not ask for one.")
+	fs.DurationVar(&o.MaxSessionDuration, "role.maxSessionDuration", o.MaxSessionDuration,
+		"Maximum duration of a role session the client is allowed to ask for.")
+}
+
+// Validate checks the role options and returns all of the found errors.
+func (o *RoleOptions) Validate() []error {
+	var errs []error
+	if o.SessionDuration <= 0 {
+		errs = append(errs, fmt.Errorf("role.sessionDuration %s must be positive", o.SessionDuration))
+	}
+	if o.MaxSessionDuration < o.SessionDuration {
+		errs = append(errs, fmt.Errorf("role.maxSessionDuration %s must not be less than role.sessionDuration %s",
+			o.MaxSessionDuration, o.SessionDuration))
+	}
+	return errs
+}
@@ -0,0 +1,76 @@
+package options
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/sign"
+)
+
+// knownEncryptionKeys are the encryption keys published with the sample configurations, which must never
+// seal the signing keys of a deployment.
+var knownEncryptionKeys = []string{"siam-development-secret-encryption-key-change-me"}
+
+// SecretOptions defines the configuration options for the secrets of users.
+type SecretOptions struct {
+	// MaxCount is the maximum number of secrets a user is allowed to own.
+	MaxCount int `json:"maxCount" mapstructure:"maxCount"`
+	// MaxSkew is the maximum difference between the time of a signed request and the server time.
+	MaxSkew time.Duration `json:"maxSkew" mapstructure:"maxSkew"`
+	// EncryptionKey is the server key which seals the signing keys of the secrets at rest, see auth.KeyCipher.
+	// The secrets are unusable if it is changed.
+	EncryptionKey string `json:"encryptionKey" mapstructure:"encryptionKey"`
+	// MaxBodySize is the largest body in bytes of the signed requests, which is read before the signature
+	// is verified.
+	MaxBodySize int64 `json:"maxBodySize" mapstructure:"maxBodySize"`
+}
+
+// NewSecretOptions creates a SecretOptions instance with default values.
+func NewSecretOptions() *SecretOptions {
+	return &SecretOptions{
+		MaxCount:    10,
+		MaxSkew:     5 * time.Minute,
+		MaxBodySize: sign.DefaultMaxBodySize,
+	}
+}
+
+// Flags adds flags for the secret options to the specified FlagSet.
+func (o *SecretOptions) Flags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxCount, "secret.maxCount", o.MaxCount, "Maximum number of secrets a user is allowed to own.")
+	fs.DurationVar(&o.MaxSkew, "secret.maxSkew", o.MaxSkew,
+		"Maximum difference between the time of a signed request and the server time.")
+	fs.StringVar(&o.EncryptionKey, "secret.encryptionKey", o.EncryptionKey,
+		"Server key which seals the signing keys of the secrets at rest, the secrets are unusable if it is changed.")
+	fs.Int64Var(&o.MaxBodySize, "secret.maxBodySize", o.MaxBodySize,
+		"Largest body in bytes of the signed requests, which is read before the signature is verified.")
+}
+
+// Validate checks the secret options and returns all of the found errors.
+func (o *SecretOptions) Validate() []error {
+	var errs []error
+	if o.MaxCount < 1 {
+		errs = append(errs, fmt.Errorf("secret.maxCount %d must be positive", o.MaxCount))
+	}
+	if o.MaxSkew <= 0 {
+		errs = append(errs, fmt.Errorf("secret.maxSkew %s must be positive", o.MaxSkew))
+	}
+	if o.MaxBodySize <= 0 {
+		errs = append(errs, fmt.Errorf("secret.maxBodySize %d must be positive", o.MaxBodySize))
+	}
+	switch {
+	case len(o.EncryptionKey) < 32:
+		errs = append(errs, fmt.Errorf("secret.encryptionKey must be at least 32 bytes"))
+	case slices.Contains(knownEncryptionKeys, o.EncryptionKey):
+		errs = append(errs, fmt.Errorf("secret.encryptionKey must not be the published sample key"))
+	}
+	return errs
+}
+
+// NewKeyCipher creates the cipher of the signing keys with the encryption key.
+func (o *SecretOptions) NewKeyCipher() (*auth.KeyCipher, error) {
+	return auth.NewKeyCipher(o.EncryptionKey)
+}
@@ -0,0 +1,42 @@
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// Supported store types.
+const (
+	StoreDatabase = "database"
+	StoreMemory   = "memory"
+)
+
+// StoreOptions defines the configuration options for the storage of the api objects.
+type StoreOptions struct {
+	// Type is the storage backend, the memory store loses all of the data when the server exits.
+	Type string `json:"type" mapstructure:"type"`
+}
+
+// NewStoreOptions creates a StoreOptions instance with default values.
+func NewStoreOptions() *StoreOptions {
+	return &StoreOptions{
+		Type: StoreDatabase,
+	}
+}
+
+// Flags adds flags for the store options to the specified FlagSet.
+func (o *StoreOptions) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Type, "store.type", o.Type,
+		"Storage backend of the api objects, supported values: database, memory.")
+}
+
+// Validate checks the store options and returns all of the found errors.
+func (o *StoreOptions) Validate() []error {
+	switch o.Type {
+	case StoreDatabase, StoreMemory:
+		return nil
+	default:
+		return []error{fmt.Errorf("store.type %q is not supported", o.Type)}
+	}
+}
@@ -0,0 +1,191 @@
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/attachment"
+	auditcontroller "github.com/strayca7/siam/internal/apiserver/controller/v1/audit"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/authz"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/group"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/login"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/policy"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/role"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/secret"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/tenant"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/user"
+	"github.com/strayca7/siam/internal/apiserver/controller/v1/watch"
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// installRoutes installs the generic and the v1 API routes.
+func (s *apiServer) installRoutes() {
+	g := s.engine
+	g.NoRoute(func(c *gin.Context) {
+		core.WriteResponse(c, serrors.WithCode(code.ErrPageNotFound, "page not found"), nil)
+	})
+	if s.opts.Server.Healthz {
+		g.GET("/healthz", func(c *gin.Context) {
+			c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		})
+	}
+
+	userController := user.NewUserController(s.store)
+	loginController := login.NewLoginController(s.store, s.jwt)
+	// the nonces are kept in the store, so the replays to the other instances sharing the database are rejected
+	verifier := sign.NewVerifier(secretKeyFunc(s.store, s.keys), s.opts.Secret.MaxSkew,
+		sign.WithMaxBodySize(s.opts.Secret.MaxBodySize), sign.WithNonceStore(s.store.Nonces()))
+	authn := middleware.AutoAuth(s.jwt, tokenClaimsFunc(s.store), verifier)
+
+	v1 := g.Group("/v1")
+	{
+		// the paths below /v1/tenants/:tenant are scoped to the tenant, the others to the default tenant
+		tenantv1 := v1.Group("/tenants/:tenant", tenantScope(s.store))
+
+		// the logins and the user registration of the default tenant are the only routes without authentication
+		v1.POST("/login", loginController.Login)
+		tenantv1.POST("/login", loginController.Login)
+		if s.auditSink != nil {
+			// the routes below are audited except the reads
+			v1.Use(audit.Middleware(s.auditSink))
+			tenantv1.Use(audit.Middleware(s.auditSink))
+		}
+		v1.POST("/users", middleware.WritePrimary(), userController.Create)
+
+		v1.Use(authn, tenantAccess(s.store))
+		tenantv1.Use(authn, tenantAccess(s.store))
+
+		// the users of the other tenants, and the admins of all of the tenants, are created by the admins
+		tenantv1.POST("/users", middleware.WritePrimary(), tenantAdmin(s.store), userController.AdminCreate)
+		s.installResourceRoutes(v1, userController)
+		s.installResourceRoutes(tenantv1, userController)
+
+		tenantController := tenant.NewTenantController(s.store)
+		// the tenants are administrated by the system admins, who are the admins of the default tenant
+		v1.POST("/tenants", middleware.WritePrimary(), tenantAdmin(s.store), tenantController.Create)
+		v1.GET("/tenants", tenantAdmin(s.store), tenantController.List)
+		v1.GET("/tenants/:tenant", tenantAdmin(s.store), tenantController.Get)
+		v1.PUT("/tenants/:tenant", middleware.WritePrimary(), tenantAdmin(s.store), tenantController.Update)
+		v1.DELETE("/tenants/:tenant", middleware.WritePrimary(), tenantAdmin(s.store), tenantController.Delete)
+	}
+}
+
+// installResourceRoutes installs the routes of the resources in a tenant, except the user creation
+// which is authenticated in some tenants only.
+func (s *apiServer) installResourceRoutes(g *gin.RouterGroup, userController *user.UserController) {
+	// the modifying requests read from the primary, while the authz checks and the other reads
+	// go to the replicas unless the client asks for the primary, see middleware.ReadPrimary
+	// the sessions of the roles make the authorization requests only
+	userv1 := g.Group("/users", middleware.WritePrimary(), noRoleSession())
+	{
+		userv1.GET("", userController.List)
+		userv1.GET(":name", userController.Get)
+
+		// the users and the objects they own are managed by themselves and the admins
+		ownerv1 := userv1.Group(":name", ownerOrAdmin(s.store))
+		{
+			ownerv1.PUT("", userController.Update)
+			ownerv1.DELETE("", userController.Delete)
+			ownerv1.PUT("/change-password", userController.ChangePassword)
+
+			secretv1 := ownerv1.Group("/secrets")
+			{
+				secretController := secret.NewSecretController(s.store, s.opts.Secret, s.keys)
+
+				secretv1.POST("", secretController.Create)
+				secretv1.GET("", secretController.List)
+				secretv1.GET(":accessKey", secretController.Get)
+				secretv1.PUT(":accessKey", secretController.Update)
+				secretv1.DELETE(":accessKey", secretController.Delete)
+				secretv1.POST(":accessKey/rotate", secretController.Rotate)
+				secretv1.POST(":accessKey/expire", secretController.Expire)
+			}
+
+			// the policies owned by a user apply to it, so only the admins write them,
+			// otherwise a user could grant itself everything
+			policyv1 := ownerv1.Group("/policies")
+			{
+				policyController := policy.NewPolicyController(s.store)
+
+				policyv1.POST("", tenantAdmin(s.store), policyController.Create)
+				policyv1.GET("", policyController.List)
+				policyv1.GET(":policy", policyController.Get)
+				policyv1.PUT(":policy", tenantAdmin(s.store), policyController.Update)
+				policyv1.DELETE(":policy", tenantAdmin(s.store), policyController.Delete)
+			}
+
+			installAttachmentRoutes(ownerv1, attachment.NewAttachmentController(s.store, apiv1.PrincipalUser),
+				tenantAdmin(s.store))
+		}
+	}
+
+	// the groups and the roles grant the policies attached to them, so only the admins change them
+	// and their members, while the trusted users assume the roles
+	groupv1 := g.Group("/groups", middleware.WritePrimary(), noRoleSession())
+	{
+		groupController := group.NewGroupController(s.store)
+
+		groupv1.POST("", tenantAdmin(s.store), groupController.Create)
+		groupv1.GET("", groupController.List)
+		groupv1.GET(":name", groupController.Get)
+		groupv1.PUT(":name", tenantAdmin(s.store), groupController.Update)
+		groupv1.DELETE(":name", tenantAdmin(s.store), groupController.Delete)
+		groupv1.POST(":name/members", tenantAdmin(s.store), groupController.AddMember)
+		groupv1.GET(":name/members", groupController.ListMembers)
+		groupv1.DELETE(":name/members/:username", tenantAdmin(s.store), groupController.RemoveMember)
+
+		installAttachmentRoutes(groupv1.Group(":name"),
+			attachment.NewAttachmentController(s.store, apiv1.PrincipalGroup), tenantAdmin(s.store))
+	}
+
+	rolev1 := g.Group("/roles", middleware.WritePrimary(), noRoleSession())
+	{
+		roleController := role.NewRoleController(s.store, s.jwt, s.opts.Role)
+
+		rolev1.POST("", tenantAdmin(s.store), roleController.Create)
+		rolev1.GET("", roleController.List)
+		rolev1.GET(":name", roleController.Get)
+		rolev1.PUT(":name", tenantAdmin(s.store), roleController.Update)
+		rolev1.DELETE(":name", tenantAdmin(s.store), roleController.Delete)
+		rolev1.POST(":name/assume", roleController.Assume)
+
+		installAttachmentRoutes(rolev1.Group(":name"),
+			attachment.NewAttachmentController(s.store, apiv1.PrincipalRole), tenantAdmin(s.store))
+	}
+
+	authzController := authz.NewAuthzController(s.store)
+	g.POST("/authz", authzController.Authorize)
+
+	auditController := auditcontroller.NewAuditController(s.store,
+		s.opts.Audit.Enabled && s.opts.Audit.Sink == options.AuditSinkStore)
+	g.GET("/audit-events", noRoleSession(), auditController.List)
+
+	// the watches see the objects of all of the users, so they are limited to the admins
+	watchv1 := g.Group("/watch", tenantAdmin(s.store))
+	{
+		watchController := watch.NewWatchController(s.store, s.stopping)
+
+		watchv1.GET("/policies", watchController.Policies)
+		watchv1.GET("/secrets", watchController.Secrets)
+	}
+}
+
+// installAttachmentRoutes installs the routes of the policies attached to the principal of the group,
+// which is named by the `name` parameter. Only the principals passed by admin change the attachments,
+// since both attaching an allow policy and detaching a deny policy grant the permissions.
+func installAttachmentRoutes(g *gin.RouterGroup, controller *attachment.AttachmentController, admin gin.HandlerFunc) {
+	attachmentv1 := g.Group("/attachments")
+	{
+		attachmentv1.POST("", admin, controller.Create)
+		attachmentv1.GET("", controller.List)
+		attachmentv1.DELETE(":owner/:policy", admin, controller.Delete)
+	}
+}
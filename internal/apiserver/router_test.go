@@ -0,0 +1,203 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "apiserver")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+const testAdmin = "root"
+
+// newTestServer creates a server of the memory store with the system admin testAdmin.
+func newTestServer(t *testing.T) *apiServer {
+	t.Helper()
+	opts := options.NewOptions()
+	opts.Server.Mode = "test"
+	opts.Store.Type = options.StoreMemory
+	opts.Audit.Enabled = false
+	opts.JWT.Key = "test-jwt-key-0123456789abcdefghijklmn"
+	opts.Secret.EncryptionKey = "test-encryption-key-0123456789abcdefgh"
+	opts.Admin.Username = testAdmin
+	opts.Admin.Password = "password1"
+	s, err := createAPIServer(opts)
+	if err != nil {
+		t.Fatalf("createAPIServer() error = %v", err)
+	}
+	return s
+}
+
+// do serves the request of the token with the JSON body, and returns the status and the response body.
+func (s *apiServer) do(t *testing.T, token, method, path string, body any) (int, []byte) {
+	t.Helper()
+	var r *http.Request
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		r = httptest.NewRequest(method, path, strings.NewReader(string(data)))
+		r.Header.Set("Content-Type", "application/json")
+	} else {
+		r = httptest.NewRequest(method, path, nil)
+	}
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.engine.ServeHTTP(w, r)
+	return w.Code, w.Body.Bytes()
+}
+
+// register registers the user in the default tenant and returns its token.
+func (s *apiServer) register(t *testing.T, name string) string {
+	t.Helper()
+	body := map[string]string{"name": name, "password": "password1", "email": name + "@siam.example"}
+	if status, resp := s.do(t, "", http.MethodPost, "/v1/users", body); status != http.StatusOK {
+		t.Fatalf("register %q: %d %s", name, status, resp)
+	}
+	return s.login(t, name)
+}
+
+// login returns a token of the user in the default tenant.
+func (s *apiServer) login(t *testing.T, name string) string {
+	t.Helper()
+	status, resp := s.do(t, "", http.MethodPost, "/v1/login", map[string]string{"username": name, "password": "password1"})
+	if status != http.StatusOK {
+		t.Fatalf("login %q: %d %s", name, status, resp)
+	}
+	var token struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(resp, &token); err != nil {
+		t.Fatalf("decode token of %q: %v", name, err)
+	}
+	return token.Token
+}
+
+// allowAll is a policy which allows everything.
+var allowAll = map[string]any{
+	"name": "everything",
+	"document": map[string]any{
+		"version":    "2025-10-01",
+		"statements": []map[string]any{{"effect": "allow", "actions": []string{"*"}, "resources": []string{"*"}}},
+	},
+}
+
+func TestOwnedPolicyWrites(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(t, testAdmin)
+	alice := s.register(t, "alice")
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   any
+		want   int
+	}{
+		{"owner creates", alice, http.MethodPost, "/v1/users/alice/policies", allowAll, http.StatusForbidden},
+		{"admin creates", admin, http.MethodPost, "/v1/users/alice/policies", allowAll, http.StatusOK},
+		{"owner lists", alice, http.MethodGet, "/v1/users/alice/policies", nil, http.StatusOK},
+		{"owner gets", alice, http.MethodGet, "/v1/users/alice/policies/everything", nil, http.StatusOK},
+		{"owner updates", alice, http.MethodPut, "/v1/users/alice/policies/everything", allowAll, http.StatusForbidden},
+		{"owner deletes", alice, http.MethodDelete, "/v1/users/alice/policies/everything", nil, http.StatusForbidden},
+		{"admin deletes", admin, http.MethodDelete, "/v1/users/alice/policies/everything", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if status, resp := s.do(t, tt.token, tt.method, tt.path, tt.body); status != tt.want {
+			t.Errorf("%s: %s %s = %d %s, want %d", tt.name, tt.method, tt.path, status, resp, tt.want)
+		}
+	}
+}
+
+func TestGroupAndRoleWrites(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.login(t, testAdmin)
+	alice := s.register(t, "alice")
+	for _, req := range []struct {
+		path string
+		body any
+	}{
+		{"/v1/users/alice/policies", allowAll},
+		{"/v1/groups", map[string]any{"name": "devs"}},
+		{"/v1/roles", map[string]any{"name": "deployer", "trustedUsers": []string{"alice"}}},
+	} {
+		if status, resp := s.do(t, admin, http.MethodPost, req.path, req.body); status != http.StatusOK {
+			t.Fatalf("POST %s = %d %s", req.path, status, resp)
+		}
+	}
+
+	attach := map[string]any{"policyOwner": "alice", "policyName": "everything"}
+	describe := map[string]any{"description": "changed"}
+	tests := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodPost, "/v1/groups", map[string]any{"name": "ops"}},
+		{http.MethodPut, "/v1/groups/devs", describe},
+		{http.MethodPost, "/v1/groups/devs/members", map[string]any{"username": "alice"}},
+		{http.MethodPost, "/v1/groups/devs/attachments", attach},
+		{http.MethodPost, "/v1/roles", map[string]any{"name": "auditor"}},
+		{http.MethodPut, "/v1/roles/deployer", describe},
+		{http.MethodPost, "/v1/roles/deployer/attachments", attach},
+		{http.MethodPost, "/v1/users/alice/attachments", attach},
+	}
+	// the non-admins are denied, and the admins are allowed to make the same requests
+	for _, tt := range tests {
+		if status, resp := s.do(t, alice, tt.method, tt.path, tt.body); status != http.StatusForbidden {
+			t.Errorf("non-admin %s %s = %d %s, want %d", tt.method, tt.path, status, resp, http.StatusForbidden)
+		}
+		if status, resp := s.do(t, admin, tt.method, tt.path, tt.body); status != http.StatusOK {
+			t.Errorf("admin %s %s = %d %s, want %d", tt.method, tt.path, status, resp, http.StatusOK)
+		}
+	}
+
+	deletes := []string{
+		"/v1/users/alice/attachments/alice/everything",
+		"/v1/roles/deployer/attachments/alice/everything",
+		"/v1/roles/deployer",
+		"/v1/groups/devs/attachments/alice/everything",
+		"/v1/groups/devs/members/alice",
+		"/v1/groups/devs",
+	}
+	for _, path := range deletes {
+		if status, resp := s.do(t, alice, http.MethodDelete, path, nil); status != http.StatusForbidden {
+			t.Errorf("non-admin DELETE %s = %d %s, want %d", path, status, resp, http.StatusForbidden)
+		}
+		if status, resp := s.do(t, admin, http.MethodDelete, path, nil); status != http.StatusOK {
+			t.Errorf("admin DELETE %s = %d %s, want %d", path, status, resp, http.StatusOK)
+		}
+	}
+
+	// the reads are not limited to the admins
+	for _, path := range []string{"/v1/groups", "/v1/groups/ops", "/v1/groups/ops/members", "/v1/roles/auditor"} {
+		if status, resp := s.do(t, alice, http.MethodGet, path, nil); status != http.StatusOK {
+			t.Errorf("non-admin GET %s = %d %s, want %d", path, status, resp, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/audit"
+	"github.com/strayca7/siam/internal/apiserver/options"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/apiserver/store/database"
+	"github.com/strayca7/siam/internal/apiserver/store/memory"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/auth"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// apiServer holds all of the runtime dependencies of siam-apiserver.
+type apiServer struct {
+	opts *options.Options
+	// db is the database of the store, nil if the memory store is used.
+	db    *gorm.DB
+	store store.Factory
+	// auditSink is nil if the audit is disabled.
+	auditSink audit.Sink
+	jwt       *auth.JWT
+	// keys seals the signing keys of the secrets.
+	keys   *auth.KeyCipher
+	engine *gin.Engine
+	server *http.Server
+	// stopping is closed when the server starts to shut down, the long running requests like the watches end on it.
+	stopping chan struct{}
+}
+
+// createAPIServer loads the message catalogs, creates the store and builds the http server.
+func createAPIServer(opts *options.Options) (_ *apiServer, err error) {
+	for _, path := range opts.Server.MessageCatalogs {
+		if err := serrors.LoadCatalog(path); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := opts.Secret.NewKeyCipher()
+	if err != nil {
+		return nil, err
+	}
+	jwt, err := opts.JWT.NewAuthJWT()
+	if err != nil {
+		return nil, err
+	}
+
+	var db *gorm.DB
+	if opts.Store.Type == options.StoreDatabase {
+		if db, err = openDatabase(opts); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				closeDatabase(db)
+			}
+		}()
+		if err := sealLegacySigningKeys(context.Background(), db, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	storeFactory := createStore(db)
+	if err := bootstrapAdmin(context.Background(), storeFactory, opts.Admin); err != nil {
+		return nil, err
+	}
+	auditSink, err := createAuditSink(opts.Audit, storeFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	gin.SetMode(opts.Server.Mode)
+	engine := gin.New()
+	engine.Use(gin.Recovery(), middleware.Trace(), middleware.Logger(), middleware.ReadPrimary())
+
+	s := &apiServer{
+		opts:      opts,
+		db:        db,
+		store:     storeFactory,
+		auditSink: auditSink,
+		jwt:       jwt,
+		keys:      keys,
+		engine:    engine,
+		server: &http.Server{
+			Addr:    opts.Server.Address(),
+			Handler: engine,
+		},
+		stopping: make(chan struct{}),
+	}
+	s.server.RegisterOnShutdown(func() {
+		close(s.stopping)
+	})
+	s.installRoutes()
+
+	return s, nil
+}
+
+// openDatabase connects to the database of the store,
+// the pending migrations are applied if the auto migration is enabled.
+func openDatabase(opts *options.Options) (*gorm.DB, error) {
+	db, err := opts.Database.NewDatabaseCli(logger.L())
+	if err != nil {
+		return nil, err
+	}
+	if opts.Migration.AutoMigrate {
+		m, err := newMigrator(db, opts.Database.Driver)
+		if err != nil {
+			closeDatabase(db)
+			return nil, err
+		}
+		if err := m.Up(context.Background()); err != nil {
+			closeDatabase(db)
+			return nil, fmt.Errorf("migrate database: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// closeDatabase closes the database of a server which failed to be created.
+func closeDatabase(db *gorm.DB) {
+	if err := pkgdatabase.Close(db); err != nil {
+		logger.L().Error("Failed to close the database", zap.Error(err))
+	}
+}
+
+// createStore creates the store on top of the database, or the memory store if db is nil.
+func createStore(db *gorm.DB) store.Factory {
+	if db == nil {
+		logger.L().Warn("The memory store is used, all of the data will be lost when the server exits")
+		return memory.New()
+	}
+	return database.New(db)
+}
+
+// bootstrapAdmin creates the system admin of the options in the default tenant if it does not exist,
+// the existing user is left as it is.
+func bootstrapAdmin(ctx context.Context, s store.Factory, opts *options.AdminOptions) error {
+	if opts.Username == "" {
+		return nil
+	}
+	_, err := s.Users().Get(ctx, opts.Username)
+	if err == nil {
+		return nil
+	}
+	if !serrors.IsCode(err, code.ErrUserNotFound) {
+		return fmt.Errorf("get admin %q: %w", opts.Username, err)
+	}
+
+	hashed, err := auth.Encrypt(opts.Password)
+	if err != nil {
+		return fmt.Errorf("encrypt password of admin %q: %w", opts.Username, err)
+	}
+	admin := &apiv1.User{
+		ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("user-"), Name: opts.Username},
+		Password:   hashed,
+		IsAdmin:    true,
+	}
+	if err := s.Users().Create(ctx, admin); err != nil {
+		return fmt.Errorf("create admin %q: %w", opts.Username, err)
+	}
+	logger.L().Info("Created the system admin", zap.String("username", opts.Username))
+	return nil
+}
+
+// createAuditSink creates the audit sink of the options, nil if the audit is disabled.
+func createAuditSink(opts *options.AuditOptions, store store.Factory) (audit.Sink, error) {
+	if !opts.Enabled {
+		return nil, nil
+	}
+	switch opts.Sink {
+	case options.AuditSinkFile:
+		return audit.NewFileSink(opts.File)
+	case options.AuditSinkLogger:
+		return audit.NewLoggerSink(logger.L()), nil
+	default:
+		return audit.NewStoreSink(store), nil
+	}
+}
+
+// Run starts the http server and blocks until SIGINT or SIGTERM is received,
+// then it shuts the server down gracefully.
+func (s *apiServer) Run() error {
+	healthCtx, stopHealthCheck := context.WithCancel(context.Background())
+	defer stopHealthCheck()
+	if s.db != nil && s.opts.Database.HealthCheckInterval > 0 {
+		go pkgdatabase.HealthCheck(healthCtx, s.db, s.opts.Database.HealthCheckInterval, logger.L())
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.L().Info("Start to listening the incoming requests", zap.String("address", s.server.Addr))
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-errCh:
+		return err
+	case sig := <-quit:
+		logger.L().Info("Shutting down server", zap.String("signal", sig.String()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(s.opts.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown server: %w", err)
+	}
+
+	stopHealthCheck()
+	if closer, ok := s.auditSink.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.L().Error("Failed to close the audit sink", zap.Error(err))
+		}
+	}
+	if err := s.store.Close(); err != nil {
+		logger.L().Error("Failed to close the store", zap.Error(err))
+	}
+	logger.L().Info("Server exited")
+	return nil
+}
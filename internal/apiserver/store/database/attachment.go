@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type policyAttachments struct {
+	db *gorm.DB
+}
+
+func (p *policyAttachments) Create(ctx context.Context, attachment *apiv1.PolicyAttachment) error {
+	attachment.Tenant = store.TenantFromContext(ctx)
+	if err := p.db.WithContext(ctx).Create(attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrAttachmentAlreadyExists, "policy %q of user %q is already attached to %s %q",
+				attachment.PolicyName, attachment.PolicyOwner, attachment.PrincipalKind, attachment.PrincipalName)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "attach policy %q to %s %q",
+			attachment.PolicyName, attachment.PrincipalKind, attachment.PrincipalName)
+	}
+	return nil
+}
+
+func (p *policyAttachments) Delete(ctx context.Context, principal model.Principal, owner, name string) error {
+	result := scoped(ctx, p.db).
+		Where("principal_kind = ? AND principal_name = ? AND policy_owner = ? AND policy_name = ?",
+			principal.Kind, principal.Name, owner, name).
+		Delete(&apiv1.PolicyAttachment{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "detach policy %q from %s %q",
+			name, principal.Kind, principal.Name)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrAttachmentNotFound, "policy %q of user %q is not attached to %s %q",
+			name, owner, principal.Kind, principal.Name)
+	}
+	return nil
+}
+
+func (p *policyAttachments) DeleteCollection(ctx context.Context, principal model.Principal) error {
+	err := scoped(ctx, p.db).
+		Where("principal_kind = ? AND principal_name = ?", principal.Kind, principal.Name).
+		Delete(&apiv1.PolicyAttachment{}).Error
+	if err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "detach policies from %s %q", principal.Kind, principal.Name)
+	}
+	return nil
+}
+
+func (p *policyAttachments) DeletePolicy(ctx context.Context, owner, name string) error {
+	db := scoped(ctx, p.db).Where("policy_owner = ?", owner)
+	if name != "" {
+		db = db.Where("policy_name = ?", name)
+	}
+	if err := db.Delete(&apiv1.PolicyAttachment{}).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "detach policies of user %q", owner)
+	}
+	return nil
+}
+
+func (p *policyAttachments) List(ctx context.Context, principal model.Principal, opts store.ListOptions) (
+	*apiv1.PolicyAttachmentList, error,
+) {
+	db := scoped(ctx, p.db).Where("principal_kind = ? AND principal_name = ?", principal.Kind, principal.Name)
+	db, err := selected(db, &apiv1.PolicyAttachment{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select policy attachments")
+	}
+	list := &apiv1.PolicyAttachmentList{Items: []*apiv1.PolicyAttachment{}}
+	if err := db.Model(&apiv1.PolicyAttachment{}).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count policies attached to %s %q", principal.Kind, principal.Name)
+	}
+	if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list policies attached to %s %q", principal.Kind, principal.Name)
+	}
+	return list, nil
+}
+
+func (p *policyAttachments) ListPolicies(ctx context.Context, principals []model.Principal) ([]*apiv1.Policy, error) {
+	policies := []*apiv1.Policy{}
+	if len(principals) == 0 {
+		return policies, nil
+	}
+
+	// the names are grouped by the kind to keep the condition short
+	var kinds []string
+	names := map[string][]string{}
+	for _, pr := range principals {
+		if _, ok := names[pr.Kind]; !ok {
+			kinds = append(kinds, pr.Kind)
+		}
+		names[pr.Kind] = append(names[pr.Kind], pr.Name)
+	}
+	conds := make([]string, 0, len(kinds))
+	args := make([]any, 0, 2*len(kinds))
+	for _, kind := range kinds {
+		conds = append(conds, "(a.principal_kind = ? AND a.principal_name IN ?)")
+		args = append(args, kind, names[kind])
+	}
+
+	// EXISTS instead of JOIN lists a policy only once even if it is attached to several principals
+	err := scoped(ctx, p.db).
+		Where("EXISTS (SELECT 1 FROM policy_attachments a"+
+			" WHERE a.tenant = policies.tenant"+
+			" AND a.policy_owner = policies.username AND a.policy_name = policies.name"+
+			" AND ("+strings.Join(conds, " OR ")+"))", args...).
+		Order("id").
+		Find(&policies).Error
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list attached policies")
+	}
+	return policies, nil
+}
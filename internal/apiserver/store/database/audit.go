@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// likeEscaper escapes the wildcards of LIKE with `!`, which needs no escaping in the string literals of any database.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+type auditEvents struct {
+	db *gorm.DB
+}
+
+func (a *auditEvents) Create(ctx context.Context, event *apiv1.AuditEvent) error {
+	event.Tenant = store.TenantFromContext(ctx)
+	if err := a.db.WithContext(ctx).Create(event).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "create audit event of %q", event.Action)
+	}
+	return nil
+}
+
+func (a *auditEvents) List(ctx context.Context, filter store.AuditFilter, opts store.ListOptions,
+) (*apiv1.AuditEventList, error) {
+	db, err := selected(scoped(ctx, a.db), &apiv1.AuditEvent{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select audit events")
+	}
+	list := &apiv1.AuditEventList{Items: []*apiv1.AuditEvent{}}
+	if err := where(db.Model(&apiv1.AuditEvent{}), filter).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count audit events")
+	}
+	query := where(db, filter).Order("id DESC").Offset(opts.Offset)
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	if err := query.Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list audit events")
+	}
+	return list, nil
+}
+
+// where applies the audit filter to the query.
+func where(db *gorm.DB, filter store.AuditFilter) *gorm.DB {
+	if filter.Actor != "" {
+		db = db.Where("actor = ?", filter.Actor)
+	}
+	if filter.Resource != "" {
+		prefix := strings.TrimSuffix(filter.Resource, "/")
+		db = db.Where("(resource = ? OR resource LIKE ? ESCAPE '!')", prefix, likeEscaper.Replace(prefix)+"/%")
+	}
+	if !filter.Since.IsZero() {
+		db = db.Where("created_at >= ?", filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		db = db.Where("created_at < ?", filter.Until.UTC())
+	}
+	return db
+}
@@ -0,0 +1,133 @@
+// Package database implements the store.Factory with gorm on top of package database,
+// which works on all of the supported database drivers.
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/serrors"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+type datastore struct {
+	db *gorm.DB
+	// watcher is shared by the datastores of the transactions.
+	watcher *watcher
+	// nextNonceSweep is shared by the datastores of the transactions, see nonces.
+	nextNonceSweep *atomic.Int64
+}
+
+var _ store.Factory = (*datastore)(nil)
+
+// New creates a store.Factory with the gorm db instance.
+func New(db *gorm.DB) store.Factory {
+	return &datastore{db: db, watcher: newWatcher(db), nextNonceSweep: &atomic.Int64{}}
+}
+
+func (ds *datastore) Tenants() store.TenantStore {
+	return &tenants{db: ds.db}
+}
+
+func (ds *datastore) Users() store.UserStore {
+	return &users{db: ds.db}
+}
+
+func (ds *datastore) Secrets() store.SecretStore {
+	return &secrets{db: ds.db}
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return &policies{db: ds.db}
+}
+
+func (ds *datastore) Groups() store.GroupStore {
+	return &groups{db: ds.db}
+}
+
+func (ds *datastore) GroupMembers() store.GroupMemberStore {
+	return &groupMembers{db: ds.db}
+}
+
+func (ds *datastore) Roles() store.RoleStore {
+	return &roles{db: ds.db}
+}
+
+func (ds *datastore) PolicyAttachments() store.PolicyAttachmentStore {
+	return &policyAttachments{db: ds.db}
+}
+
+func (ds *datastore) AuditEvents() store.AuditEventStore {
+	return &auditEvents{db: ds.db}
+}
+
+func (ds *datastore) WatchEvents() store.WatchEventStore {
+	return &watchEvents{db: ds.db, watcher: ds.watcher}
+}
+
+func (ds *datastore) Nonces() store.NonceStore {
+	return &nonces{db: ds.db, nextSweep: ds.nextNonceSweep}
+}
+
+func (ds *datastore) Tx(ctx context.Context, fn func(tx store.Factory) error) error {
+	return ds.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&datastore{db: tx, watcher: ds.watcher, nextNonceSweep: ds.nextNonceSweep})
+	})
+}
+
+func (ds *datastore) Close() error {
+	ds.watcher.close()
+	return pkgdatabase.Close(ds.db)
+}
+
+// scoped returns a session of the db scoped to the tenant of the context, see store.WithTenant.
+// The session is reusable, e.g. for both the count and the page of a list.
+func scoped(ctx context.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(ctx).Where("tenant = ?", store.TenantFromContext(ctx)).Session(&gorm.Session{})
+}
+
+// paginate applies the list options to the query.
+func paginate(db *gorm.DB, opts store.ListOptions) *gorm.DB {
+	db = db.Order("id").Offset(opts.Offset)
+	if opts.Limit > 0 {
+		db = db.Limit(opts.Limit)
+	}
+	return db
+}
+
+// update saves all of the fields of the object of the metadata if its resource version is not changed since
+// the object was read, the resource version is set to the given version. It reports false if the object
+// has been changed or deleted in the meantime.
+func update(ctx context.Context, db *gorm.DB, obj any, meta *metav1.ObjectMeta, version uint64) (bool, error) {
+	old := meta.ResourceVersion
+	meta.ResourceVersion = version
+	// the update of the selected fields never falls back to the insert like Save
+	result := db.WithContext(ctx).Model(obj).Where("resource_version = ?", old).Select("*").Updates(obj)
+	if result.Error != nil || result.RowsAffected == 0 {
+		meta.ResourceVersion = old
+		return false, result.Error
+	}
+	return true, nil
+}
+
+// transaction runs fn in a transaction of the db, fn must return the coded errors.
+// The failures to begin and commit the transaction are coded as code.ErrDatabase.
+func transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	failed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			failed = true
+			return err
+		}
+		return nil
+	})
+	if err != nil && !failed {
+		return serrors.WrapC(err, code.ErrDatabase, "commit transaction")
+	}
+	return err
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type groups struct {
+	db *gorm.DB
+}
+
+func (g *groups) Create(ctx context.Context, group *apiv1.Group) error {
+	group.Tenant = store.TenantFromContext(ctx)
+	if err := g.db.WithContext(ctx).Create(group).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrGroupAlreadyExists, "group %q already exists", group.Name)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "create group %q", group.Name)
+	}
+	return nil
+}
+
+func (g *groups) Get(ctx context.Context, name string) (*apiv1.Group, error) {
+	group := &apiv1.Group{}
+	if err := scoped(ctx, g.db).Where("name = ?", name).First(group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrGroupNotFound, "group %q not found", name)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get group %q", name)
+	}
+	return group, nil
+}
+
+func (g *groups) Update(ctx context.Context, group *apiv1.Group) error {
+	group.Tenant = store.TenantFromContext(ctx)
+	if err := g.db.WithContext(ctx).Save(group).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "update group %q", group.Name)
+	}
+	return nil
+}
+
+func (g *groups) Delete(ctx context.Context, name string) error {
+	result := scoped(ctx, g.db).Where("name = ?", name).Delete(&apiv1.Group{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "delete group %q", name)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrGroupNotFound, "group %q not found", name)
+	}
+	return nil
+}
+
+func (g *groups) List(ctx context.Context, opts store.ListOptions) (*apiv1.GroupList, error) {
+	db, err := selected(scoped(ctx, g.db), &apiv1.Group{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select groups")
+	}
+	list := &apiv1.GroupList{Items: []*apiv1.Group{}}
+	if err := db.Model(&apiv1.Group{}).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count groups")
+	}
+	if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list groups")
+	}
+	return list, nil
+}
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type groupMembers struct {
+	db *gorm.DB
+}
+
+func (g *groupMembers) Create(ctx context.Context, member *apiv1.GroupMember) error {
+	member.Tenant = store.TenantFromContext(ctx)
+	if err := g.db.WithContext(ctx).Create(member).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrGroupMemberAlreadyExists, "user %q is already a member of group %q",
+				member.Username, member.Group)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "add user %q to group %q", member.Username, member.Group)
+	}
+	return nil
+}
+
+func (g *groupMembers) Delete(ctx context.Context, group, username string) error {
+	result := scoped(ctx, g.db).
+		Where("group_name = ? AND username = ?", group, username).
+		Delete(&apiv1.GroupMember{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "remove user %q from group %q", username, group)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrGroupMemberNotFound, "user %q is not a member of group %q", username, group)
+	}
+	return nil
+}
+
+func (g *groupMembers) DeleteCollection(ctx context.Context, group string) error {
+	if err := scoped(ctx, g.db).Where("group_name = ?", group).Delete(&apiv1.GroupMember{}).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "delete members of group %q", group)
+	}
+	return nil
+}
+
+func (g *groupMembers) DeleteUser(ctx context.Context, username string) error {
+	if err := scoped(ctx, g.db).Where("username = ?", username).Delete(&apiv1.GroupMember{}).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "remove user %q from groups", username)
+	}
+	return nil
+}
+
+func (g *groupMembers) List(ctx context.Context, group string, opts store.ListOptions) (*apiv1.GroupMemberList, error) {
+	db, err := selected(scoped(ctx, g.db), &apiv1.GroupMember{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select group members")
+	}
+	list := &apiv1.GroupMemberList{Items: []*apiv1.GroupMember{}}
+	if err := db.Model(&apiv1.GroupMember{}).Where("group_name = ?", group).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count members of group %q", group)
+	}
+	if err := paginate(db.Where("group_name = ?", group), opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list members of group %q", group)
+	}
+	return list, nil
+}
+
+func (g *groupMembers) ListGroups(ctx context.Context, username string) ([]string, error) {
+	groups := []string{}
+	err := scoped(ctx, g.db).Model(&apiv1.GroupMember{}).
+		Where("username = ?", username).
+		Order("group_name").
+		Pluck("group_name", &groups).Error
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list groups of user %q", username)
+	}
+	return groups, nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// nonceSweepInterval is how often every instance deletes the expired nonces.
+const nonceSweepInterval = time.Minute
+
+type nonces struct {
+	db *gorm.DB
+	// nextSweep is the time in unix nanoseconds the expired nonces are deleted at next.
+	nextSweep *atomic.Int64
+}
+
+func (n *nonces) Add(ctx context.Context, nonce string, expiresAt, now time.Time) (bool, error) {
+	db := n.db.WithContext(ctx)
+	next := n.nextSweep.Load()
+	if now.UnixNano() > next && n.nextSweep.CompareAndSwap(next, now.Add(nonceSweepInterval).UnixNano()) {
+		if err := db.Where("expires_at < ?", now).Delete(&model.Nonce{}).Error; err != nil {
+			return false, serrors.WrapC(err, code.ErrDatabase, "delete expired nonces")
+		}
+	}
+
+	err := db.Create(&model.Nonce{Nonce: nonce, ExpiresAt: expiresAt}).Error
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return false, serrors.WrapC(err, code.ErrDatabase, "create nonce")
+	}
+	// the nonce may have expired but not been swept yet
+	result := db.Model(&model.Nonce{}).Where("nonce = ? AND expires_at < ?", nonce, now).
+		Update("expires_at", expiresAt)
+	if result.Error != nil {
+		return false, serrors.WrapC(result.Error, code.ErrDatabase, "update nonce")
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+type policies struct {
+	db *gorm.DB
+}
+
+func (p *policies) Create(ctx context.Context, pol *apiv1.Policy) error {
+	pol.Tenant = store.TenantFromContext(ctx)
+	return transaction(ctx, p.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		pol.ResourceVersion = version
+		if err := tx.Create(pol).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return serrors.WithCodef(code.ErrPolicyAlreadyExists, "policy %q of user %q already exists",
+					pol.Name, pol.Username)
+			}
+			return serrors.WrapC(err, code.ErrDatabase, "create policy %q", pol.Name)
+		}
+		return recordPolicies(tx, metav1.Added, pol)
+	})
+}
+
+func (p *policies) Get(ctx context.Context, username, name string) (*apiv1.Policy, error) {
+	pol := &apiv1.Policy{}
+	err := scoped(ctx, p.db).
+		Where("username = ? AND name = ?", username, name).
+		First(pol).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrPolicyNotFound, "policy %q of user %q not found", name, username)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get policy %q", name)
+	}
+	return pol, nil
+}
+
+func (p *policies) Update(ctx context.Context, pol *apiv1.Policy) error {
+	pol.Tenant = store.TenantFromContext(ctx)
+	return transaction(ctx, p.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		ok, err := update(ctx, tx, pol, &pol.ObjectMeta, version)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "update policy %q", pol.Name)
+		}
+		if !ok {
+			return serrors.WithCodef(code.ErrConflict, "policy %q of user %q has been modified", pol.Name, pol.Username)
+		}
+		return recordPolicies(tx, metav1.Modified, pol)
+	})
+}
+
+func (p *policies) Delete(ctx context.Context, username, name string) error {
+	return transaction(ctx, p.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		pol := &apiv1.Policy{}
+		if err := scoped(ctx, tx).Where("username = ? AND name = ?", username, name).First(pol).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return serrors.WithCodef(code.ErrPolicyNotFound, "policy %q of user %q not found", name, username)
+			}
+			return serrors.WrapC(err, code.ErrDatabase, "get policy %q", name)
+		}
+		if err := tx.Delete(pol).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "delete policy %q", name)
+		}
+		pol.ResourceVersion = version
+		return recordPolicies(tx, metav1.Deleted, pol)
+	})
+}
+
+func (p *policies) DeleteCollection(ctx context.Context, username string) error {
+	return transaction(ctx, p.db, func(tx *gorm.DB) error {
+		// lock the versions before reading the policies, so that they are not changed until they are deleted
+		if _, err := reserve(tx, 0); err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "lock resource version")
+		}
+		var deleted []*apiv1.Policy
+		if err := scoped(ctx, tx).Where("username = ?", username).Order("id").Find(&deleted).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "list policies of user %q", username)
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		last, err := reserve(tx, len(deleted))
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource versions")
+		}
+		if err := tx.Delete(deleted).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "delete policies of user %q", username)
+		}
+		for i, pol := range deleted {
+			pol.ResourceVersion = last - uint64(len(deleted)-1-i)
+		}
+		return recordPolicies(tx, metav1.Deleted, deleted...)
+	})
+}
+
+func (p *policies) List(ctx context.Context, username string, opts store.ListOptions) (*apiv1.PolicyList, error) {
+	list := &apiv1.PolicyList{Items: []*apiv1.Policy{}}
+	// the version, the count and the items are read from the same database, the list is at least as new as
+	// its version, so a watch from the version may repeat but never miss the changes
+	err := pkgdatabase.Snapshot(ctx, p.db, func(tx *gorm.DB) error {
+		db, err := selected(scoped(ctx, tx), &apiv1.Policy{}, opts)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "select policies")
+		}
+		if username != "" {
+			db = db.Where("username = ?", username)
+		}
+		if list.ResourceVersion, err = latest(tx); err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "get latest resource version")
+		}
+		if err := db.Model(&apiv1.Policy{}).Count(&list.TotalCount).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "count policies of user %q", username)
+		}
+		if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "list policies of user %q", username)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// recordPolicies records the watch events of the changes of the policies in the transaction tx.
+func recordPolicies(tx *gorm.DB, typ metav1.EventType, pols ...*apiv1.Policy) error {
+	events := make([]*model.WatchEvent, 0, len(pols))
+	for _, pol := range pols {
+		event, err := model.NewWatchEvent(pol.Tenant, model.KindPolicy, typ, pol.ResourceVersion, pol)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrUnknown, "encode watch event of policy %q", pol.Name)
+		}
+		events = append(events, event)
+	}
+	if err := record(tx, events...); err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "record watch events of policies")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type roles struct {
+	db *gorm.DB
+}
+
+func (r *roles) Create(ctx context.Context, role *apiv1.Role) error {
+	role.Tenant = store.TenantFromContext(ctx)
+	if err := r.db.WithContext(ctx).Create(role).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrRoleAlreadyExists, "role %q already exists", role.Name)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "create role %q", role.Name)
+	}
+	return nil
+}
+
+func (r *roles) Get(ctx context.Context, name string) (*apiv1.Role, error) {
+	role := &apiv1.Role{}
+	if err := scoped(ctx, r.db).Where("name = ?", name).First(role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrRoleNotFound, "role %q not found", name)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get role %q", name)
+	}
+	return role, nil
+}
+
+func (r *roles) Update(ctx context.Context, role *apiv1.Role) error {
+	role.Tenant = store.TenantFromContext(ctx)
+	if err := r.db.WithContext(ctx).Save(role).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "update role %q", role.Name)
+	}
+	return nil
+}
+
+func (r *roles) Delete(ctx context.Context, name string) error {
+	result := scoped(ctx, r.db).Where("name = ?", name).Delete(&apiv1.Role{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "delete role %q", name)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrRoleNotFound, "role %q not found", name)
+	}
+	return nil
+}
+
+func (r *roles) List(ctx context.Context, opts store.ListOptions) (*apiv1.RoleList, error) {
+	db, err := selected(scoped(ctx, r.db), &apiv1.Role{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select roles")
+	}
+	list := &apiv1.RoleList{Items: []*apiv1.Role{}}
+	if err := db.Model(&apiv1.Role{}).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count roles")
+	}
+	if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list roles")
+	}
+	return list, nil
+}
+
+// Untrust scans all of the roles, the trusted principals are stored as JSON which is queried
+// differently by every database driver, and there are only a few roles.
+func (r *roles) Untrust(ctx context.Context, principal model.Principal) error {
+	var all []*apiv1.Role
+	if err := scoped(ctx, r.db).Order("id").Find(&all).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "list roles")
+	}
+	for _, role := range all {
+		if !untrust(role, principal) {
+			continue
+		}
+		if err := r.Update(ctx, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// untrust removes the principal from the trusted principals of the role, and reports whether it is removed.
+func untrust(role *apiv1.Role, principal model.Principal) bool {
+	trusted := &role.TrustedUsers
+	if principal.Kind == apiv1.PrincipalGroup {
+		trusted = &role.TrustedGroups
+	}
+	n := len(*trusted)
+	*trusted = slices.DeleteFunc(*trusted, func(name string) bool { return name == principal.Name })
+	return len(*trusted) != n
+}
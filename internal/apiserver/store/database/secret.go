@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+type secrets struct {
+	db *gorm.DB
+}
+
+func (s *secrets) Create(ctx context.Context, secret *apiv1.Secret) error {
+	secret.Tenant = store.TenantFromContext(ctx)
+	return transaction(ctx, s.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		secret.ResourceVersion = version
+		if err := tx.Create(secret).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "create secret for user %q", secret.Username)
+		}
+		return recordSecrets(tx, metav1.Added, secret)
+	})
+}
+
+func (s *secrets) Get(ctx context.Context, username, accessKey string) (*apiv1.Secret, error) {
+	secret := &apiv1.Secret{}
+	err := scoped(ctx, s.db).
+		Where("username = ? AND access_key = ?", username, accessKey).
+		First(secret).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrSecretNotFound, "secret %q of user %q not found", accessKey, username)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get secret %q", accessKey)
+	}
+	return secret, nil
+}
+
+func (s *secrets) GetByAccessKey(ctx context.Context, accessKey string) (*apiv1.Secret, error) {
+	secret := &apiv1.Secret{}
+	if err := s.db.WithContext(ctx).Where("access_key = ?", accessKey).First(secret).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrSecretNotFound, "secret %q not found", accessKey)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get secret %q", accessKey)
+	}
+	return secret, nil
+}
+
+func (s *secrets) Update(ctx context.Context, secret *apiv1.Secret) error {
+	secret.Tenant = store.TenantFromContext(ctx)
+	return transaction(ctx, s.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		ok, err := update(ctx, tx, secret, &secret.ObjectMeta, version)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "update secret %q", secret.AccessKey)
+		}
+		if !ok {
+			return serrors.WithCodef(code.ErrConflict, "secret %q has been modified", secret.AccessKey)
+		}
+		return recordSecrets(tx, metav1.Modified, secret)
+	})
+}
+
+func (s *secrets) Delete(ctx context.Context, username, accessKey string) error {
+	return transaction(ctx, s.db, func(tx *gorm.DB) error {
+		version, err := reserve(tx, 1)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource version")
+		}
+		secret := &apiv1.Secret{}
+		err = scoped(ctx, tx).Where("username = ? AND access_key = ?", username, accessKey).First(secret).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return serrors.WithCodef(code.ErrSecretNotFound, "secret %q of user %q not found", accessKey, username)
+			}
+			return serrors.WrapC(err, code.ErrDatabase, "get secret %q", accessKey)
+		}
+		if err := tx.Delete(secret).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "delete secret %q", accessKey)
+		}
+		secret.ResourceVersion = version
+		return recordSecrets(tx, metav1.Deleted, secret)
+	})
+}
+
+func (s *secrets) DeleteCollection(ctx context.Context, username string) error {
+	return transaction(ctx, s.db, func(tx *gorm.DB) error {
+		// lock the versions before reading the secrets, so that they are not changed until they are deleted
+		if _, err := reserve(tx, 0); err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "lock resource version")
+		}
+		var deleted []*apiv1.Secret
+		if err := scoped(ctx, tx).Where("username = ?", username).Order("id").Find(&deleted).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "list secrets of user %q", username)
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		last, err := reserve(tx, len(deleted))
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "reserve resource versions")
+		}
+		if err := tx.Delete(deleted).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "delete secrets of user %q", username)
+		}
+		for i, secret := range deleted {
+			secret.ResourceVersion = last - uint64(len(deleted)-1-i)
+		}
+		return recordSecrets(tx, metav1.Deleted, deleted...)
+	})
+}
+
+func (s *secrets) List(ctx context.Context, username string, opts store.ListOptions) (*apiv1.SecretList, error) {
+	list := &apiv1.SecretList{Items: []*apiv1.Secret{}}
+	// the version, the count and the items are read from the same database, the list is at least as new as
+	// its version, so a watch from the version may repeat but never miss the changes
+	err := pkgdatabase.Snapshot(ctx, s.db, func(tx *gorm.DB) error {
+		db, err := selected(scoped(ctx, tx), &apiv1.Secret{}, opts)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "select secrets")
+		}
+		if username != "" {
+			db = db.Where("username = ?", username)
+		}
+		if list.ResourceVersion, err = latest(tx); err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "get latest resource version")
+		}
+		if err := db.Model(&apiv1.Secret{}).Count(&list.TotalCount).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "count secrets of user %q", username)
+		}
+		if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+			return serrors.WrapC(err, code.ErrDatabase, "list secrets of user %q", username)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// recordSecrets records the watch events of the changes of the secrets in the transaction tx.
+func recordSecrets(tx *gorm.DB, typ metav1.EventType, secrets ...*apiv1.Secret) error {
+	events := make([]*model.WatchEvent, 0, len(secrets))
+	for _, secret := range secrets {
+		event, err := model.NewWatchEvent(secret.Tenant, model.KindSecret, typ, secret.ResourceVersion, secret)
+		if err != nil {
+			return serrors.WrapC(err, code.ErrUnknown, "encode watch event of secret %q", secret.AccessKey)
+		}
+		events = append(events, event)
+	}
+	if err := record(tx, events...); err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "record watch events of secrets")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/staging/src/apimachinery/selector"
+)
+
+// selected applies the selectors of the list options to the query of the model. The labels are read from
+// the JSON `labels` column, and the fields are the columns of the struct fields of the same JSON names.
+// The returned session is reusable like the one of scoped.
+func selected(db *gorm.DB, model any, opts store.ListOptions) (*gorm.DB, error) {
+	if opts.LabelSelector.Empty() && opts.FieldSelector.Empty() {
+		return db, nil
+	}
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+
+	labeled := stmt.Schema.LookUpField("labels") != nil
+	for _, r := range opts.LabelSelector {
+		if !labeled {
+			// the objects without labels satisfy only the requirements of the absent keys
+			if !r.Matches(nil) {
+				db = db.Where("1 = 0")
+			}
+			continue
+		}
+		db = db.Where(condition(labelOf(db, r.Key), r))
+	}
+	for _, r := range opts.FieldSelector {
+		column := columnOf(stmt.Schema, r.Key)
+		if column == "" {
+			return nil, fmt.Errorf("field %q of %s has no column", r.Key, stmt.Schema.Name)
+		}
+		db = db.Where(condition(clause.Column{Name: column}, r))
+	}
+	return db.Session(&gorm.Session{}), nil
+}
+
+// labelOf returns the expression of the value of the label, which is NULL if the label does not exist.
+func labelOf(db *gorm.DB, key string) clause.Expr {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return gorm.Expr("labels ->> ?", key)
+	case "mysql":
+		return gorm.Expr("JSON_UNQUOTE(JSON_EXTRACT(labels, ?))", `$."`+key+`"`)
+	default:
+		return gorm.Expr("json_extract(labels, ?)", `$."`+key+`"`)
+	}
+}
+
+// columnOf returns the column of the field of the JSON name, it is empty if there is no such field.
+func columnOf(s *schema.Schema, name string) string {
+	for _, f := range s.Fields {
+		if json, _, _ := strings.Cut(f.Tag.Get("json"), ","); json == name {
+			return f.DBName
+		}
+	}
+	return ""
+}
+
+// condition returns the SQL condition of the requirement on the expression, which is NULL if the key does not exist.
+func condition(expr any, r selector.Requirement) clause.Expr {
+	switch r.Operator {
+	case selector.Equals:
+		return gorm.Expr("? = ?", expr, r.Values[0])
+	case selector.NotEquals:
+		return gorm.Expr("(? IS NULL OR ? <> ?)", expr, expr, r.Values[0])
+	case selector.In:
+		return gorm.Expr("? IN ?", expr, r.Values)
+	case selector.NotIn:
+		return gorm.Expr("(? IS NULL OR ? NOT IN ?)", expr, expr, r.Values)
+	case selector.Exists:
+		return gorm.Expr("? IS NOT NULL", expr)
+	default:
+		return gorm.Expr("? IS NULL", expr)
+	}
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type tenants struct {
+	db *gorm.DB
+}
+
+func (t *tenants) Create(ctx context.Context, tenant *apiv1.Tenant) error {
+	if err := t.db.WithContext(ctx).Create(tenant).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrTenantAlreadyExists, "tenant %q already exists", tenant.Name)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "create tenant %q", tenant.Name)
+	}
+	return nil
+}
+
+func (t *tenants) Get(ctx context.Context, name string) (*apiv1.Tenant, error) {
+	tenant := &apiv1.Tenant{}
+	if err := t.db.WithContext(ctx).Where("name = ?", name).First(tenant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrTenantNotFound, "tenant %q not found", name)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get tenant %q", name)
+	}
+	return tenant, nil
+}
+
+func (t *tenants) Update(ctx context.Context, tenant *apiv1.Tenant) error {
+	if err := t.db.WithContext(ctx).Save(tenant).Error; err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "update tenant %q", tenant.Name)
+	}
+	return nil
+}
+
+func (t *tenants) Delete(ctx context.Context, name string) error {
+	result := t.db.WithContext(ctx).Where("name = ?", name).Delete(&apiv1.Tenant{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "delete tenant %q", name)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrTenantNotFound, "tenant %q not found", name)
+	}
+	return nil
+}
+
+func (t *tenants) List(ctx context.Context, opts store.ListOptions) (*apiv1.TenantList, error) {
+	db, err := selected(t.db.WithContext(ctx), &apiv1.Tenant{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select tenants")
+	}
+	list := &apiv1.TenantList{Items: []*apiv1.Tenant{}}
+	if err := db.Model(&apiv1.Tenant{}).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count tenants")
+	}
+	if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list tenants")
+	}
+	return list, nil
+}
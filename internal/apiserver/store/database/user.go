@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type users struct {
+	db *gorm.DB
+}
+
+func (u *users) Create(ctx context.Context, user *apiv1.User) error {
+	user.Tenant = store.TenantFromContext(ctx)
+	user.ResourceVersion = 1
+	if err := u.db.WithContext(ctx).Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return serrors.WithCodef(code.ErrUserAlreadyExists, "user %q already exists", user.Name)
+		}
+		return serrors.WrapC(err, code.ErrDatabase, "create user %q", user.Name)
+	}
+	return nil
+}
+
+func (u *users) Get(ctx context.Context, name string) (*apiv1.User, error) {
+	return u.get(scoped(ctx, u.db), name)
+}
+
+func (u *users) Lock(ctx context.Context, name string) (*apiv1.User, error) {
+	return u.get(scoped(ctx, u.db).Clauses(clause.Locking{Strength: "UPDATE"}), name)
+}
+
+func (u *users) get(db *gorm.DB, name string) (*apiv1.User, error) {
+	user := &apiv1.User{}
+	if err := db.Where("name = ?", name).First(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, serrors.WithCodef(code.ErrUserNotFound, "user %q not found", name)
+		}
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get user %q", name)
+	}
+	return user, nil
+}
+
+func (u *users) Update(ctx context.Context, user *apiv1.User) error {
+	user.Tenant = store.TenantFromContext(ctx)
+	ok, err := update(ctx, u.db, user, &user.ObjectMeta, user.ResourceVersion+1)
+	if err != nil {
+		return serrors.WrapC(err, code.ErrDatabase, "update user %q", user.Name)
+	}
+	if !ok {
+		return serrors.WithCodef(code.ErrConflict, "user %q has been modified", user.Name)
+	}
+	return nil
+}
+
+func (u *users) Delete(ctx context.Context, name string) error {
+	result := scoped(ctx, u.db).Where("name = ?", name).Delete(&apiv1.User{})
+	if result.Error != nil {
+		return serrors.WrapC(result.Error, code.ErrDatabase, "delete user %q", name)
+	}
+	if result.RowsAffected == 0 {
+		return serrors.WithCodef(code.ErrUserNotFound, "user %q not found", name)
+	}
+	return nil
+}
+
+func (u *users) List(ctx context.Context, opts store.ListOptions) (*apiv1.UserList, error) {
+	db, err := selected(scoped(ctx, u.db), &apiv1.User{}, opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "select users")
+	}
+	list := &apiv1.UserList{Items: []*apiv1.User{}}
+	if err := db.Model(&apiv1.User{}).Count(&list.TotalCount).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "count users")
+	}
+	if err := paginate(db, opts).Find(&list.Items).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list users")
+	}
+	return list, nil
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store/notifier"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+const (
+	// maxWatchEvents is the number of the watch events kept, the older events are dropped.
+	maxWatchEvents = 10000
+	// watchChannel is the Postgres channel notified of the new watch events.
+	watchChannel = "siam_watch"
+	// pollInterval is the interval to check for the new watch events on the databases without LISTEN/NOTIFY.
+	pollInterval = time.Second
+	// retryInterval is the interval to listen again after the connection of LISTEN is lost.
+	retryInterval = 5 * time.Second
+)
+
+// reserve reserves n resource versions for the changes of the transaction tx and returns the last one.
+// The counter of the versions is locked until the end of the transaction, so that the changes are committed
+// in the order of their versions. Reserving no versions only locks the counter.
+//
+// The counter is a single row of all of the tenants, so the transactions which change the watched resources
+// are serialized on it: the throughput of these writes is bounded by one commit, including its round trips,
+// at a time across the whole server, and a slow transaction holding the lock stalls the others. A sequence
+// would not block, but its versions are not committed in order, so a watcher could skip a change committed
+// after a greater version had been seen. The transactions which reserve the versions must be kept short.
+func reserve(tx *gorm.DB, n int) (uint64, error) {
+	if err := tx.Exec("UPDATE resource_versions SET version = version + ? WHERE id = 1", n).Error; err != nil {
+		return 0, err
+	}
+	return latest(tx)
+}
+
+// latest returns the last reserved resource version.
+func latest(db *gorm.DB) (uint64, error) {
+	var version uint64
+	if err := db.Raw("SELECT version FROM resource_versions WHERE id = 1").Scan(&version).Error; err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// record records the watch events of the changes in the transaction tx and drops the oldest events.
+// The watchers of Postgres are notified when the transaction is committed.
+func record(tx *gorm.DB, events ...*model.WatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(events).Error; err != nil {
+		return err
+	}
+	last := events[len(events)-1].ResourceVersion
+	if last > maxWatchEvents {
+		if err := tx.Where("resource_version <= ?", last-maxWatchEvents).Delete(&model.WatchEvent{}).Error; err != nil {
+			return err
+		}
+	}
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Exec("SELECT pg_notify(?, ?)", watchChannel, fmt.Sprint(last)).Error
+	}
+	return nil
+}
+
+type watchEvents struct {
+	db      *gorm.DB
+	watcher *watcher
+}
+
+func (w *watchEvents) List(ctx context.Context, kind string, after uint64, limit int) ([]*model.WatchEvent, error) {
+	db := w.db.WithContext(ctx)
+	events := []*model.WatchEvent{}
+	query := scoped(ctx, w.db).Where("kind = ? AND resource_version > ?", kind, after).Order("resource_version")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&events).Error; err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "list watch events of %s", kind)
+	}
+
+	// the events are dropped from the oldest, so they are all kept if the oldest one is checked after the list
+	var oldest sql.NullInt64
+	if err := db.Model(&model.WatchEvent{}).Select("MIN(resource_version)").Row().Scan(&oldest); err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabase, "get oldest watch event")
+	}
+	next := uint64(oldest.Int64)
+	if !oldest.Valid {
+		version, err := latest(db)
+		if err != nil {
+			return nil, serrors.WrapC(err, code.ErrDatabase, "get latest resource version")
+		}
+		next = version + 1
+	}
+	if after+1 < next {
+		return nil, serrors.WithCodef(code.ErrResourceVersionTooOld,
+			"resource version %d is too old, the oldest available version is %d", after, next-1)
+	}
+	return events, nil
+}
+
+func (w *watchEvents) Latest(ctx context.Context) (uint64, error) {
+	version, err := latest(w.db.WithContext(ctx))
+	if err != nil {
+		return 0, serrors.WrapC(err, code.ErrDatabase, "get latest resource version")
+	}
+	return version, nil
+}
+
+func (w *watchEvents) Subscribe() (<-chan struct{}, func()) {
+	return w.watcher.subscribe()
+}
+
+// watcher watches the database for the new watch events and notifies the subscribers in the process.
+// It listens to the notifications of Postgres on a dedicated connection of the primary database,
+// and polls the latest resource version on the other databases. It starts with the first subscriber.
+type watcher struct {
+	db       *gorm.DB
+	notifier *notifier.Notifier
+	once     sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newWatcher(db *gorm.DB) *watcher {
+	return &watcher{db: db, notifier: notifier.New(), done: make(chan struct{})}
+}
+
+func (w *watcher) subscribe() (<-chan struct{}, func()) {
+	w.once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		go w.run(ctx)
+	})
+	return w.notifier.Subscribe()
+}
+
+// close stops watching the database and closes the channels of the subscribers.
+func (w *watcher) close() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+	if w.cancel != nil {
+		w.cancel()
+	}
+	<-w.done
+	w.notifier.Close()
+}
+
+func (w *watcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	if w.db.Dialector.Name() != "postgres" {
+		w.poll(ctx)
+		return
+	}
+	for {
+		err := w.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.L().Warn("Failed to listen to the watch events, retrying", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// poll notifies the subscribers whenever the latest resource version changes.
+func (w *watcher) poll(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		version, err := latest(w.db.WithContext(pkgdatabase.WithPrimary(ctx)))
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.L().Warn("Failed to poll the watch events", zap.Error(err))
+			}
+			continue
+		}
+		if version != last {
+			last = version
+			w.notifier.Notify()
+		}
+	}
+}
+
+// listen holds a connection of the primary database to LISTEN to the notifications of the watch events,
+// it returns when the connection fails or the context is canceled.
+func (w *watcher) listen(ctx context.Context) error {
+	sqldb, err := w.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqldb.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("connection %T does not support LISTEN", driverConn)
+		}
+		pgconn := stdConn.Conn()
+		if _, err := pgconn.Exec(ctx, "LISTEN "+watchChannel); err != nil {
+			return err
+		}
+		// the events may have been missed while not listening
+		w.notifier.Notify()
+		for {
+			if _, err := pgconn.WaitForNotification(ctx); err != nil {
+				return err
+			}
+			w.notifier.Notify()
+		}
+	})
+}
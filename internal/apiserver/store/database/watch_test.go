@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/apiserver/migrations"
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/database/migrate"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "database")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+
+	exit := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(exit)
+}
+
+// newTestDB opens an SQLite database with all of the migrations applied.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := pkgdatabase.New(&pkgdatabase.Options{
+		Driver: pkgdatabase.SQLite,
+		Path:   filepath.Join(t.TempDir(), "siam.db"),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqldb, err := db.DB()
+	if err != nil {
+		t.Fatalf("get database: %v", err)
+	}
+	t.Cleanup(func() { _ = sqldb.Close() })
+	dialect, err := migrate.LookupDialect(pkgdatabase.SQLite)
+	if err != nil {
+		t.Fatalf("LookupDialect() error = %v", err)
+	}
+	ms, err := migrations.Load(pkgdatabase.SQLite)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := migrate.New(sqldb, dialect, ms).Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestWatchEventsCompaction(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	// write records the events of the versions in a transaction, like the changes of the policies
+	write := func(versions ...uint64) {
+		t.Helper()
+		events := make([]*model.WatchEvent, 0, len(versions))
+		for _, version := range versions {
+			event, err := model.NewWatchEvent(model.DefaultTenant, model.KindPolicy, metav1.Modified, version, struct{}{})
+			if err != nil {
+				t.Fatalf("NewWatchEvent() error = %v", err)
+			}
+			events = append(events, event)
+		}
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return record(tx, events...)
+		}); err != nil {
+			t.Fatalf("record(%v) error = %v", versions, err)
+		}
+	}
+	write(1, 2, 3)
+	// the events up to the version maxWatchEvents before the last one are dropped
+	write(maxWatchEvents + 2)
+
+	w := &watchEvents{db: db}
+	tests := []struct {
+		name   string
+		after  uint64
+		want   []uint64
+		tooOld bool
+	}{
+		{"before the dropped events", 0, nil, true},
+		{"after the first dropped event", 1, nil, true},
+		{"after the dropped events", 2, []uint64{3, maxWatchEvents + 2}, false},
+		{"after the latest event", maxWatchEvents + 2, []uint64{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := w.List(ctx, model.KindPolicy, tt.after, 0)
+			if tt.tooOld {
+				if !serrors.IsCode(err, code.ErrResourceVersionTooOld) {
+					t.Errorf("List() error = %v, want %d", err, code.ErrResourceVersionTooOld)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got := []uint64{}
+			for _, event := range events {
+				got = append(got, event.ResourceVersion)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("List() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// attachmentKey is the unique key of a policy attachment.
+type attachmentKey struct {
+	principal model.Principal
+	policy    policyKey
+}
+
+type policyAttachments struct {
+	ds *datastore
+}
+
+func (p *policyAttachments) Create(ctx context.Context, attachment *apiv1.PolicyAttachment) error {
+	attachment.Tenant = store.TenantFromContext(ctx)
+	return p.ds.write(func(d *data) error {
+		key := attachmentKey{
+			principal: model.Principal{Kind: attachment.PrincipalKind, Name: attachment.PrincipalName},
+			policy:    policyKey{attachment.Tenant, attachment.PolicyOwner, attachment.PolicyName},
+		}
+		if _, ok := d.attachments[key]; ok {
+			return serrors.WithCodef(code.ErrAttachmentAlreadyExists, "policy %q of user %q is already attached to %s %q",
+				attachment.PolicyName, attachment.PolicyOwner, attachment.PrincipalKind, attachment.PrincipalName)
+		}
+		attachment.ID = d.nextID()
+		attachment.CreatedAt = now()
+		stored := *attachment
+		d.attachments[key] = &stored
+		return nil
+	})
+}
+
+func (p *policyAttachments) Delete(ctx context.Context, principal model.Principal, owner, name string) error {
+	key := attachmentKey{principal: principal, policy: policyKey{store.TenantFromContext(ctx), owner, name}}
+	return p.ds.write(func(d *data) error {
+		if _, ok := d.attachments[key]; !ok {
+			return serrors.WithCodef(code.ErrAttachmentNotFound, "policy %q of user %q is not attached to %s %q",
+				name, owner, principal.Kind, principal.Name)
+		}
+		delete(d.attachments, key)
+		return nil
+	})
+}
+
+func (p *policyAttachments) DeleteCollection(ctx context.Context, principal model.Principal) error {
+	tenant := store.TenantFromContext(ctx)
+	return p.ds.write(func(d *data) error {
+		for key := range d.attachments {
+			if key.policy.tenant == tenant && key.principal == principal {
+				delete(d.attachments, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (p *policyAttachments) DeletePolicy(ctx context.Context, owner, name string) error {
+	tenant := store.TenantFromContext(ctx)
+	return p.ds.write(func(d *data) error {
+		for key := range d.attachments {
+			if key.policy.tenant == tenant && key.policy.username == owner && (name == "" || key.policy.name == name) {
+				delete(d.attachments, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (p *policyAttachments) List(ctx context.Context, principal model.Principal, opts store.ListOptions) (
+	*apiv1.PolicyAttachmentList, error,
+) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.PolicyAttachmentList{Items: []*apiv1.PolicyAttachment{}}
+	err := p.ds.read(func(d *data) error {
+		for key, stored := range d.attachments {
+			if key.policy.tenant == tenant && key.principal == principal && opts.Matches(nil, stored.Fields()) {
+				attachment := *stored
+				list.Items = append(list.Items, &attachment)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(a *apiv1.PolicyAttachment) uint64 { return a.ID }, opts)
+	return list, nil
+}
+
+func (p *policyAttachments) ListPolicies(ctx context.Context, principals []model.Principal) ([]*apiv1.Policy, error) {
+	tenant := store.TenantFromContext(ctx)
+	policies := []*apiv1.Policy{}
+	err := p.ds.read(func(d *data) error {
+		seen := map[policyKey]bool{}
+		for key := range d.attachments {
+			if key.policy.tenant != tenant || seen[key.policy] || !slices.Contains(principals, key.principal) {
+				continue
+			}
+			// the attachments of the deleted policies are removed in the same transaction
+			if stored, ok := d.policies[key.policy]; ok {
+				seen[key.policy] = true
+				pol := *stored
+				policies = append(policies, &pol)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedPage(policies, func(p *apiv1.Policy) uint64 { return p.ID }, store.ListOptions{}), nil
+}
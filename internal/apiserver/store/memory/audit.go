@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type auditEvents struct {
+	ds *datastore
+}
+
+func (a *auditEvents) Create(ctx context.Context, event *apiv1.AuditEvent) error {
+	event.Tenant = store.TenantFromContext(ctx)
+	return a.ds.write(func(d *data) error {
+		event.ID = d.nextID()
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = now()
+		}
+		stored := *event
+		d.auditEvents = append(d.auditEvents, &stored)
+		return nil
+	})
+}
+
+func (a *auditEvents) List(ctx context.Context, filter store.AuditFilter, opts store.ListOptions,
+) (*apiv1.AuditEventList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.AuditEventList{Items: []*apiv1.AuditEvent{}}
+	err := a.ds.read(func(d *data) error {
+		for _, stored := range d.auditEvents {
+			if stored.Tenant == tenant && matches(stored, filter) && opts.Matches(nil, stored.Fields()) {
+				event := *stored
+				list.Items = append(list.Items, &event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	// the latest events first
+	slices.SortFunc(list.Items, func(a, b *apiv1.AuditEvent) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+	list.Items = page(list.Items, opts)
+	return list, nil
+}
+
+// matches reports whether the event is selected by the filter.
+func matches(event *apiv1.AuditEvent, filter store.AuditFilter) bool {
+	if filter.Actor != "" && event.Actor != filter.Actor {
+		return false
+	}
+	if filter.Resource != "" {
+		prefix := strings.TrimSuffix(filter.Resource, "/")
+		if event.Resource != prefix && !strings.HasPrefix(event.Resource, prefix+"/") {
+			return false
+		}
+	}
+	if !filter.Since.IsZero() && event.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !event.CreatedAt.Before(filter.Until) {
+		return false
+	}
+	return true
+}
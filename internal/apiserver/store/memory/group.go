@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type groups struct {
+	ds *datastore
+}
+
+func (g *groups) Create(ctx context.Context, group *apiv1.Group) error {
+	group.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: group.Tenant, name: group.Name}
+	return g.ds.write(func(d *data) error {
+		if _, ok := d.groups[key]; ok {
+			return serrors.WithCodef(code.ErrGroupAlreadyExists, "group %q already exists", group.Name)
+		}
+		group.ID = d.nextID()
+		group.CreatedAt, group.UpdatedAt = now(), now()
+		stored := *group
+		d.groups[key] = &stored
+		return nil
+	})
+}
+
+func (g *groups) Get(ctx context.Context, name string) (*apiv1.Group, error) {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	var group apiv1.Group
+	err := g.ds.read(func(d *data) error {
+		stored, ok := d.groups[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrGroupNotFound, "group %q not found", name)
+		}
+		group = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (g *groups) Update(ctx context.Context, group *apiv1.Group) error {
+	group.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: group.Tenant, name: group.Name}
+	return g.ds.write(func(d *data) error {
+		if _, ok := d.groups[key]; !ok {
+			return serrors.WithCodef(code.ErrGroupNotFound, "group %q not found", group.Name)
+		}
+		group.UpdatedAt = now()
+		stored := *group
+		d.groups[key] = &stored
+		return nil
+	})
+}
+
+func (g *groups) Delete(ctx context.Context, name string) error {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	return g.ds.write(func(d *data) error {
+		if _, ok := d.groups[key]; !ok {
+			return serrors.WithCodef(code.ErrGroupNotFound, "group %q not found", name)
+		}
+		delete(d.groups, key)
+		return nil
+	})
+}
+
+func (g *groups) List(ctx context.Context, opts store.ListOptions) (*apiv1.GroupList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.GroupList{Items: []*apiv1.Group{}}
+	err := g.ds.read(func(d *data) error {
+		for key, stored := range d.groups {
+			if key.tenant == tenant && opts.Matches(nil, stored.Fields()) {
+				group := *stored
+				list.Items = append(list.Items, &group)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(g *apiv1.Group) uint64 { return g.ID }, opts)
+	return list, nil
+}
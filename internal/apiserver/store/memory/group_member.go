@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// memberKey is the unique key of a group member.
+type memberKey struct {
+	tenant   string
+	group    string
+	username string
+}
+
+type groupMembers struct {
+	ds *datastore
+}
+
+func (g *groupMembers) Create(ctx context.Context, member *apiv1.GroupMember) error {
+	member.Tenant = store.TenantFromContext(ctx)
+	return g.ds.write(func(d *data) error {
+		key := memberKey{member.Tenant, member.Group, member.Username}
+		if _, ok := d.members[key]; ok {
+			return serrors.WithCodef(code.ErrGroupMemberAlreadyExists, "user %q is already a member of group %q",
+				member.Username, member.Group)
+		}
+		member.ID = d.nextID()
+		member.CreatedAt = now()
+		stored := *member
+		d.members[key] = &stored
+		return nil
+	})
+}
+
+func (g *groupMembers) Delete(ctx context.Context, group, username string) error {
+	key := memberKey{store.TenantFromContext(ctx), group, username}
+	return g.ds.write(func(d *data) error {
+		if _, ok := d.members[key]; !ok {
+			return serrors.WithCodef(code.ErrGroupMemberNotFound, "user %q is not a member of group %q", username, group)
+		}
+		delete(d.members, key)
+		return nil
+	})
+}
+
+func (g *groupMembers) DeleteCollection(ctx context.Context, group string) error {
+	tenant := store.TenantFromContext(ctx)
+	return g.ds.write(func(d *data) error {
+		for key := range d.members {
+			if key.tenant == tenant && key.group == group {
+				delete(d.members, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (g *groupMembers) DeleteUser(ctx context.Context, username string) error {
+	tenant := store.TenantFromContext(ctx)
+	return g.ds.write(func(d *data) error {
+		for key := range d.members {
+			if key.tenant == tenant && key.username == username {
+				delete(d.members, key)
+			}
+		}
+		return nil
+	})
+}
+
+func (g *groupMembers) List(ctx context.Context, group string, opts store.ListOptions) (*apiv1.GroupMemberList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.GroupMemberList{Items: []*apiv1.GroupMember{}}
+	err := g.ds.read(func(d *data) error {
+		for key, stored := range d.members {
+			if key.tenant == tenant && key.group == group && opts.Matches(nil, stored.Fields()) {
+				member := *stored
+				list.Items = append(list.Items, &member)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(m *apiv1.GroupMember) uint64 { return m.ID }, opts)
+	return list, nil
+}
+
+func (g *groupMembers) ListGroups(ctx context.Context, username string) ([]string, error) {
+	tenant := store.TenantFromContext(ctx)
+	groups := []string{}
+	err := g.ds.read(func(d *data) error {
+		for key := range d.members {
+			if key.tenant == tenant && key.username == username {
+				groups = append(groups, key.group)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(groups)
+	return groups, nil
+}
@@ -0,0 +1,259 @@
+// Package memory implements the store.Factory in memory, it is meant for the local development and the tests.
+//
+// All of the stores share a single lock, a transaction holds the lock until it ends
+// and restores a snapshot of the data if it fails.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/apiserver/store/notifier"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// nameKey is the unique key of the objects named in a tenant, like the users, the groups and the roles.
+type nameKey struct {
+	tenant string
+	name   string
+}
+
+// data holds all of the objects, the objects are copied in and out of the stores.
+// The objects of all of the tenants are mixed, they are told apart by the tenant in the keys or in the objects.
+type data struct {
+	tenants map[string]*apiv1.Tenant
+	users   map[nameKey]*apiv1.User
+	// secrets are indexed by the access key.
+	secrets  map[string]*apiv1.Secret
+	policies map[policyKey]*apiv1.Policy
+	groups   map[nameKey]*apiv1.Group
+	members  map[memberKey]*apiv1.GroupMember
+	roles    map[nameKey]*apiv1.Role
+	// attachments are indexed by the principal and the policy.
+	attachments map[attachmentKey]*apiv1.PolicyAttachment
+	// auditEvents are appended in the order of creation.
+	auditEvents []*apiv1.AuditEvent
+	// lastID is the last id assigned to any object.
+	lastID uint64
+	// lastVersion is the resource version of the latest change of the watchable objects.
+	lastVersion uint64
+	// watchEvents are the latest watch events in the order of their versions,
+	// compacted is the version of the latest event dropped from them.
+	watchEvents []*model.WatchEvent
+	compacted   uint64
+	// nonces are the expiration times of the nonces, the expired ones are deleted after nextNonceSweep.
+	nonces         map[string]time.Time
+	nextNonceSweep time.Time
+}
+
+// maxWatchEvents is the number of the watch events kept, the older events are dropped.
+const maxWatchEvents = 1000
+
+func newData() *data {
+	return &data{
+		tenants:     map[string]*apiv1.Tenant{},
+		users:       map[nameKey]*apiv1.User{},
+		secrets:     map[string]*apiv1.Secret{},
+		policies:    map[policyKey]*apiv1.Policy{},
+		groups:      map[nameKey]*apiv1.Group{},
+		members:     map[memberKey]*apiv1.GroupMember{},
+		roles:       map[nameKey]*apiv1.Role{},
+		attachments: map[attachmentKey]*apiv1.PolicyAttachment{},
+		nonces:      map[string]time.Time{},
+	}
+}
+
+// clone copies the maps, the stored objects are replaced instead of modified in place so they are shared.
+func (d *data) clone() *data {
+	return &data{
+		tenants:     maps.Clone(d.tenants),
+		users:       maps.Clone(d.users),
+		secrets:     maps.Clone(d.secrets),
+		policies:    maps.Clone(d.policies),
+		groups:      maps.Clone(d.groups),
+		members:     maps.Clone(d.members),
+		roles:       maps.Clone(d.roles),
+		attachments: maps.Clone(d.attachments),
+		// the events are only appended, the appends after the snapshot do not change it
+		auditEvents:    slices.Clip(d.auditEvents),
+		lastID:         d.lastID,
+		lastVersion:    d.lastVersion,
+		watchEvents:    slices.Clip(d.watchEvents),
+		compacted:      d.compacted,
+		nonces:         maps.Clone(d.nonces),
+		nextNonceSweep: d.nextNonceSweep,
+	}
+}
+
+func (d *data) nextID() uint64 {
+	d.lastID++
+	return d.lastID
+}
+
+// record assigns the next resource version to the change of the object of the metadata and records its event.
+func (d *data) record(kind string, typ metav1.EventType, meta *metav1.ObjectMeta, obj any) error {
+	version := d.lastVersion + 1
+	old := meta.ResourceVersion
+	meta.ResourceVersion = version
+	event, err := model.NewWatchEvent(meta.Tenant, kind, typ, version, obj)
+	if err != nil {
+		meta.ResourceVersion = old
+		return serrors.WrapC(err, code.ErrUnknown, "encode watch event of %s %q", kind, meta.Name)
+	}
+	event.CreatedAt = now()
+
+	d.lastVersion = version
+	d.watchEvents = append(d.watchEvents, event)
+	if drop := len(d.watchEvents) - maxWatchEvents; drop > 0 {
+		d.compacted = d.watchEvents[drop-1].ResourceVersion
+		d.watchEvents = d.watchEvents[drop:]
+	}
+	return nil
+}
+
+type datastore struct {
+	mu   *sync.RWMutex
+	data **data
+	// notifier notifies the watchers of the new watch events.
+	notifier *notifier.Notifier
+	// inTx indicates the lock is held by the transaction.
+	inTx bool
+}
+
+var _ store.Factory = (*datastore)(nil)
+
+// New creates an empty in-memory store.Factory.
+func New() store.Factory {
+	d := newData()
+	return &datastore{mu: &sync.RWMutex{}, data: &d, notifier: notifier.New()}
+}
+
+func (ds *datastore) Tenants() store.TenantStore {
+	return &tenants{ds: ds}
+}
+
+func (ds *datastore) Users() store.UserStore {
+	return &users{ds: ds}
+}
+
+func (ds *datastore) Secrets() store.SecretStore {
+	return &secrets{ds: ds}
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return &policies{ds: ds}
+}
+
+func (ds *datastore) Groups() store.GroupStore {
+	return &groups{ds: ds}
+}
+
+func (ds *datastore) GroupMembers() store.GroupMemberStore {
+	return &groupMembers{ds: ds}
+}
+
+func (ds *datastore) Roles() store.RoleStore {
+	return &roles{ds: ds}
+}
+
+func (ds *datastore) PolicyAttachments() store.PolicyAttachmentStore {
+	return &policyAttachments{ds: ds}
+}
+
+func (ds *datastore) AuditEvents() store.AuditEventStore {
+	return &auditEvents{ds: ds}
+}
+
+func (ds *datastore) WatchEvents() store.WatchEventStore {
+	return &watchEvents{ds: ds}
+}
+
+func (ds *datastore) Nonces() store.NonceStore {
+	return &nonces{ds: ds}
+}
+
+func (ds *datastore) Tx(ctx context.Context, fn func(tx store.Factory) error) error {
+	if ds.inTx {
+		// nested transactions share the outer one
+		return fn(ds)
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	snapshot := (*ds.data).clone()
+	if err := fn(&datastore{mu: ds.mu, data: ds.data, notifier: ds.notifier, inTx: true}); err != nil {
+		*ds.data = snapshot
+		return err
+	}
+	// the watchers notified in the transaction wait for the lock, notify them again in case they missed the changes
+	ds.notifier.Notify()
+	return nil
+}
+
+func (ds *datastore) Close() error {
+	ds.notifier.Close()
+	return nil
+}
+
+// watch runs fn with the write lock like write, and notifies the watchers if it succeeds.
+// It is used by the changes of the watchable objects.
+func (ds *datastore) watch(fn func(d *data) error) error {
+	if err := ds.write(fn); err != nil {
+		return err
+	}
+	ds.notifier.Notify()
+	return nil
+}
+
+// read runs fn with the read lock unless the lock is held by the transaction.
+func (ds *datastore) read(fn func(d *data) error) error {
+	if !ds.inTx {
+		ds.mu.RLock()
+		defer ds.mu.RUnlock()
+	}
+	return fn(*ds.data)
+}
+
+// write runs fn with the write lock unless the lock is held by the transaction.
+func (ds *datastore) write(fn func(d *data) error) error {
+	if !ds.inTx {
+		ds.mu.Lock()
+		defer ds.mu.Unlock()
+	}
+	return fn(*ds.data)
+}
+
+// sortedPage sorts the items by id and returns the page of the list options.
+func sortedPage[T any](items []T, id func(T) uint64, opts store.ListOptions) []T {
+	slices.SortFunc(items, func(a, b T) int {
+		return cmp.Compare(id(a), id(b))
+	})
+	return page(items, opts)
+}
+
+// page returns the page of the list options of the sorted items.
+func page[T any](items []T, opts store.ListOptions) []T {
+	if opts.Offset >= len(items) {
+		return items[:0]
+	}
+	items = items[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(items) {
+		items = items[:opts.Limit]
+	}
+	return items
+}
+
+// now returns the current time in the precision of the database.
+func now() time.Time {
+	return time.Now().Round(time.Microsecond)
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// nonceSweepInterval is how often the expired nonces are deleted.
+const nonceSweepInterval = time.Minute
+
+type nonces struct {
+	ds *datastore
+}
+
+func (n *nonces) Add(_ context.Context, nonce string, expiresAt, now time.Time) (bool, error) {
+	fresh := false
+	err := n.ds.write(func(d *data) error {
+		if now.After(d.nextNonceSweep) {
+			for stored, exp := range d.nonces {
+				if now.After(exp) {
+					delete(d.nonces, stored)
+				}
+			}
+			d.nextNonceSweep = now.Add(nonceSweepInterval)
+		}
+
+		if exp, ok := d.nonces[nonce]; ok && !now.After(exp) {
+			return nil
+		}
+		d.nonces[nonce] = expiresAt
+		fresh = true
+		return nil
+	})
+	return fresh, err
+}
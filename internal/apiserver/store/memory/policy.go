@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// policyKey is the unique key of a policy.
+type policyKey struct {
+	tenant   string
+	username string
+	name     string
+}
+
+type policies struct {
+	ds *datastore
+}
+
+func (p *policies) Create(ctx context.Context, pol *apiv1.Policy) error {
+	pol.Tenant = store.TenantFromContext(ctx)
+	return p.ds.watch(func(d *data) error {
+		key := policyKey{pol.Tenant, pol.Username, pol.Name}
+		if _, ok := d.policies[key]; ok {
+			return serrors.WithCodef(code.ErrPolicyAlreadyExists, "policy %q of user %q already exists",
+				pol.Name, pol.Username)
+		}
+		pol.ID = d.nextID()
+		pol.CreatedAt, pol.UpdatedAt = now(), now()
+		if err := d.record(model.KindPolicy, metav1.Added, &pol.ObjectMeta, pol); err != nil {
+			return err
+		}
+		stored := *pol
+		d.policies[key] = &stored
+		return nil
+	})
+}
+
+func (p *policies) Get(ctx context.Context, username, name string) (*apiv1.Policy, error) {
+	key := policyKey{store.TenantFromContext(ctx), username, name}
+	var pol apiv1.Policy
+	err := p.ds.read(func(d *data) error {
+		stored, ok := d.policies[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrPolicyNotFound, "policy %q of user %q not found", name, username)
+		}
+		pol = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pol, nil
+}
+
+func (p *policies) Update(ctx context.Context, pol *apiv1.Policy) error {
+	pol.Tenant = store.TenantFromContext(ctx)
+	return p.ds.watch(func(d *data) error {
+		key := policyKey{pol.Tenant, pol.Username, pol.Name}
+		stored, ok := d.policies[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrPolicyNotFound, "policy %q of user %q not found", pol.Name, pol.Username)
+		}
+		if stored.ResourceVersion != pol.ResourceVersion {
+			return serrors.WithCodef(code.ErrConflict, "policy %q of user %q has been modified", pol.Name, pol.Username)
+		}
+		pol.UpdatedAt = now()
+		if err := d.record(model.KindPolicy, metav1.Modified, &pol.ObjectMeta, pol); err != nil {
+			return err
+		}
+		updated := *pol
+		d.policies[key] = &updated
+		return nil
+	})
+}
+
+func (p *policies) Delete(ctx context.Context, username, name string) error {
+	key := policyKey{store.TenantFromContext(ctx), username, name}
+	return p.ds.watch(func(d *data) error {
+		stored, ok := d.policies[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrPolicyNotFound, "policy %q of user %q not found", name, username)
+		}
+		deleted := *stored
+		if err := d.record(model.KindPolicy, metav1.Deleted, &deleted.ObjectMeta, &deleted); err != nil {
+			return err
+		}
+		delete(d.policies, key)
+		return nil
+	})
+}
+
+func (p *policies) DeleteCollection(ctx context.Context, username string) error {
+	tenant := store.TenantFromContext(ctx)
+	return p.ds.watch(func(d *data) error {
+		var deleted []*apiv1.Policy
+		for key, stored := range d.policies {
+			if key.tenant == tenant && key.username == username {
+				pol := *stored
+				deleted = append(deleted, &pol)
+			}
+		}
+		for _, pol := range sortedPage(deleted, func(p *apiv1.Policy) uint64 { return p.ID }, store.ListOptions{}) {
+			if err := d.record(model.KindPolicy, metav1.Deleted, &pol.ObjectMeta, pol); err != nil {
+				return err
+			}
+			delete(d.policies, policyKey{tenant, username, pol.Name})
+		}
+		return nil
+	})
+}
+
+func (p *policies) List(ctx context.Context, username string, opts store.ListOptions) (*apiv1.PolicyList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.PolicyList{Items: []*apiv1.Policy{}}
+	err := p.ds.read(func(d *data) error {
+		for key, stored := range d.policies {
+			if key.tenant == tenant && (username == "" || key.username == username) &&
+				opts.Matches(stored.Labels, stored.Fields()) {
+				pol := *stored
+				list.Items = append(list.Items, &pol)
+			}
+		}
+		list.ResourceVersion = d.lastVersion
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(p *apiv1.Policy) uint64 { return p.ID }, opts)
+	return list, nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type roles struct {
+	ds *datastore
+}
+
+// copyRole copies the role deeply, the trusted principals must not be shared with the caller.
+func copyRole(role *apiv1.Role) *apiv1.Role {
+	c := *role
+	c.TrustedUsers = slices.Clone(role.TrustedUsers)
+	c.TrustedGroups = slices.Clone(role.TrustedGroups)
+	return &c
+}
+
+func (r *roles) Create(ctx context.Context, role *apiv1.Role) error {
+	role.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: role.Tenant, name: role.Name}
+	return r.ds.write(func(d *data) error {
+		if _, ok := d.roles[key]; ok {
+			return serrors.WithCodef(code.ErrRoleAlreadyExists, "role %q already exists", role.Name)
+		}
+		role.ID = d.nextID()
+		role.CreatedAt, role.UpdatedAt = now(), now()
+		d.roles[key] = copyRole(role)
+		return nil
+	})
+}
+
+func (r *roles) Get(ctx context.Context, name string) (*apiv1.Role, error) {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	var role *apiv1.Role
+	err := r.ds.read(func(d *data) error {
+		stored, ok := d.roles[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrRoleNotFound, "role %q not found", name)
+		}
+		role = copyRole(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *roles) Update(ctx context.Context, role *apiv1.Role) error {
+	role.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: role.Tenant, name: role.Name}
+	return r.ds.write(func(d *data) error {
+		if _, ok := d.roles[key]; !ok {
+			return serrors.WithCodef(code.ErrRoleNotFound, "role %q not found", role.Name)
+		}
+		role.UpdatedAt = now()
+		d.roles[key] = copyRole(role)
+		return nil
+	})
+}
+
+func (r *roles) Delete(ctx context.Context, name string) error {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	return r.ds.write(func(d *data) error {
+		if _, ok := d.roles[key]; !ok {
+			return serrors.WithCodef(code.ErrRoleNotFound, "role %q not found", name)
+		}
+		delete(d.roles, key)
+		return nil
+	})
+}
+
+func (r *roles) List(ctx context.Context, opts store.ListOptions) (*apiv1.RoleList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.RoleList{Items: []*apiv1.Role{}}
+	err := r.ds.read(func(d *data) error {
+		for key, stored := range d.roles {
+			if key.tenant == tenant && opts.Matches(nil, stored.Fields()) {
+				list.Items = append(list.Items, copyRole(stored))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(r *apiv1.Role) uint64 { return r.ID }, opts)
+	return list, nil
+}
+
+func (r *roles) Untrust(ctx context.Context, principal model.Principal) error {
+	tenant := store.TenantFromContext(ctx)
+	return r.ds.write(func(d *data) error {
+		for key, stored := range d.roles {
+			if key.tenant != tenant {
+				continue
+			}
+			role := copyRole(stored)
+			trusted := &role.TrustedUsers
+			if principal.Kind == apiv1.PrincipalGroup {
+				trusted = &role.TrustedGroups
+			}
+			if !slices.Contains(*trusted, principal.Name) {
+				continue
+			}
+			*trusted = slices.DeleteFunc(*trusted, func(n string) bool { return n == principal.Name })
+			role.UpdatedAt = now()
+			d.roles[key] = role
+		}
+		return nil
+	})
+}
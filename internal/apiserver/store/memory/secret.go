@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+type secrets struct {
+	ds *datastore
+}
+
+func (s *secrets) Create(ctx context.Context, secret *apiv1.Secret) error {
+	secret.Tenant = store.TenantFromContext(ctx)
+	return s.ds.watch(func(d *data) error {
+		if _, ok := d.secrets[secret.AccessKey]; ok {
+			return serrors.WithCodef(code.ErrDatabase, "access key %q is duplicated", secret.AccessKey)
+		}
+		secret.ID = d.nextID()
+		secret.CreatedAt, secret.UpdatedAt = now(), now()
+		if err := d.record(model.KindSecret, metav1.Added, &secret.ObjectMeta, secret); err != nil {
+			return err
+		}
+		stored := *secret
+		d.secrets[secret.AccessKey] = &stored
+		return nil
+	})
+}
+
+func (s *secrets) Get(ctx context.Context, username, accessKey string) (*apiv1.Secret, error) {
+	tenant := store.TenantFromContext(ctx)
+	var secret apiv1.Secret
+	err := s.ds.read(func(d *data) error {
+		stored, ok := d.secrets[accessKey]
+		if !ok || stored.Tenant != tenant || stored.Username != username {
+			return serrors.WithCodef(code.ErrSecretNotFound, "secret %q of user %q not found", accessKey, username)
+		}
+		secret = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// GetByAccessKey is not scoped to the tenant, the access keys are unique in all of the tenants.
+func (s *secrets) GetByAccessKey(_ context.Context, accessKey string) (*apiv1.Secret, error) {
+	var secret apiv1.Secret
+	err := s.ds.read(func(d *data) error {
+		stored, ok := d.secrets[accessKey]
+		if !ok {
+			return serrors.WithCodef(code.ErrSecretNotFound, "secret %q not found", accessKey)
+		}
+		secret = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (s *secrets) Update(ctx context.Context, secret *apiv1.Secret) error {
+	secret.Tenant = store.TenantFromContext(ctx)
+	return s.ds.watch(func(d *data) error {
+		stored, ok := d.secrets[secret.AccessKey]
+		if !ok || stored.Tenant != secret.Tenant {
+			return serrors.WithCodef(code.ErrSecretNotFound, "secret %q not found", secret.AccessKey)
+		}
+		if stored.ResourceVersion != secret.ResourceVersion {
+			return serrors.WithCodef(code.ErrConflict, "secret %q has been modified", secret.AccessKey)
+		}
+		secret.UpdatedAt = now()
+		if err := d.record(model.KindSecret, metav1.Modified, &secret.ObjectMeta, secret); err != nil {
+			return err
+		}
+		updated := *secret
+		d.secrets[secret.AccessKey] = &updated
+		return nil
+	})
+}
+
+func (s *secrets) Delete(ctx context.Context, username, accessKey string) error {
+	tenant := store.TenantFromContext(ctx)
+	return s.ds.watch(func(d *data) error {
+		stored, ok := d.secrets[accessKey]
+		if !ok || stored.Tenant != tenant || stored.Username != username {
+			return serrors.WithCodef(code.ErrSecretNotFound, "secret %q of user %q not found", accessKey, username)
+		}
+		deleted := *stored
+		if err := d.record(model.KindSecret, metav1.Deleted, &deleted.ObjectMeta, &deleted); err != nil {
+			return err
+		}
+		delete(d.secrets, accessKey)
+		return nil
+	})
+}
+
+func (s *secrets) DeleteCollection(ctx context.Context, username string) error {
+	tenant := store.TenantFromContext(ctx)
+	return s.ds.watch(func(d *data) error {
+		var deleted []*apiv1.Secret
+		for _, stored := range d.secrets {
+			if stored.Tenant == tenant && stored.Username == username {
+				secret := *stored
+				deleted = append(deleted, &secret)
+			}
+		}
+		for _, secret := range sortedPage(deleted, func(s *apiv1.Secret) uint64 { return s.ID }, store.ListOptions{}) {
+			if err := d.record(model.KindSecret, metav1.Deleted, &secret.ObjectMeta, secret); err != nil {
+				return err
+			}
+			delete(d.secrets, secret.AccessKey)
+		}
+		return nil
+	})
+}
+
+func (s *secrets) List(ctx context.Context, username string, opts store.ListOptions) (*apiv1.SecretList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.SecretList{Items: []*apiv1.Secret{}}
+	err := s.ds.read(func(d *data) error {
+		for _, stored := range d.secrets {
+			if stored.Tenant == tenant && (username == "" || stored.Username == username) &&
+				opts.Matches(stored.Labels, stored.Fields()) {
+				secret := *stored
+				list.Items = append(list.Items, &secret)
+			}
+		}
+		list.ResourceVersion = d.lastVersion
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(s *apiv1.Secret) uint64 { return s.ID }, opts)
+	return list, nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type tenants struct {
+	ds *datastore
+}
+
+func (t *tenants) Create(_ context.Context, tenant *apiv1.Tenant) error {
+	return t.ds.write(func(d *data) error {
+		if _, ok := d.tenants[tenant.Name]; ok {
+			return serrors.WithCodef(code.ErrTenantAlreadyExists, "tenant %q already exists", tenant.Name)
+		}
+		tenant.ID = d.nextID()
+		tenant.CreatedAt, tenant.UpdatedAt = now(), now()
+		stored := *tenant
+		d.tenants[tenant.Name] = &stored
+		return nil
+	})
+}
+
+func (t *tenants) Get(_ context.Context, name string) (*apiv1.Tenant, error) {
+	var tenant apiv1.Tenant
+	err := t.ds.read(func(d *data) error {
+		stored, ok := d.tenants[name]
+		if !ok {
+			return serrors.WithCodef(code.ErrTenantNotFound, "tenant %q not found", name)
+		}
+		tenant = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (t *tenants) Update(_ context.Context, tenant *apiv1.Tenant) error {
+	return t.ds.write(func(d *data) error {
+		if _, ok := d.tenants[tenant.Name]; !ok {
+			return serrors.WithCodef(code.ErrTenantNotFound, "tenant %q not found", tenant.Name)
+		}
+		tenant.UpdatedAt = now()
+		stored := *tenant
+		d.tenants[tenant.Name] = &stored
+		return nil
+	})
+}
+
+func (t *tenants) Delete(_ context.Context, name string) error {
+	return t.ds.write(func(d *data) error {
+		if _, ok := d.tenants[name]; !ok {
+			return serrors.WithCodef(code.ErrTenantNotFound, "tenant %q not found", name)
+		}
+		delete(d.tenants, name)
+		return nil
+	})
+}
+
+func (t *tenants) List(_ context.Context, opts store.ListOptions) (*apiv1.TenantList, error) {
+	list := &apiv1.TenantList{Items: []*apiv1.Tenant{}}
+	err := t.ds.read(func(d *data) error {
+		for _, stored := range d.tenants {
+			if opts.Matches(nil, stored.Fields()) {
+				tenant := *stored
+				list.Items = append(list.Items, &tenant)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(t *apiv1.Tenant) uint64 { return t.ID }, opts)
+	return list, nil
+}
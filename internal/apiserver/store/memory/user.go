@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+type users struct {
+	ds *datastore
+}
+
+func (u *users) Create(ctx context.Context, user *apiv1.User) error {
+	user.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: user.Tenant, name: user.Name}
+	return u.ds.write(func(d *data) error {
+		if _, ok := d.users[key]; ok {
+			return serrors.WithCodef(code.ErrUserAlreadyExists, "user %q already exists", user.Name)
+		}
+		user.ID = d.nextID()
+		user.ResourceVersion = 1
+		user.CreatedAt, user.UpdatedAt = now(), now()
+		stored := *user
+		d.users[key] = &stored
+		return nil
+	})
+}
+
+func (u *users) Get(ctx context.Context, name string) (*apiv1.User, error) {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	var user apiv1.User
+	err := u.ds.read(func(d *data) error {
+		stored, ok := d.users[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrUserNotFound, "user %q not found", name)
+		}
+		user = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Lock is the same as Get, the transaction already holds the lock of all of the objects.
+func (u *users) Lock(ctx context.Context, name string) (*apiv1.User, error) {
+	return u.Get(ctx, name)
+}
+
+func (u *users) Update(ctx context.Context, user *apiv1.User) error {
+	user.Tenant = store.TenantFromContext(ctx)
+	key := nameKey{tenant: user.Tenant, name: user.Name}
+	return u.ds.write(func(d *data) error {
+		stored, ok := d.users[key]
+		if !ok {
+			return serrors.WithCodef(code.ErrUserNotFound, "user %q not found", user.Name)
+		}
+		if stored.ResourceVersion != user.ResourceVersion {
+			return serrors.WithCodef(code.ErrConflict, "user %q has been modified", user.Name)
+		}
+		user.ResourceVersion++
+		user.UpdatedAt = now()
+		updated := *user
+		d.users[key] = &updated
+		return nil
+	})
+}
+
+func (u *users) Delete(ctx context.Context, name string) error {
+	key := nameKey{tenant: store.TenantFromContext(ctx), name: name}
+	return u.ds.write(func(d *data) error {
+		if _, ok := d.users[key]; !ok {
+			return serrors.WithCodef(code.ErrUserNotFound, "user %q not found", name)
+		}
+		delete(d.users, key)
+		return nil
+	})
+}
+
+func (u *users) List(ctx context.Context, opts store.ListOptions) (*apiv1.UserList, error) {
+	tenant := store.TenantFromContext(ctx)
+	list := &apiv1.UserList{Items: []*apiv1.User{}}
+	err := u.ds.read(func(d *data) error {
+		for key, stored := range d.users {
+			if key.tenant == tenant && opts.Matches(stored.Labels, stored.Fields()) {
+				user := *stored
+				list.Items = append(list.Items, &user)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	list.TotalCount = int64(len(list.Items))
+	list.Items = sortedPage(list.Items, func(u *apiv1.User) uint64 { return u.ID }, opts)
+	return list, nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+type watchEvents struct {
+	ds *datastore
+}
+
+func (w *watchEvents) List(ctx context.Context, kind string, after uint64, limit int) ([]*model.WatchEvent, error) {
+	tenant := store.TenantFromContext(ctx)
+	events := []*model.WatchEvent{}
+	err := w.ds.read(func(d *data) error {
+		if after < d.compacted {
+			return serrors.WithCodef(code.ErrResourceVersionTooOld,
+				"resource version %d is too old, the oldest available version is %d", after, d.compacted)
+		}
+		start := sort.Search(len(d.watchEvents), func(i int) bool {
+			return d.watchEvents[i].ResourceVersion > after
+		})
+		for _, stored := range d.watchEvents[start:] {
+			if limit > 0 && len(events) == limit {
+				break
+			}
+			if stored.Tenant == tenant && stored.Kind == kind {
+				event := *stored
+				events = append(events, &event)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (w *watchEvents) Latest(context.Context) (uint64, error) {
+	var version uint64
+	err := w.ds.read(func(d *data) error {
+		version = d.lastVersion
+		return nil
+	})
+	return version, err
+}
+
+func (w *watchEvents) Subscribe() (<-chan struct{}, func()) {
+	return w.ds.notifier.Subscribe()
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/policy"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+func TestWatchEventsCompaction(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	pol := &apiv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "everything"},
+		Username:   "alice",
+		Document: policy.Document{Version: policy.Version20251001, Statements: []policy.Statement{
+			{Effect: policy.Allow, Actions: []string{"*"}, Resources: []string{"*"}},
+		}},
+	}
+	if err := s.Policies().Create(ctx, pol); err != nil {
+		t.Fatalf("create policy: %v", err)
+	}
+	// the event of the creation is dropped by the updates
+	for range maxWatchEvents {
+		if err := s.Policies().Update(ctx, pol); err != nil {
+			t.Fatalf("update policy: %v", err)
+		}
+	}
+	latest, err := s.WatchEvents().Latest(ctx)
+	if err != nil || latest != maxWatchEvents+1 {
+		t.Fatalf("Latest() = %d, %v, want %d", latest, err, maxWatchEvents+1)
+	}
+
+	tests := []struct {
+		name   string
+		after  uint64
+		want   int
+		tooOld bool
+		first  uint64
+	}{
+		{"before the dropped event", 0, 0, true, 0},
+		{"after the dropped event", 1, maxWatchEvents, false, 2},
+		{"after the latest event", latest, 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := s.WatchEvents().List(ctx, model.KindPolicy, tt.after, 0)
+			if tt.tooOld {
+				if !serrors.IsCode(err, code.ErrResourceVersionTooOld) {
+					t.Errorf("List() error = %v, want %d", err, code.ErrResourceVersionTooOld)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(events) != tt.want {
+				t.Fatalf("List() = %d events, want %d", len(events), tt.want)
+			}
+			if len(events) > 0 && events[0].ResourceVersion != tt.first {
+				t.Errorf("List() starts at %d, want %d", events[0].ResourceVersion, tt.first)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package notifier broadcasts the notifications of the changes of a store to the subscribers in the process.
+// The notifications are coalesced, a subscriber which is busy receives a single notification of all of
+// the changes in the meantime, and reads the changes from the store itself.
+package notifier
+
+import "sync"
+
+// Notifier broadcasts the notifications to the subscribers, the zero value is not usable, see New.
+type Notifier struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	closed      bool
+}
+
+// New creates a Notifier without subscribers.
+func New() *Notifier {
+	return &Notifier{subscribers: map[chan struct{}]struct{}{}}
+}
+
+// Subscribe returns a channel which receives a value after any changes, and the function to unsubscribe.
+// The channel is closed when the notifier is closed.
+func (n *Notifier) Subscribe() (<-chan struct{}, func()) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if n.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	n.subscribers[ch] = struct{}{}
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		if _, ok := n.subscribers[ch]; ok {
+			delete(n.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Notify notifies all of the subscribers, it never blocks.
+func (n *Notifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// the subscriber has not received the previous notification yet
+		}
+	}
+}
+
+// Close closes the channels of all of the subscribers, the later subscribers get closed channels.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers {
+		close(ch)
+	}
+	n.subscribers = nil
+	n.closed = true
+}
@@ -0,0 +1,240 @@
+// Package store defines the storage interfaces of siam-apiserver.
+//
+// The errors returned by the stores are coded by package code,
+// e.g. code.ErrUserNotFound is returned when the user does not exist, and code.ErrDatabase for the storage failures.
+//
+// All of the stores except the TenantStore are scoped by the tenant of the context, see WithTenant,
+// the objects of the other tenants are never read or changed.
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+	"github.com/strayca7/siam/staging/src/apimachinery/selector"
+)
+
+type tenantContextKey struct{}
+
+// WithTenant returns a new context which scopes the stores to the tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant the stores are scoped to, it is model.DefaultTenant if not set.
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return model.DefaultTenant
+}
+
+// Factory creates the stores of all of the resources.
+type Factory interface {
+	Tenants() TenantStore
+	Users() UserStore
+	Secrets() SecretStore
+	Policies() PolicyStore
+	Groups() GroupStore
+	GroupMembers() GroupMemberStore
+	Roles() RoleStore
+	PolicyAttachments() PolicyAttachmentStore
+	AuditEvents() AuditEventStore
+	WatchEvents() WatchEventStore
+	Nonces() NonceStore
+	// Tx runs fn in a transaction, the changes made through the Factory passed to fn
+	// are committed if fn returns nil and rolled back otherwise.
+	Tx(ctx context.Context, fn func(tx Factory) error) error
+	Close() error
+}
+
+// ListOptions defines the pagination and the selectors of the list operations,
+// a non-positive Limit lists all of the objects.
+type ListOptions struct {
+	Offset int
+	Limit  int
+	// LabelSelector selects the objects by their labels, the objects without labels have no label keys.
+	LabelSelector selector.Selector
+	// FieldSelector selects the objects by the fields returned by their Fields method.
+	FieldSelector selector.Selector
+}
+
+// NewListOptions converts the list options of the API, the keys of the field selector must be the keys of fields,
+// which are the selectable fields of the listed objects. It returns code.ErrValidation if any selector is invalid.
+func NewListOptions(opts *metav1.ListOptions, fields selector.Set) (ListOptions, error) {
+	labelSelector, err := selector.Parse(opts.LabelSelector)
+	if err != nil {
+		return ListOptions{}, serrors.WithCode(code.ErrValidation, err.Error())
+	}
+	fieldSelector, err := selector.Parse(opts.FieldSelector)
+	if err != nil {
+		return ListOptions{}, serrors.WithCode(code.ErrValidation, err.Error())
+	}
+	for _, r := range fieldSelector {
+		if _, ok := fields[r.Key]; !ok {
+			return ListOptions{}, serrors.WithCodef(code.ErrValidation, "field %q is not supported by the field selector", r.Key)
+		}
+		// the fields always exist
+		if r.Operator == selector.Exists || r.Operator == selector.DoesNotExist {
+			return ListOptions{}, serrors.WithCodef(code.ErrValidation,
+				"field selector does not support the existence requirement %q", r)
+		}
+	}
+
+	return ListOptions{
+		Offset:        opts.Offset,
+		Limit:         opts.Limit,
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	}, nil
+}
+
+// Matches reports whether the object of the labels and the fields is selected by the selectors.
+func (o ListOptions) Matches(labels map[string]string, fields selector.Set) bool {
+	return o.LabelSelector.Matches(labels) && o.FieldSelector.Matches(fields)
+}
+
+// TenantStore defines the tenant storage interface, it is not scoped by the tenant of the context.
+type TenantStore interface {
+	Create(ctx context.Context, tenant *apiv1.Tenant) error
+	Get(ctx context.Context, name string) (*apiv1.Tenant, error)
+	Update(ctx context.Context, tenant *apiv1.Tenant) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, opts ListOptions) (*apiv1.TenantList, error)
+}
+
+// UserStore defines the user storage interface.
+type UserStore interface {
+	Create(ctx context.Context, user *apiv1.User) error
+	Get(ctx context.Context, name string) (*apiv1.User, error)
+	// Lock gets the user and locks it until the end of the transaction.
+	Lock(ctx context.Context, name string) (*apiv1.User, error)
+	// Update fails with code.ErrConflict if the user has been changed since it was read,
+	// which is told by the resource version. The resource version is increased by the update.
+	Update(ctx context.Context, user *apiv1.User) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, opts ListOptions) (*apiv1.UserList, error)
+}
+
+// SecretStore defines the secret storage interface, the changes of the secrets are recorded as the watch events.
+type SecretStore interface {
+	Create(ctx context.Context, secret *apiv1.Secret) error
+	Get(ctx context.Context, username, accessKey string) (*apiv1.Secret, error)
+	// GetByAccessKey gets the secret by the access key regardless of the owner and the tenant,
+	// the access keys are unique across the tenants.
+	GetByAccessKey(ctx context.Context, accessKey string) (*apiv1.Secret, error)
+	// Update fails with code.ErrConflict if the secret has been changed since it was read,
+	// which is told by the resource version. The resource version is set to the version of the change.
+	Update(ctx context.Context, secret *apiv1.Secret) error
+	Delete(ctx context.Context, username, accessKey string) error
+	// DeleteCollection deletes all of the secrets of the user.
+	DeleteCollection(ctx context.Context, username string) error
+	// List lists the secrets of the user, or of all of the users if username is empty.
+	List(ctx context.Context, username string, opts ListOptions) (*apiv1.SecretList, error)
+}
+
+// PolicyStore defines the policy storage interface, the changes of the policies are recorded as the watch events.
+type PolicyStore interface {
+	Create(ctx context.Context, policy *apiv1.Policy) error
+	Get(ctx context.Context, username, name string) (*apiv1.Policy, error)
+	// Update fails with code.ErrConflict if the policy has been changed since it was read,
+	// which is told by the resource version. The resource version is set to the version of the change.
+	Update(ctx context.Context, policy *apiv1.Policy) error
+	Delete(ctx context.Context, username, name string) error
+	// DeleteCollection deletes all of the policies of the user.
+	DeleteCollection(ctx context.Context, username string) error
+	// List lists the policies of the user, or of all of the users if username is empty.
+	List(ctx context.Context, username string, opts ListOptions) (*apiv1.PolicyList, error)
+}
+
+// GroupStore defines the group storage interface.
+type GroupStore interface {
+	Create(ctx context.Context, group *apiv1.Group) error
+	Get(ctx context.Context, name string) (*apiv1.Group, error)
+	Update(ctx context.Context, group *apiv1.Group) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, opts ListOptions) (*apiv1.GroupList, error)
+}
+
+// GroupMemberStore defines the group membership storage interface.
+type GroupMemberStore interface {
+	Create(ctx context.Context, member *apiv1.GroupMember) error
+	Delete(ctx context.Context, group, username string) error
+	// DeleteCollection deletes all of the members of the group.
+	DeleteCollection(ctx context.Context, group string) error
+	// DeleteUser deletes the user from all of the groups.
+	DeleteUser(ctx context.Context, username string) error
+	List(ctx context.Context, group string, opts ListOptions) (*apiv1.GroupMemberList, error)
+	// ListGroups lists the names of the groups the user is a member of.
+	ListGroups(ctx context.Context, username string) ([]string, error)
+}
+
+// RoleStore defines the role storage interface.
+type RoleStore interface {
+	Create(ctx context.Context, role *apiv1.Role) error
+	Get(ctx context.Context, name string) (*apiv1.Role, error)
+	Update(ctx context.Context, role *apiv1.Role) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context, opts ListOptions) (*apiv1.RoleList, error)
+	// Untrust removes the user or the group from the trusted principals of all of the roles.
+	Untrust(ctx context.Context, principal model.Principal) error
+}
+
+// PolicyAttachmentStore defines the policy attachment storage interface.
+type PolicyAttachmentStore interface {
+	Create(ctx context.Context, attachment *apiv1.PolicyAttachment) error
+	Delete(ctx context.Context, principal model.Principal, owner, name string) error
+	// DeleteCollection deletes all of the attachments of the principal.
+	DeleteCollection(ctx context.Context, principal model.Principal) error
+	// DeletePolicy deletes all of the attachments of the policy, or of all of the policies of the owner
+	// if name is empty.
+	DeletePolicy(ctx context.Context, owner, name string) error
+	List(ctx context.Context, principal model.Principal, opts ListOptions) (*apiv1.PolicyAttachmentList, error)
+	// ListPolicies lists the distinct policies attached to any of the principals.
+	ListPolicies(ctx context.Context, principals []model.Principal) ([]*apiv1.Policy, error)
+}
+
+// AuditFilter selects the audit events, the zero fields match all of the events.
+type AuditFilter struct {
+	Actor string
+	// Resource matches the resource and its sub resources, e.g. `users/alice` matches `users/alice/secrets/xxx`.
+	Resource string
+	// Since and Until select the events created in [Since, Until).
+	Since time.Time
+	Until time.Time
+}
+
+// AuditEventStore defines the audit event storage interface, the events are never changed once created.
+type AuditEventStore interface {
+	Create(ctx context.Context, event *apiv1.AuditEvent) error
+	// List lists the events matching the filter, the latest events first.
+	List(ctx context.Context, filter AuditFilter, opts ListOptions) (*apiv1.AuditEventList, error)
+}
+
+// WatchEventStore defines the storage interface of the watch events, which record the changes of the policies
+// and the secrets. Every change is assigned the next resource version of the storage, and the events become
+// visible in the order of their versions. Only the latest events are kept, the older ones are dropped.
+type WatchEventStore interface {
+	// List lists at most limit events of the kind after the resource version in the order of their versions.
+	// It fails with code.ErrResourceVersionTooOld if any of the events after the version have been dropped.
+	List(ctx context.Context, kind string, after uint64, limit int) ([]*model.WatchEvent, error)
+	// Latest returns the resource version of the latest change of all of the tenants.
+	Latest(ctx context.Context) (uint64, error)
+	// Subscribe returns a channel which receives a value after the new events are recorded, the notifications
+	// are coalesced and may be spurious. The channel is closed when the store is closed, the returned function
+	// unsubscribes.
+	Subscribe() (<-chan struct{}, func())
+}
+
+// NonceStore remembers the nonces of the signed requests for the sign.Verifier, it is not scoped by the tenant.
+// The stores of a database are shared by all of the instances of siam-apiserver, so a request replayed to
+// another instance is rejected as well.
+type NonceStore interface {
+	// Add remembers the nonce until expiresAt, it reports false if the nonce is remembered and not expired at now.
+	Add(ctx context.Context, nonce string, expiresAt, now time.Time) (bool, error)
+}
@@ -0,0 +1,321 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strayca7/siam/internal/apiserver/migrations"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/apiserver/store/database"
+	"github.com/strayca7/siam/internal/apiserver/store/memory"
+	"github.com/strayca7/siam/internal/pkg/code"
+	pkgdatabase "github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/database/migrate"
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/policy"
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "store")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+
+	exit := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(exit)
+}
+
+// factory is a store under test.
+type factory struct {
+	name string
+	new  func(t *testing.T) store.Factory
+}
+
+// factories are the stores under test: the memory store, and the database store of an SQLite database
+// with all of the migrations applied.
+var factories = []factory{
+	{"memory", func(*testing.T) store.Factory { return memory.New() }},
+	{"sqlite", newSQLite},
+}
+
+func newSQLite(t *testing.T) store.Factory {
+	t.Helper()
+	db, err := pkgdatabase.New(&pkgdatabase.Options{
+		Driver: pkgdatabase.SQLite,
+		Path:   filepath.Join(t.TempDir(), "siam.db"),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	s := database.New(db)
+	t.Cleanup(func() { _ = s.Close() })
+
+	sqldb, err := db.DB()
+	if err != nil {
+		t.Fatalf("get database: %v", err)
+	}
+	dialect, err := migrate.LookupDialect(pkgdatabase.SQLite)
+	if err != nil {
+		t.Fatalf("LookupDialect() error = %v", err)
+	}
+	ms, err := migrations.Load(pkgdatabase.SQLite)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := migrate.New(sqldb, dialect, ms).Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return s
+}
+
+// runStores runs the test on every store under test.
+func runStores(t *testing.T, test func(t *testing.T, s store.Factory)) {
+	for _, f := range factories {
+		t.Run(f.name, func(t *testing.T) {
+			test(t, f.new(t))
+		})
+	}
+}
+
+// newPolicy returns a policy of the user which allows everything.
+func newPolicy(username, name string) *apiv1.Policy {
+	return &apiv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("policy-"), Name: name},
+		Username:   username,
+		Document: policy.Document{Version: policy.Version20251001, Statements: []policy.Statement{
+			{Effect: policy.Allow, Actions: []string{"*"}, Resources: []string{"*"}},
+		}},
+	}
+}
+
+func TestTenantScoping(t *testing.T) {
+	runStores(t, func(t *testing.T, s store.Factory) {
+		defaultCtx := context.Background()
+		acme := store.WithTenant(defaultCtx, "acme")
+
+		// the same names are used in both of the tenants
+		for _, ctx := range []context.Context{defaultCtx, acme} {
+			email := store.TenantFromContext(ctx) + "@siam.example"
+			user := &apiv1.User{
+				ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("user-"), Name: "alice"},
+				Email:      email,
+			}
+			if err := s.Users().Create(ctx, user); err != nil {
+				t.Fatalf("create user in %s: %v", store.TenantFromContext(ctx), err)
+			}
+		}
+		if err := s.Policies().Create(acme, newPolicy("alice", "everything")); err != nil {
+			t.Fatalf("create policy: %v", err)
+		}
+		secret := &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("secret-")},
+			Username:   "alice",
+			AccessKey:  "AKACME",
+		}
+		if err := s.Secrets().Create(acme, secret); err != nil {
+			t.Fatalf("create secret: %v", err)
+		}
+		if err := s.Groups().Create(acme, &apiv1.Group{Name: "devs"}); err != nil {
+			t.Fatalf("create group: %v", err)
+		}
+
+		for _, ctx := range []context.Context{defaultCtx, acme} {
+			tenant := store.TenantFromContext(ctx)
+			user, err := s.Users().Get(ctx, "alice")
+			if err != nil {
+				t.Fatalf("get user in %s: %v", tenant, err)
+			}
+			if user.Tenant != tenant || user.Email != tenant+"@siam.example" {
+				t.Errorf("user in %s = %s, %s, want the one of the tenant", tenant, user.Tenant, user.Email)
+			}
+			users, err := s.Users().List(ctx, store.ListOptions{})
+			if err != nil || len(users.Items) != 1 {
+				t.Errorf("list users in %s = %v, %v, want 1 user", tenant, users, err)
+			}
+		}
+
+		// the objects of acme are not found in the default tenant
+		if _, err := s.Policies().Get(defaultCtx, "alice", "everything"); !serrors.IsCode(err, code.ErrPolicyNotFound) {
+			t.Errorf("get policy of acme in default error = %v, want %d", err, code.ErrPolicyNotFound)
+		}
+		if _, err := s.Secrets().Get(defaultCtx, "alice", "AKACME"); !serrors.IsCode(err, code.ErrSecretNotFound) {
+			t.Errorf("get secret of acme in default error = %v, want %d", err, code.ErrSecretNotFound)
+		}
+		if _, err := s.Groups().Get(defaultCtx, "devs"); !serrors.IsCode(err, code.ErrGroupNotFound) {
+			t.Errorf("get group of acme in default error = %v, want %d", err, code.ErrGroupNotFound)
+		}
+		if policies, err := s.Policies().List(defaultCtx, "", store.ListOptions{}); err != nil || len(policies.Items) != 0 {
+			t.Errorf("list policies in default = %v, %v, want none", policies, err)
+		}
+		if secrets, err := s.Secrets().List(defaultCtx, "", store.ListOptions{}); err != nil || len(secrets.Items) != 0 {
+			t.Errorf("list secrets in default = %v, %v, want none", secrets, err)
+		}
+
+		// the access keys are unique across the tenants, so the signed requests find their secrets
+		if got, err := s.Secrets().GetByAccessKey(defaultCtx, "AKACME"); err != nil || got.Tenant != "acme" {
+			t.Errorf("GetByAccessKey() = %v, %v, want the secret of acme", got, err)
+		}
+
+		// the changes of a tenant leave the other tenants alone
+		if err := s.Users().Delete(defaultCtx, "alice"); err != nil {
+			t.Fatalf("delete user in default: %v", err)
+		}
+		if err := s.Policies().DeleteCollection(defaultCtx, "alice"); err != nil {
+			t.Fatalf("delete policies in default: %v", err)
+		}
+		if _, err := s.Users().Get(acme, "alice"); err != nil {
+			t.Errorf("get user in acme after the deletion in default: %v", err)
+		}
+		if _, err := s.Policies().Get(acme, "alice", "everything"); err != nil {
+			t.Errorf("get policy in acme after the deletion in default: %v", err)
+		}
+	})
+}
+
+func TestUpdateConflict(t *testing.T) {
+	runStores(t, func(t *testing.T, s store.Factory) {
+		ctx := context.Background()
+		user := &apiv1.User{ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("user-"), Name: "alice"}}
+		if err := s.Users().Create(ctx, user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		if err := s.Policies().Create(ctx, newPolicy("alice", "everything")); err != nil {
+			t.Fatalf("create policy: %v", err)
+		}
+		secret := &apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{InstanceID: metav1.NewInstanceID("secret-")},
+			Username:   "alice",
+			AccessKey:  "AKALICE",
+		}
+		if err := s.Secrets().Create(ctx, secret); err != nil {
+			t.Fatalf("create secret: %v", err)
+		}
+
+		// get reads the object, and update reads the object, changes it and updates it
+		tests := []struct {
+			name   string
+			get    func() (*metav1.ObjectMeta, error)
+			update func() (*metav1.ObjectMeta, error)
+		}{
+			{
+				name: "user",
+				get: func() (*metav1.ObjectMeta, error) {
+					user, err := s.Users().Get(ctx, "alice")
+					if err != nil {
+						return nil, err
+					}
+					return &user.ObjectMeta, nil
+				},
+				update: func() (*metav1.ObjectMeta, error) {
+					user, err := s.Users().Get(ctx, "alice")
+					if err != nil {
+						return nil, err
+					}
+					user.Nickname += "a"
+					return &user.ObjectMeta, s.Users().Update(ctx, user)
+				},
+			},
+			{
+				name: "policy",
+				get: func() (*metav1.ObjectMeta, error) {
+					pol, err := s.Policies().Get(ctx, "alice", "everything")
+					if err != nil {
+						return nil, err
+					}
+					return &pol.ObjectMeta, nil
+				},
+				update: func() (*metav1.ObjectMeta, error) {
+					pol, err := s.Policies().Get(ctx, "alice", "everything")
+					if err != nil {
+						return nil, err
+					}
+					pol.Description += "a"
+					return &pol.ObjectMeta, s.Policies().Update(ctx, pol)
+				},
+			},
+			{
+				name: "secret",
+				get: func() (*metav1.ObjectMeta, error) {
+					secret, err := s.Secrets().Get(ctx, "alice", "AKALICE")
+					if err != nil {
+						return nil, err
+					}
+					return &secret.ObjectMeta, nil
+				},
+				update: func() (*metav1.ObjectMeta, error) {
+					secret, err := s.Secrets().Get(ctx, "alice", "AKALICE")
+					if err != nil {
+						return nil, err
+					}
+					secret.Description += "a"
+					return &secret.ObjectMeta, s.Secrets().Update(ctx, secret)
+				},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				before, err := tt.get()
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				updated, err := tt.update()
+				if err != nil {
+					t.Fatalf("update: %v", err)
+				}
+				if updated.ResourceVersion <= before.ResourceVersion {
+					t.Errorf("resource version after the update = %d, want more than %d",
+						updated.ResourceVersion, before.ResourceVersion)
+				}
+				after, err := tt.get()
+				if err != nil {
+					t.Fatalf("get after the update: %v", err)
+				}
+				if after.ResourceVersion != updated.ResourceVersion {
+					t.Errorf("stored resource version = %d, want %d", after.ResourceVersion, updated.ResourceVersion)
+				}
+			})
+		}
+
+		// the updates of the objects read before another update are rejected
+		staleUser, err := s.Users().Get(ctx, "alice")
+		if err != nil {
+			t.Fatalf("get user: %v", err)
+		}
+		stalePolicy, err := s.Policies().Get(ctx, "alice", "everything")
+		if err != nil {
+			t.Fatalf("get policy: %v", err)
+		}
+		staleSecret, err := s.Secrets().Get(ctx, "alice", "AKALICE")
+		if err != nil {
+			t.Fatalf("get secret: %v", err)
+		}
+		for _, tt := range tests {
+			if _, err := tt.update(); err != nil {
+				t.Fatalf("update %s: %v", tt.name, err)
+			}
+		}
+		staleUser.Nickname = "stale"
+		if err := s.Users().Update(ctx, staleUser); !serrors.IsCode(err, code.ErrConflict) {
+			t.Errorf("update stale user error = %v, want %d", err, code.ErrConflict)
+		}
+		stalePolicy.Description = "stale"
+		if err := s.Policies().Update(ctx, stalePolicy); !serrors.IsCode(err, code.ErrConflict) {
+			t.Errorf("update stale policy error = %v, want %d", err, code.ErrConflict)
+		}
+		staleSecret.Description = "stale"
+		if err := s.Secrets().Update(ctx, staleSecret); !serrors.IsCode(err, code.ErrConflict) {
+			t.Errorf("update stale secret error = %v, want %d", err, code.ErrConflict)
+		}
+	})
+}
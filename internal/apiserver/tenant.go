@@ -0,0 +1,163 @@
+package apiserver
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/apiserver/model"
+	"github.com/strayca7/siam/internal/apiserver/store"
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/internal/pkg/middleware"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// tenantScope scopes the stores to the tenant of the `tenant` path parameter, which must exist
+// unless it is the default tenant.
+func tenantScope(s store.Factory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant := c.Param("tenant")
+		if tenant != model.DefaultTenant {
+			if _, err := s.Tenants().Get(c.Request.Context(), tenant); err != nil {
+				core.WriteResponse(c, err, nil)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(store.WithTenant(c.Request.Context(), tenant))
+		c.Next()
+	}
+}
+
+// tenantAccess allows the authenticated principal to access only the tenant the stores are scoped to,
+// except the system admins who access all of the tenants.
+func tenantAccess(s store.Factory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tenant, username := principalOf(ctx)
+		if tenant == store.TenantFromContext(ctx) {
+			c.Next()
+			return
+		}
+
+		admin, err := isAdmin(ctx, s, model.DefaultTenant, tenant, username)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+			return
+		}
+		if !admin {
+			core.WriteResponse(c, serrors.WithCodef(code.ErrTenantForbidden, "user %q of tenant %q is not allowed to access tenant %q",
+				username, tenant, store.TenantFromContext(ctx)), nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tenantAdmin allows only the admins of the tenant the stores are scoped to, and the system admins.
+// The admins of the default tenant are the system admins.
+func tenantAdmin(s store.Factory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, err := adminOfScope(c.Request.Context(), s)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+			return
+		}
+		if !admin {
+			_, username := principalOf(c.Request.Context())
+			core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied, "user %q is not an admin of tenant %q",
+				username, store.TenantFromContext(c.Request.Context())), nil)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(middleware.ContextWithAdmin(c.Request.Context(), true))
+		c.Next()
+	}
+}
+
+// ownerOrAdmin allows only the user of the `name` path parameter in the tenant the stores are scoped to,
+// and the admins of tenantAdmin, to manage the user and the objects it owns. Whether the principal is
+// an admin is stored in the request context, see middleware.AdminFromContext.
+func ownerOrAdmin(s store.Factory) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		tenant, username := principalOf(ctx)
+		admin, err := adminOfScope(ctx, s)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+			return
+		}
+		owner := tenant == store.TenantFromContext(ctx) && username == c.Param("name")
+		if !owner && !admin {
+			core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied, "user %q is not allowed to manage user %q",
+				username, c.Param("name")), nil)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(middleware.ContextWithAdmin(ctx, admin))
+		c.Next()
+	}
+}
+
+// noRoleSession rejects the sessions of the assumed roles, which are scoped to the authorization requests
+// with the policies of the roles and never manage the objects.
+func noRoleSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, username := principalOf(c.Request.Context())
+		if role, ok := model.RoleFromSubject(username); ok {
+			core.WriteResponse(c, serrors.WithCodef(code.ErrPermissionDenied,
+				"session of role %q is allowed to make the authorization requests only", role), nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminOfScope reports whether the authenticated principal is an admin of the tenant the stores are scoped to,
+// or a system admin.
+func adminOfScope(ctx context.Context, s store.Factory) (bool, error) {
+	tenant, username := principalOf(ctx)
+	target := store.TenantFromContext(ctx)
+	admin, err := isAdmin(ctx, s, target, tenant, username)
+	if err == nil && !admin && target != model.DefaultTenant {
+		admin, err = isAdmin(ctx, s, model.DefaultTenant, tenant, username)
+	}
+	return admin, err
+}
+
+// principalOf returns the tenant and the username of the authenticated principal.
+func principalOf(ctx context.Context) (tenant, username string) {
+	username, _ = middleware.UsernameFromContext(ctx)
+	tenant, ok := middleware.TenantFromContext(ctx)
+	if !ok {
+		tenant = model.DefaultTenant
+	}
+	return tenant, username
+}
+
+// isAdmin reports whether the principal of the tenant is an admin of the target tenant.
+// The role sessions are never admins.
+func isAdmin(ctx context.Context, s store.Factory, target, tenant, username string) (bool, error) {
+	if tenant != target {
+		return false, nil
+	}
+	if _, ok := model.RoleFromSubject(username); ok {
+		return false, nil
+	}
+	user, err := s.Users().Get(store.WithTenant(ctx, tenant), username)
+	if err != nil {
+		// the user may have been deleted after the token was issued
+		if serrors.IsCode(err, code.ErrUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.IsAdmin, nil
+}
@@ -0,0 +1,120 @@
+// Package bind binds the request of gin.Context to structs and converts the failures into coded errors.
+package bind
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+func init() {
+	// the violations are reported with the names of the fields in the requests instead of the Go ones
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// inlined is the name of the embedded structs without a name in the JSON body, whose fields are inlined.
+const inlined = "-inlined-"
+
+// fieldName returns the name of the field in the JSON body or the URL query, which is the field name
+// if it has neither.
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	if f.Anonymous {
+		return inlined
+	}
+	return f.Name
+}
+
+// JSON binds the request body to obj and validates it with the `binding` struct tags.
+// It returns code.ErrValidation with a serrors.FieldViolation of each invalid field if the body is well-formed
+// but invalid, otherwise code.ErrBind.
+func JSON(c *gin.Context, obj any) error {
+	return convert(c.ShouldBindJSON(obj))
+}
+
+// Query binds the URL query parameters to obj and validates it with the `binding` struct tags.
+func Query(c *gin.Context, obj any) error {
+	return convert(c.ShouldBindQuery(obj))
+}
+
+// List binds the list options in the URL query parameters, the offset is resolved from the continue token
+// if the token is set. It returns code.ErrValidation if the token is malformed.
+func List(c *gin.Context, opts *metav1.ListOptions) error {
+	if err := Query(c, opts); err != nil {
+		return err
+	}
+	offset, err := opts.Start()
+	if err != nil {
+		return serrors.WrapC(serrors.NewFieldViolation("continue", "%v", err), code.ErrValidation,
+			"invalid list options")
+	}
+	opts.Offset = offset
+	return nil
+}
+
+// Meta validates the labels and the annotations of the request, which are not checked by the struct tags.
+// It returns code.ErrValidation if any of them is invalid.
+func Meta(labels, annotations map[string]string) error {
+	var errs []error
+	if err := metav1.ValidateLabels(labels); err != nil {
+		errs = append(errs, serrors.NewFieldViolation("labels", "%v", err))
+	}
+	if err := metav1.ValidateAnnotations(annotations); err != nil {
+		errs = append(errs, serrors.NewFieldViolation("annotations", "%v", err))
+	}
+	if agg := serrors.NewAggregate(errs); agg != nil {
+		return serrors.WrapC(agg, code.ErrValidation, "invalid metadata: %s", agg.Error())
+	}
+	return nil
+}
+
+func convert(err error) error {
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		errs := make([]error, 0, len(verrs))
+		for _, fe := range verrs {
+			errs = append(errs, violation(fe))
+		}
+		agg := serrors.NewAggregate(errs)
+		return serrors.WrapC(agg, code.ErrValidation, "invalid request: %s", agg.Error())
+	}
+	return serrors.WithCode(code.ErrBind, err.Error())
+}
+
+// violation converts the validation failure of a field into its violation, the field is the path in the request
+// like `requests[0].action`.
+func violation(fe validator.FieldError) *serrors.FieldViolation {
+	// the namespace starts with the name of the struct, like `createUserRequest.name`
+	_, field, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		field = fe.Field()
+	}
+	field = strings.ReplaceAll(field, inlined+".", "")
+	tag := fe.Tag()
+	if fe.Param() != "" {
+		tag = fmt.Sprintf("%s=%s", tag, fe.Param())
+	}
+	return serrors.NewFieldViolation(field, "failed the %q validation", tag)
+}
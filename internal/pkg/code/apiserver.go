@@ -2,26 +2,103 @@ package code
 
 // code for API server
 
-// siam-apiserver: secret errors.
+// siam-apiserver: user errors.
 const (
 	// ErrUserNotFound - 404: User not found.
+	// zh-CN: 用户不存在。
 	ErrUserNotFound = iota + 110001
 
 	// ErrUserAlreadyExists - 409: User already exists.
+	// zh-CN: 用户已存在。
 	ErrUserAlreadyExists
 )
 
 // siam-apiserver: secret errors.
 const (
 	// ErrReachMaxCount - 429: Reach max count.
+	// zh-CN: 已达到最大数量。
 	ErrReachMaxCount = iota + 110101
 
 	// ErrSecretNotFound - 404: Secret not found.
+	// zh-CN: 密钥不存在。
 	ErrSecretNotFound
 )
 
 // siam-apiserver: policy errors.
 const (
 	// ErrPolicyNotFound - 404: Policy not found.
+	// zh-CN: 策略不存在。
 	ErrPolicyNotFound = iota + 110201
+
+	// ErrPolicyAlreadyExists - 409: Policy already exists.
+	// zh-CN: 策略已存在。
+	ErrPolicyAlreadyExists
+
+	// ErrAttachmentNotFound - 404: Policy attachment not found.
+	// zh-CN: 策略绑定不存在。
+	ErrAttachmentNotFound
+
+	// ErrAttachmentAlreadyExists - 409: Policy attachment already exists.
+	// zh-CN: 策略绑定已存在。
+	ErrAttachmentAlreadyExists
+)
+
+// siam-apiserver: audit errors.
+const (
+	// ErrAuditQueryUnsupported - 400: Audit sink does not support query.
+	// zh-CN: 审计存储不支持查询。
+	ErrAuditQueryUnsupported = iota + 110301
+)
+
+// siam-apiserver: group errors.
+const (
+	// ErrGroupNotFound - 404: Group not found.
+	// zh-CN: 用户组不存在。
+	ErrGroupNotFound = iota + 110401
+
+	// ErrGroupAlreadyExists - 409: Group already exists.
+	// zh-CN: 用户组已存在。
+	ErrGroupAlreadyExists
+
+	// ErrGroupMemberNotFound - 404: Group member not found.
+	// zh-CN: 用户组成员不存在。
+	ErrGroupMemberNotFound
+
+	// ErrGroupMemberAlreadyExists - 409: Group member already exists.
+	// zh-CN: 用户组成员已存在。
+	ErrGroupMemberAlreadyExists
+)
+
+// siam-apiserver: role errors.
+const (
+	// ErrRoleNotFound - 404: Role not found.
+	// zh-CN: 角色不存在。
+	ErrRoleNotFound = iota + 110501
+
+	// ErrRoleAlreadyExists - 409: Role already exists.
+	// zh-CN: 角色已存在。
+	ErrRoleAlreadyExists
+
+	// ErrRoleNotTrusted - 403: Role is not allowed to be assumed.
+	// zh-CN: 不允许扮演该角色。
+	ErrRoleNotTrusted
+)
+
+// siam-apiserver: tenant errors.
+const (
+	// ErrTenantNotFound - 404: Tenant not found.
+	// zh-CN: 租户不存在。
+	ErrTenantNotFound = iota + 110601
+
+	// ErrTenantAlreadyExists - 409: Tenant already exists.
+	// zh-CN: 租户已存在。
+	ErrTenantAlreadyExists
+
+	// ErrTenantNotEmpty - 409: Tenant is not empty.
+	// zh-CN: 租户不为空。
+	ErrTenantNotEmpty
+
+	// ErrTenantForbidden - 403: Access to the tenant is forbidden.
+	// zh-CN: 禁止访问该租户。
+	ErrTenantForbidden
 )
@@ -0,0 +1,84 @@
+package code
+
+// code for all services
+
+// common: basic errors.
+const (
+	// ErrSuccess - 200: OK.
+	// zh-CN: 成功。
+	ErrSuccess = iota + 100001
+
+	// ErrUnknown - 500: Internal server error.
+	// zh-CN: 服务器内部错误。
+	ErrUnknown
+
+	// ErrBind - 400: Error occurred while binding the request body to the struct.
+	// zh-CN: 请求体绑定到结构体时出错。
+	ErrBind
+
+	// ErrValidation - 400: Validation failed.
+	// zh-CN: 校验失败。
+	ErrValidation
+
+	// ErrPageNotFound - 404: Page not found.
+	// zh-CN: 页面不存在。
+	ErrPageNotFound
+
+	// ErrConflict - 409: Object has been modified, please apply the changes to the latest version.
+	// zh-CN: 对象已被修改，请基于最新版本重新修改。
+	ErrConflict
+
+	// ErrResourceVersionTooOld - 410: Resource version is too old, please list the objects again.
+	// zh-CN: 资源版本过旧，请重新列出对象。
+	ErrResourceVersionTooOld
+)
+
+// common: database errors.
+const (
+	// ErrDatabase - 500: Database error.
+	// zh-CN: 数据库错误。
+	ErrDatabase = iota + 100101
+
+	// ErrDatabaseUnavailable - 503: Database is unavailable.
+	// zh-CN: 数据库不可用。
+	ErrDatabaseUnavailable
+)
+
+// common: authentication and authorization errors.
+const (
+	// ErrEncrypt - 500: Error occurred while encrypting the user password.
+	// zh-CN: 加密用户密码时出错。
+	ErrEncrypt = iota + 100201
+
+	// ErrPasswordIncorrect - 401: Password was incorrect.
+	// zh-CN: 密码错误。
+	ErrPasswordIncorrect
+
+	// ErrTokenInvalid - 401: Token invalid.
+	// zh-CN: 令牌无效。
+	ErrTokenInvalid
+
+	// ErrExpired - 401: Token expired.
+	// zh-CN: 令牌已过期。
+	ErrExpired
+
+	// ErrInvalidAuthHeader - 401: Invalid authorization header.
+	// zh-CN: 认证头无效。
+	ErrInvalidAuthHeader
+
+	// ErrSignatureInvalid - 401: Signature is invalid.
+	// zh-CN: 签名无效。
+	ErrSignatureInvalid
+
+	// ErrRequestTimeSkewed - 401: Request time is out of the allowed window.
+	// zh-CN: 请求时间超出允许范围。
+	ErrRequestTimeSkewed
+
+	// ErrNonceReplayed - 401: Request nonce has been used.
+	// zh-CN: 请求随机数已被使用。
+	ErrNonceReplayed
+
+	// ErrPermissionDenied - 403: Permission denied.
+	// zh-CN: 权限不足。
+	ErrPermissionDenied
+)
@@ -0,0 +1,100 @@
+// Code generated by "codegen"; DO NOT EDIT.
+
+package code
+
+import "github.com/strayca7/siam/pkg/serrors"
+
+// rules are the rules the error codes are validated by when they are registered.
+var rules = &serrors.Rules{
+	HTTPStatuses: []int{200, 400, 401, 403, 404, 409, 410, 429, 500, 503},
+	Ranges: map[string]serrors.Range{
+		"apiserver": {Min: 110001, Max: 119999},
+		"base":      {Min: 100001, Max: 109999},
+	},
+}
+
+// init registers the error codes defined in this package to package serrors.
+func init() {
+	serrors.MustRegisterAll(rules, "base",
+		serrors.Code{C: ErrSuccess, HTTP: 200, Ext: "OK"},
+		serrors.Code{C: ErrUnknown, HTTP: 500, Ext: "Internal server error"},
+		serrors.Code{C: ErrBind, HTTP: 400, Ext: "Error occurred while binding the request body to the struct"},
+		serrors.Code{C: ErrValidation, HTTP: 400, Ext: "Validation failed"},
+		serrors.Code{C: ErrPageNotFound, HTTP: 404, Ext: "Page not found"},
+		serrors.Code{C: ErrConflict, HTTP: 409, Ext: "Object has been modified, please apply the changes to the latest version"},
+		serrors.Code{C: ErrResourceVersionTooOld, HTTP: 410, Ext: "Resource version is too old, please list the objects again"},
+		serrors.Code{C: ErrDatabase, HTTP: 500, Ext: "Database error"},
+		serrors.Code{C: ErrDatabaseUnavailable, HTTP: 503, Ext: "Database is unavailable"},
+		serrors.Code{C: ErrEncrypt, HTTP: 500, Ext: "Error occurred while encrypting the user password"},
+		serrors.Code{C: ErrPasswordIncorrect, HTTP: 401, Ext: "Password was incorrect"},
+		serrors.Code{C: ErrTokenInvalid, HTTP: 401, Ext: "Token invalid"},
+		serrors.Code{C: ErrExpired, HTTP: 401, Ext: "Token expired"},
+		serrors.Code{C: ErrInvalidAuthHeader, HTTP: 401, Ext: "Invalid authorization header"},
+		serrors.Code{C: ErrSignatureInvalid, HTTP: 401, Ext: "Signature is invalid"},
+		serrors.Code{C: ErrRequestTimeSkewed, HTTP: 401, Ext: "Request time is out of the allowed window"},
+		serrors.Code{C: ErrNonceReplayed, HTTP: 401, Ext: "Request nonce has been used"},
+		serrors.Code{C: ErrPermissionDenied, HTTP: 403, Ext: "Permission denied"},
+	)
+	serrors.MustRegisterAll(rules, "apiserver",
+		serrors.Code{C: ErrUserNotFound, HTTP: 404, Ext: "User not found"},
+		serrors.Code{C: ErrUserAlreadyExists, HTTP: 409, Ext: "User already exists"},
+		serrors.Code{C: ErrReachMaxCount, HTTP: 429, Ext: "Reach max count"},
+		serrors.Code{C: ErrSecretNotFound, HTTP: 404, Ext: "Secret not found"},
+		serrors.Code{C: ErrPolicyNotFound, HTTP: 404, Ext: "Policy not found"},
+		serrors.Code{C: ErrPolicyAlreadyExists, HTTP: 409, Ext: "Policy already exists"},
+		serrors.Code{C: ErrAttachmentNotFound, HTTP: 404, Ext: "Policy attachment not found"},
+		serrors.Code{C: ErrAttachmentAlreadyExists, HTTP: 409, Ext: "Policy attachment already exists"},
+		serrors.Code{C: ErrAuditQueryUnsupported, HTTP: 400, Ext: "Audit sink does not support query"},
+		serrors.Code{C: ErrGroupNotFound, HTTP: 404, Ext: "Group not found"},
+		serrors.Code{C: ErrGroupAlreadyExists, HTTP: 409, Ext: "Group already exists"},
+		serrors.Code{C: ErrGroupMemberNotFound, HTTP: 404, Ext: "Group member not found"},
+		serrors.Code{C: ErrGroupMemberAlreadyExists, HTTP: 409, Ext: "Group member already exists"},
+		serrors.Code{C: ErrRoleNotFound, HTTP: 404, Ext: "Role not found"},
+		serrors.Code{C: ErrRoleAlreadyExists, HTTP: 409, Ext: "Role already exists"},
+		serrors.Code{C: ErrRoleNotTrusted, HTTP: 403, Ext: "Role is not allowed to be assumed"},
+		serrors.Code{C: ErrTenantNotFound, HTTP: 404, Ext: "Tenant not found"},
+		serrors.Code{C: ErrTenantAlreadyExists, HTTP: 409, Ext: "Tenant already exists"},
+		serrors.Code{C: ErrTenantNotEmpty, HTTP: 409, Ext: "Tenant is not empty"},
+		serrors.Code{C: ErrTenantForbidden, HTTP: 403, Ext: "Access to the tenant is forbidden"},
+	)
+	serrors.MustRegisterMessages("zh-CN", map[int]string{
+		ErrSuccess:                  "成功",
+		ErrUnknown:                  "服务器内部错误",
+		ErrBind:                     "请求体绑定到结构体时出错",
+		ErrValidation:               "校验失败",
+		ErrPageNotFound:             "页面不存在",
+		ErrConflict:                 "对象已被修改，请基于最新版本重新修改",
+		ErrResourceVersionTooOld:    "资源版本过旧，请重新列出对象",
+		ErrDatabase:                 "数据库错误",
+		ErrDatabaseUnavailable:      "数据库不可用",
+		ErrEncrypt:                  "加密用户密码时出错",
+		ErrPasswordIncorrect:        "密码错误",
+		ErrTokenInvalid:             "令牌无效",
+		ErrExpired:                  "令牌已过期",
+		ErrInvalidAuthHeader:        "认证头无效",
+		ErrSignatureInvalid:         "签名无效",
+		ErrRequestTimeSkewed:        "请求时间超出允许范围",
+		ErrNonceReplayed:            "请求随机数已被使用",
+		ErrPermissionDenied:         "权限不足",
+		ErrUserNotFound:             "用户不存在",
+		ErrUserAlreadyExists:        "用户已存在",
+		ErrReachMaxCount:            "已达到最大数量",
+		ErrSecretNotFound:           "密钥不存在",
+		ErrPolicyNotFound:           "策略不存在",
+		ErrPolicyAlreadyExists:      "策略已存在",
+		ErrAttachmentNotFound:       "策略绑定不存在",
+		ErrAttachmentAlreadyExists:  "策略绑定已存在",
+		ErrAuditQueryUnsupported:    "审计存储不支持查询",
+		ErrGroupNotFound:            "用户组不存在",
+		ErrGroupAlreadyExists:       "用户组已存在",
+		ErrGroupMemberNotFound:      "用户组成员不存在",
+		ErrGroupMemberAlreadyExists: "用户组成员已存在",
+		ErrRoleNotFound:             "角色不存在",
+		ErrRoleAlreadyExists:        "角色已存在",
+		ErrRoleNotTrusted:           "不允许扮演该角色",
+		ErrTenantNotFound:           "租户不存在",
+		ErrTenantAlreadyExists:      "租户已存在",
+		ErrTenantNotEmpty:           "租户不为空",
+		ErrTenantForbidden:          "禁止访问该租户",
+	})
+}
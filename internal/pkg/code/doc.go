@@ -4,13 +4,35 @@ On the base of http error codes below we define our own error codes.
 Each custom error code corresponds to a specific http error code.
 Each http error code at least corresponds to one custom error code.
 
-siam code only allowed the following http code:
+siam code only allowed the following http code, 409, 410, 429 and 503 are allowed for the conflicting
+updates, the expired resource versions of the watches, the rate limits and the unavailable dependencies,
+which the clients handle differently from the other client and server errors:
 
 	StatusOK                           = 200 // RFC 7231, 6.3.
 	StatusBadRequest                   = 400 // RFC 7231, 6.5.1
 	StatusUnauthorized                 = 401 // RFC 7235, 3.1
 	StatusForbidden                    = 403 // RFC 7231, 6.5.3
 	StatusNotFound                     = 404 // RFC 7231, 6.5.4
+	StatusConflict                     = 409 // RFC 7231, 6.5.8
+	StatusGone                         = 410 // RFC 7231, 6.5.9
+	StatusTooManyRequests              = 429 // RFC 6585, 4
 	StatusInternalServerError          = 500 // RFC 7231, 6.6.1
+	StatusServiceUnavailable           = 503 // RFC 7231, 6.6.4
+
+The error codes of each service are in the range of the service:
+
+	base       100001 - 109999 // base.go, the codes shared by all of the services
+	apiserver  110001 - 119999 // apiserver.go
+
+Every error code is documented by a comment of the form `// ErrUserNotFound - 404: User not found.`,
+which gives the http code and the external message of the error code. The comments are compiled into
+code_generated.go, which registers the error codes when the package is imported, into the error code
+reference document docs/error_code_generated.md, and into the constants of the API package
+github.com/strayca7/siam/staging/src/api/apiserver/v1 for the clients out of this module. The http codes and the ranges above are enforced by the
+go:generate directive below, both when the error codes are generated and when they are registered,
+and the generation fails if the http codes listed above are not the ones of the directive.
+Run `make gen` after changing the error codes, and `make gen.check` to check them.
 */
 package code
+
+//go:generate codegen -statuses 200,400,401,403,404,409,410,429,500,503 -range base=100001-109999 -range apiserver=110001-119999 -doc ../../../docs/error_code_generated.md -public ../../../staging/src/api/apiserver/v1/code_generated.go
@@ -0,0 +1,49 @@
+// Package etag implements the ETag and the If-Match headers with the resource versions of the objects,
+// the entity tag of an object is its quoted resource version.
+package etag
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/serrors"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// Set sets the ETag response header to the entity tag of the object.
+func Set(c *gin.Context, meta *metav1.ObjectMeta) {
+	c.Header("ETag", `"`+strconv.FormatUint(meta.ResourceVersion, 10)+`"`)
+}
+
+// Check checks the object is of the version the client expects, which is given by the If-Match header
+// or by the resource version in the request body, both are optional. It returns code.ErrConflict if not,
+// whose serrors.Metadata is the current resource version so that the clients can refetch the object.
+func Check(c *gin.Context, meta *metav1.ObjectMeta, version string) error {
+	current := strconv.FormatUint(meta.ResourceVersion, 10)
+	if version != "" && version != current {
+		return serrors.WithDetails(
+			serrors.WithCodef(code.ErrConflict, "resource version %s is not the current version %s", version, current),
+			serrors.Metadata{"resourceVersion": current})
+	}
+	if header := c.GetHeader("If-Match"); header != "" && !matches(header, current) {
+		return serrors.WithDetails(
+			serrors.WithCodef(code.ErrConflict, "If-Match %s does not match the current entity tag %q", header, current),
+			serrors.Metadata{"resourceVersion": current})
+	}
+	return nil
+}
+
+// matches reports whether any entity tag of the If-Match header matches the version,
+// the weak entity tags never match.
+func matches(header, version string) bool {
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == `"`+version+`"` {
+			return true
+		}
+	}
+	return false
+}
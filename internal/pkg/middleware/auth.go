@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/auth"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+)
+
+// Keys in gin context which represent the authenticated principal.
+const (
+	// UsernameKey is the key of the authenticated user.
+	UsernameKey = "username"
+	// TenantKey is the key of the tenant of the authenticated user.
+	TenantKey = "tenant"
+)
+
+const bearerScheme = "Bearer"
+
+type (
+	usernameContextKey struct{}
+	tenantContextKey   struct{}
+	adminContextKey    struct{}
+)
+
+// ContextWithUsername returns a new context carrying the authenticated username.
+func ContextWithUsername(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, usernameContextKey{}, username)
+}
+
+// UsernameFromContext returns the authenticated username stored in the context, if any.
+func UsernameFromContext(ctx context.Context) (string, bool) {
+	username, ok := ctx.Value(usernameContextKey{}).(string)
+	return username, ok && username != ""
+}
+
+// ContextWithTenant returns a new context carrying the tenant of the authenticated user.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the authenticated user stored in the context, if any.
+// It is not set for the tokens issued before the tenants, which belong to the default tenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// ContextWithAdmin returns a new context carrying whether the authenticated user is an admin of the tenant
+// of the request, it is set by the authorization of the routes which check it.
+func ContextWithAdmin(ctx context.Context, admin bool) context.Context {
+	return context.WithValue(ctx, adminContextKey{}, admin)
+}
+
+// AdminFromContext reports whether the authenticated user is known to be an admin of the tenant of the request.
+func AdminFromContext(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminContextKey{}).(bool)
+	return admin
+}
+
+// ClaimsFunc checks the claims of a token whose signature has been verified against the current state,
+// e.g. the subject still exists. The returned errors must be coded and are kept as is.
+type ClaimsFunc func(ctx context.Context, claims *auth.Claims) error
+
+// BearerAuth authenticates the request with the JSON web token in the `Authorization: Bearer <token>` header,
+// and checks its claims by the ClaimsFunc if it is not nil.
+// The subject and the tenant of the token are stored in both the gin context and the request context.
+func BearerAuth(j *auth.JWT, check ClaimsFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, bearerScheme) || token == "" {
+			core.WriteResponse(c, serrors.WithCode(code.ErrInvalidAuthHeader, "authorization header is not a bearer token"), nil)
+			c.Abort()
+			return
+		}
+
+		claims, err := j.Parse(token)
+		if err != nil {
+			if errors.Is(err, auth.ErrTokenExpired) {
+				core.WriteResponse(c, serrors.WrapC(err, code.ErrExpired, "token is expired"), nil)
+			} else {
+				core.WriteResponse(c, serrors.WrapC(err, code.ErrTokenInvalid, "token is invalid"), nil)
+			}
+			c.Abort()
+			return
+		}
+		if check != nil {
+			if err := check(c.Request.Context(), claims); err != nil {
+				core.WriteResponse(c, err, nil)
+				c.Abort()
+				return
+			}
+		}
+
+		setPrincipal(c, claims.Tenant, claims.Subject)
+		c.Next()
+	}
+}
+
+// setPrincipal stores the authenticated username and its tenant in both the gin context and the request context.
+func setPrincipal(c *gin.Context, tenant, username string) {
+	c.Set(UsernameKey, username)
+	c.Set(TenantKey, tenant)
+	ctx := ContextWithUsername(c.Request.Context(), username)
+	c.Request = c.Request.WithContext(ContextWithTenant(ctx, tenant))
+}
+
+// AutoAuth dispatches the authentication by the scheme of the Authorization header,
+// `Bearer` goes to BearerAuth and `SIAM-HMAC-SHA256` goes to SignatureAuth.
+func AutoAuth(j *auth.JWT, check ClaimsFunc, v *sign.Verifier) gin.HandlerFunc {
+	bearer := BearerAuth(j, check)
+	signature := SignatureAuth(v)
+
+	return func(c *gin.Context) {
+		scheme, _, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		switch {
+		case strings.EqualFold(scheme, bearerScheme):
+			bearer(c)
+		case scheme == sign.Algorithm:
+			signature(c)
+		default:
+			core.WriteResponse(c, serrors.WithCodef(code.ErrInvalidAuthHeader,
+				"authorization scheme %q is not supported", scheme), nil)
+			c.Abort()
+		}
+	}
+}
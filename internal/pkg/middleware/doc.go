@@ -0,0 +1,2 @@
+// Package middleware provides the gin middlewares shared by the siam HTTP servers.
+package middleware
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/database"
+)
+
+// HeaderReadPrimary asks to read from the primary database when it is true,
+// a client sets it to read its own writes right after them.
+const HeaderReadPrimary = "X-Siam-Read-Primary"
+
+// ReadPrimary routes the database reads of the request to the primary database
+// if the request sets the HeaderReadPrimary header.
+func ReadPrimary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if primary, _ := strconv.ParseBool(c.GetHeader(HeaderReadPrimary)); primary {
+			c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// WritePrimary routes the database reads of the requests which modify the resources to the primary database,
+// so that their read-modify-write does not read a stale replica.
+func WritePrimary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/core"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+)
+
+// SignatureAuth authenticates the request signed with the SIAM-HMAC-SHA256 scheme of package sign.
+// The owner of the access key returned by the sign.KeyFunc is built by SignatureOwner,
+// its username and tenant are stored in both the gin context and the request context.
+func SignatureAuth(v *sign.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owner, err := v.Verify(c.Request)
+		if err != nil {
+			core.WriteResponse(c, signatureError(err), nil)
+			c.Abort()
+			return
+		}
+
+		tenant, username, ok := strings.Cut(owner, "/")
+		if !ok {
+			tenant, username = "", owner
+		}
+		setPrincipal(c, tenant, username)
+		c.Next()
+	}
+}
+
+// SignatureOwner returns the owner of an access key which is returned by the sign.KeyFunc of SignatureAuth.
+func SignatureOwner(tenant, username string) string {
+	return tenant + "/" + username
+}
+
+// signatureError converts the verification failure into a coded error,
+// the errors returned by the sign.KeyFunc must be coded already and are kept as is.
+func signatureError(err error) error {
+	switch {
+	case errors.Is(err, sign.ErrMalformed):
+		return serrors.WrapC(err, code.ErrInvalidAuthHeader, "malformed signature")
+	case errors.Is(err, sign.ErrTimeSkewed):
+		return serrors.WrapC(err, code.ErrRequestTimeSkewed, "request time is skewed")
+	case errors.Is(err, sign.ErrNonceReplayed):
+		return serrors.WrapC(err, code.ErrNonceReplayed, "request is replayed")
+	case errors.Is(err, sign.ErrBodyTooLarge):
+		return serrors.WrapC(err, code.ErrBind, "signed request body is too large")
+	case errors.Is(err, sign.ErrSignatureMismatch):
+		return serrors.WrapC(err, code.ErrSignatureInvalid, "signature mismatch")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/strayca7/siam/pkg/logger"
+)
+
+// Trace extracts the W3C trace context from the incoming request, or starts a new trace if absent,
+// stores it in the request context and echoes the traceparent header in the response.
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, tc := logger.WithIncomingRequest(c.Request.Context(), c.Request.Header)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(logger.HeaderTraceParent, logger.FormatTraceParent(tc))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,208 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/strayca7/siam/internal/pkg/code"
+	"github.com/strayca7/siam/pkg/database"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+var (
+	drivers         = []string{database.Postgres, database.MySQL, database.SQLite}
+	postgresSSLMode = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	mysqlSSLMode    = []string{"disable", "true", "skip-verify", "preferred"}
+)
+
+// Database defines the configuration options for the database, the connection options are
+// used by postgres and mysql, and the path is used by sqlite.
+type Database struct {
+	Driver          string `json:"driver"          mapstructure:"driver"`
+	Host            string `json:"host"            mapstructure:"host"`
+	User            string `json:"user"            mapstructure:"user"`
+	Password        string `json:"password"        mapstructure:"password"`
+	Database        string `json:"database"        mapstructure:"database"`
+	Port            int    `json:"port"            mapstructure:"port"`
+	SSLMode         string `json:"sslMode"         mapstructure:"sslMode"`
+	TimeZone        string `json:"timeZone"        mapstructure:"timeZone"`
+	Path            string `json:"path"            mapstructure:"path"`
+	MaxIdleConns    int    `json:"maxIdleConns"    mapstructure:"maxIdleConns"`
+	MaxOpenConns    int    `json:"maxOpenConns"    mapstructure:"maxOpenConns"`
+	ConnMaxIdleTime int    `json:"connMaxIdleTime" mapstructure:"connMaxIdleTime"`
+	ConnMaxLifetime int    `json:"connMaxLifetime" mapstructure:"connMaxLifetime"`
+	// ConnectTimeout is the deadline of the connection retries on start.
+	ConnectTimeout time.Duration `json:"connectTimeout" mapstructure:"connectTimeout"`
+	// HealthCheckInterval is the interval to ping the database and log the pool stats, 0 disables it.
+	HealthCheckInterval time.Duration `json:"healthCheckInterval" mapstructure:"healthCheckInterval"`
+	// Replicas are the `host[:port]` addresses of the read-only replicas of postgres or mysql,
+	// the unhealthy replicas are ejected by the health check.
+	Replicas []string `json:"replicas" mapstructure:"replicas"`
+}
+
+// NewDatabase creates a `zero` value instance.
+func NewDatabase() *Database {
+	return &Database{
+		Driver:              database.Postgres,
+		Host:                "localhost",
+		User:                "",
+		Password:            "",
+		Database:            "",
+		Port:                0,
+		SSLMode:             "disable",
+		TimeZone:            "Asia/Shanghai",
+		Path:                "",
+		MaxIdleConns:        100,
+		MaxOpenConns:        100,
+		ConnMaxIdleTime:     10,
+		ConnMaxLifetime:     30,
+		ConnectTimeout:      30 * time.Second,
+		HealthCheckInterval: time.Minute,
+	}
+}
+
+// Flags adds flags for the Database options to the specified FlagSet.
+func (o *Database) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Driver, "database.driver", o.Driver,
+		"Database driver, supported values: postgres, mysql, sqlite.")
+	fs.StringVar(&o.Host, "database.host", o.Host, "Database service host address.")
+	fs.StringVar(&o.User, "database.user", o.User, "Username for access to database service.")
+	fs.StringVar(&o.Password, "database.password", o.Password, "Password for access to database service.")
+	fs.StringVar(&o.Database, "database.database", o.Database, "Database name for the server to use.")
+	fs.IntVar(&o.Port, "database.port", o.Port,
+		"Database service port, 0 means the default port of the driver: 5432 for postgres, 3306 for mysql.")
+	fs.StringVar(&o.SSLMode, "database.sslMode", o.SSLMode,
+		"SSL mode of the connection, the sslmode of postgres or the tls parameter of mysql.")
+	fs.StringVar(&o.TimeZone, "database.timeZone", o.TimeZone, "Time zone of the database session.")
+	fs.StringVar(&o.Path, "database.path", o.Path, "Database file of sqlite.")
+	fs.StringSliceVar(&o.Replicas, "database.replicas", o.Replicas,
+		"Comma separated host[:port] addresses of the read-only replicas of postgres or mysql, "+
+			"which share the other connection options with the primary.")
+	fs.IntVar(&o.MaxIdleConns, "database.maxIdleConns", o.MaxIdleConns,
+		"Maximum idle connections allowed to connect to database.")
+	fs.IntVar(&o.MaxOpenConns, "database.maxOpenConns", o.MaxOpenConns,
+		"Maximum open connections allowed to connect to database.")
+	fs.IntVar(&o.ConnMaxIdleTime, "database.connMaxIdleTime", o.ConnMaxIdleTime,
+		"Maximum minutes a connection may be idle.")
+	fs.IntVar(&o.ConnMaxLifetime, "database.connMaxLifetime", o.ConnMaxLifetime,
+		"Maximum minutes a connection may be reused.")
+	fs.DurationVar(&o.ConnectTimeout, "database.connectTimeout", o.ConnectTimeout,
+		"Deadline to retry the connection to database on start, 0 tries only once.")
+	fs.DurationVar(&o.HealthCheckInterval, "database.healthCheckInterval", o.HealthCheckInterval,
+		"Interval to ping database and log the connection pool stats, 0 disables the health check.")
+}
+
+// Validate checks the Database options of the driver and returns all of the found errors.
+func (o *Database) Validate() []error {
+	var errs []error
+	switch o.Driver {
+	case database.Postgres:
+		errs = append(errs, o.validateServer()...)
+		if !slices.Contains(postgresSSLMode, o.SSLMode) {
+			errs = append(errs, fmt.Errorf("database.sslMode %q must be one of %v for postgres",
+				o.SSLMode, postgresSSLMode))
+		}
+	case database.MySQL:
+		errs = append(errs, o.validateServer()...)
+		if !slices.Contains(mysqlSSLMode, o.SSLMode) {
+			errs = append(errs, fmt.Errorf("database.sslMode %q must be one of %v for mysql",
+				o.SSLMode, mysqlSSLMode))
+		}
+		if _, err := time.LoadLocation(o.TimeZone); err != nil {
+			errs = append(errs, fmt.Errorf("database.timeZone %q is not a valid time zone", o.TimeZone))
+		}
+	case database.SQLite:
+		if o.Path == "" {
+			errs = append(errs, fmt.Errorf("database.path must not be empty for sqlite"))
+		}
+		if len(o.Replicas) > 0 {
+			errs = append(errs, fmt.Errorf("database.replicas is not supported by sqlite"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("database.driver %q must be one of %v", o.Driver, drivers))
+	}
+	if o.MaxIdleConns > o.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database.maxIdleConns %d must not exceed database.maxOpenConns %d",
+			o.MaxIdleConns, o.MaxOpenConns))
+	}
+	if o.ConnectTimeout < 0 {
+		errs = append(errs, fmt.Errorf("database.connectTimeout %s must not be negative", o.ConnectTimeout))
+	}
+	if o.HealthCheckInterval < 0 {
+		errs = append(errs, fmt.Errorf("database.healthCheckInterval %s must not be negative", o.HealthCheckInterval))
+	}
+	return errs
+}
+
+// validateServer checks the connection options of the database service.
+func (o *Database) validateServer() []error {
+	var errs []error
+	if o.Host == "" {
+		errs = append(errs, fmt.Errorf("database.host must not be empty"))
+	}
+	if o.Database == "" {
+		errs = append(errs, fmt.Errorf("database.database must not be empty"))
+	}
+	if o.Port < 0 || o.Port > 65535 {
+		errs = append(errs, fmt.Errorf("database.port %d must be between 0 and 65535", o.Port))
+	}
+	for i, addr := range o.Replicas {
+		if err := validateAddress(addr); err != nil {
+			errs = append(errs, fmt.Errorf("database.replicas[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// validateAddress checks the address is a host with an optional port.
+func validateAddress(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// the address without a port
+		host, port = addr, ""
+	}
+	if host == "" {
+		return fmt.Errorf("%q has no host", addr)
+	}
+	if port != "" {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("%q has an invalid port", addr)
+		}
+	}
+	return nil
+}
+
+// NewDatabaseCli creates a new gorm db instance with the given options, the connection retries are
+// logged to log. It returns an ErrDatabaseUnavailable error if the database can not be connected.
+func (o *Database) NewDatabaseCli(log *zap.Logger) (*gorm.DB, error) {
+	opts := &database.Options{
+		Driver:          o.Driver,
+		Host:            o.Host,
+		User:            o.User,
+		Password:        o.Password,
+		Database:        o.Database,
+		Port:            o.Port,
+		SSLMode:         o.SSLMode,
+		TimeZone:        o.TimeZone,
+		Path:            o.Path,
+		MaxIdleConns:    o.MaxIdleConns,
+		MaxOpenConns:    o.MaxOpenConns,
+		ConnMaxIdleTime: o.ConnMaxIdleTime,
+		ConnMaxLifetime: o.ConnMaxLifetime,
+		ConnectTimeout:  o.ConnectTimeout,
+		Replicas:        o.Replicas,
+		Logger:          log,
+	}
+	db, err := database.New(opts)
+	if err != nil {
+		return nil, serrors.WrapC(err, code.ErrDatabaseUnavailable, "open database")
+	}
+	return db, nil
+}
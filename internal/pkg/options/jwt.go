@@ -0,0 +1,83 @@
+package options
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/pkg/auth"
+)
+
+// knownKeys are the HS256 keys published with the sample configurations, anyone can forge a token signed
+// by them.
+var knownKeys = []string{"siam-development-jwt-key-change-me"}
+
+// JWT defines the configuration options for issuing and verifying the JSON web tokens.
+type JWT struct {
+	// Algorithm is the signing algorithm, one of HS256, RS256 and EdDSA.
+	Algorithm string `json:"algorithm" mapstructure:"algorithm"`
+	// Key is the shared secret of HS256.
+	Key string `json:"key" mapstructure:"key"`
+	// PrivateKeyFile is the PEM encoded private key file of RS256 and EdDSA.
+	PrivateKeyFile string        `json:"privateKeyFile" mapstructure:"privateKeyFile"`
+	Issuer         string        `json:"issuer"         mapstructure:"issuer"`
+	Timeout        time.Duration `json:"timeout"        mapstructure:"timeout"`
+}
+
+// NewJWT creates a JWT instance with default values.
+func NewJWT() *JWT {
+	return &JWT{
+		Algorithm: auth.HS256,
+		Issuer:    "siam",
+		Timeout:   2 * time.Hour,
+	}
+}
+
+// Flags adds flags for the JWT options to the specified FlagSet.
+func (o *JWT) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Algorithm, "jwt.algorithm", o.Algorithm,
+		"Signing algorithm of the tokens, supported values: HS256, RS256, EdDSA.")
+	fs.StringVar(&o.Key, "jwt.key", o.Key, "Shared secret to sign the tokens, required by HS256.")
+	fs.StringVar(&o.PrivateKeyFile, "jwt.privateKeyFile", o.PrivateKeyFile,
+		"PEM encoded private key file to sign the tokens, required by RS256 and EdDSA.")
+	fs.StringVar(&o.Issuer, "jwt.issuer", o.Issuer, "Issuer of the tokens.")
+	fs.DurationVar(&o.Timeout, "jwt.timeout", o.Timeout, "Lifetime of the tokens.")
+}
+
+// Validate checks the JWT options and returns all of the found errors.
+func (o *JWT) Validate() []error {
+	var errs []error
+	switch o.Algorithm {
+	case auth.HS256:
+		if len(o.Key) < 32 {
+			errs = append(errs, fmt.Errorf("jwt.key must be at least 32 bytes for %s", o.Algorithm))
+		} else if slices.Contains(knownKeys, o.Key) {
+			errs = append(errs, fmt.Errorf("jwt.key must not be the published sample key"))
+		}
+	case auth.RS256, auth.EdDSA:
+		if o.PrivateKeyFile == "" {
+			errs = append(errs, fmt.Errorf("jwt.privateKeyFile must not be empty for %s", o.Algorithm))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("jwt.algorithm %q is not supported", o.Algorithm))
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("jwt.timeout %s must be positive", o.Timeout))
+	}
+	return errs
+}
+
+// NewAuthJWT creates a token signer and verifier with the given options.
+func (o *JWT) NewAuthJWT() (*auth.JWT, error) {
+	key := []byte(o.Key)
+	if o.Algorithm != auth.HS256 {
+		var err error
+		if key, err = os.ReadFile(o.PrivateKeyFile); err != nil {
+			return nil, fmt.Errorf("read jwt private key: %w", err)
+		}
+	}
+	return auth.NewJWT(o.Algorithm, key, o.Issuer, o.Timeout)
+}
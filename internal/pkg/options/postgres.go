@@ -1,58 +0,0 @@
-package options
-
-import (
-	"gorm.io/gorm"
-
-	"github.com/strayca7/siam/pkg/database"
-)
-
-// Postgres defines the configuration options for the Postgres database.
-type Postgres struct {
-	Host            string `json:"host"            mapstructure:"host"`
-	User            string `json:"user"            mapstructure:"user"`
-	Password        string `json:"password"        mapstructure:"password"`
-	Database        string `json:"database"        mapstructure:"database"`
-	Port            int    `json:"port"            mapstructure:"port"`
-	SSLMode         string `json:"sslMode"         mapstructure:"sslMode"`
-	TimeZone        string `json:"timeZone"        mapstructure:"timeZone"`
-	MaxIdleConns    int    `json:"maxIdleConns"    mapstructure:"maxIdleConns"`
-	MaxOpenConns    int    `json:"maxOpenConns"    mapstructure:"maxOpenConns"`
-	ConnMaxIdleTime int    `json:"connMaxIdleTime" mapstructure:"connMaxIdleTime"`
-	ConnMaxLifetime int    `json:"connMaxLifetime" mapstructure:"connMaxLifetime"`
-}
-
-// NewPostgres creates a `zero` value instance.
-func NewPostgres() *Postgres {
-	return &Postgres{
-		Host:            "localhost",
-		User:            "",
-		Password:        "",
-		Database:        "",
-		Port:            5432,
-		SSLMode:         "disable",
-		TimeZone:        "Asia/Shanghai",
-		MaxIdleConns:    100,
-		MaxOpenConns:    100,
-		ConnMaxIdleTime: 10,
-		ConnMaxLifetime: 30,
-	}
-}
-
-// NewPostgresCli creates a new gorm db instance with the given options.
-// This logic is waiting to split into options and db package.
-func (o *Postgres) NewPostgresCli() (*gorm.DB, error) {
-	opts := &database.PostgresOptions{
-		Host:            o.Host,
-		User:            o.User,
-		Password:        o.Password,
-		Database:        o.Database,
-		Port:            o.Port,
-		SSLMode:         o.SSLMode,
-		TimeZone:        o.TimeZone,
-		MaxIdleConns:    o.MaxIdleConns,
-		MaxOpenConns:    o.MaxOpenConns,
-		ConnMaxIdleTime: o.ConnMaxIdleTime,
-		ConnMaxLifetime: o.ConnMaxLifetime,
-	}
-	return database.New(opts)
-}
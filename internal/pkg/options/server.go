@@ -0,0 +1,72 @@
+package options
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/pflag"
+)
+
+// Server defines the configuration options for the generic HTTP server.
+type Server struct {
+	// Mode is the gin running mode, one of debug, release or test.
+	Mode        string `json:"mode"        mapstructure:"mode"`
+	BindAddress string `json:"bindAddress" mapstructure:"bindAddress"`
+	BindPort    int    `json:"bindPort"    mapstructure:"bindPort"`
+	// Healthz installs the /healthz route if true.
+	Healthz bool `json:"healthz" mapstructure:"healthz"`
+	// ShutdownTimeout is the seconds to wait for in-flight requests when shutting down.
+	ShutdownTimeout int `json:"shutdownTimeout" mapstructure:"shutdownTimeout"`
+	// MessageCatalogs are the YAML or JSON files of the translated error messages, see serrors.Catalog.
+	MessageCatalogs []string `json:"messageCatalogs" mapstructure:"messageCatalogs"`
+}
+
+// NewServer creates a Server instance with default values.
+func NewServer() *Server {
+	return &Server{
+		Mode:            gin.ReleaseMode,
+		BindAddress:     "0.0.0.0",
+		BindPort:        8080,
+		Healthz:         true,
+		ShutdownTimeout: 10,
+	}
+}
+
+// Address returns the host:port the server listens on.
+func (o *Server) Address() string {
+	return net.JoinHostPort(o.BindAddress, strconv.Itoa(o.BindPort))
+}
+
+// Flags adds flags for the server options to the specified FlagSet.
+func (o *Server) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Mode, "server.mode", o.Mode, "Server running mode, supported values: debug, release, test.")
+	fs.StringVar(&o.BindAddress, "server.bindAddress", o.BindAddress, "The IP address on which to serve.")
+	fs.IntVar(&o.BindPort, "server.bindPort", o.BindPort, "The port on which to serve.")
+	fs.BoolVar(&o.Healthz, "server.healthz", o.Healthz, "Install the /healthz route.")
+	fs.IntVar(&o.ShutdownTimeout, "server.shutdownTimeout", o.ShutdownTimeout,
+		"Seconds to wait for in-flight requests before the server is forcibly stopped.")
+	fs.StringSliceVar(&o.MessageCatalogs, "server.messageCatalogs", o.MessageCatalogs,
+		"The YAML or JSON files of the translated error messages, which override the built-in translations.")
+}
+
+// Validate checks the server options and returns all of the found errors.
+func (o *Server) Validate() []error {
+	var errs []error
+	switch o.Mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Errorf("server.mode %q is not supported", o.Mode))
+	}
+	if net.ParseIP(o.BindAddress) == nil {
+		errs = append(errs, fmt.Errorf("server.bindAddress %q is not a valid IP address", o.BindAddress))
+	}
+	if o.BindPort < 1 || o.BindPort > 65535 {
+		errs = append(errs, fmt.Errorf("server.bindPort %d must be between 1 and 65535", o.BindPort))
+	}
+	if o.ShutdownTimeout < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdownTimeout %d must not be negative", o.ShutdownTimeout))
+	}
+	return errs
+}
@@ -1,12 +1,5 @@
 package util
 
-import "path/filepath"
-
-// service names
-var (
-	APIServer = "apiserver"
-)
-
 const (
 	YAML = "yaml"
 	JSON = "json"
@@ -15,12 +8,4 @@ const (
 // base paths
 const (
 	BaseConfigPath = "./configs"
-	BaseCodePath   = "internal/pkg/code"
-)
-
-// specific paths
-var (
-	CodePath = map[string]string{
-		"apiserver": filepath.Join(BaseCodePath, "apiserver.go"),
-	}
 )
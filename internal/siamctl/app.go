@@ -0,0 +1,36 @@
+// Package siamctl implements siamctl, the command line client of siam-apiserver.
+package siamctl
+
+import (
+	"github.com/strayca7/siam/internal/siamctl/cmd"
+	"github.com/strayca7/siam/pkg/app"
+)
+
+const commandDesc = `siamctl controls the SIAM API server.
+
+Run "siamctl login" first, the token and the server address are cached under $HOME/.siam
+and used by the other commands.`
+
+// NewApp creates an App object with the sub commands.
+func NewApp(basename string) *app.App {
+	return app.NewApp("SIAM Control",
+		basename,
+		app.WithDescription(commandDesc),
+		app.WithDefaultValidArgs(),
+		app.WithSilence(),
+		app.WithNoConfig(),
+		// TODO: remove it after the version flag is implemented in component-base.
+		app.WithNoVersion(),
+		app.WithCommands(
+			cmd.NewLoginCommand(),
+			cmd.NewUserCommand(),
+			cmd.NewSecretCommand(),
+			cmd.NewPolicyCommand(),
+			cmd.NewGroupCommand(),
+			cmd.NewRoleCommand(),
+			cmd.NewAttachmentCommand(),
+			cmd.NewAuditCommand(),
+			cmd.NewTenantCommand(),
+		),
+	)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Credentials is the login state cached by `siamctl login`.
+type Credentials struct {
+	Server    string    `json:"server"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Username  string    `json:"username"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// credentialsPath returns $HOME/.siam/credentials.json.
+func credentialsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".siam", "credentials.json"), nil
+}
+
+// LoadCredentials loads the cached credentials, it returns empty credentials if nothing is cached.
+func LoadCredentials() (*Credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Credentials{}, nil
+		}
+		return nil, err
+	}
+
+	creds := &Credentials{}
+	if err := json.Unmarshal(data, creds); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// Save caches the credentials, the file is only readable by the current user.
+func (c *Credentials) Save() error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
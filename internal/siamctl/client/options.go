@@ -0,0 +1,82 @@
+// Package client creates the siam-apiserver client of siamctl from the flags and the cached credentials.
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	clientv1 "github.com/strayca7/siam/staging/src/client/apiserver/v1"
+)
+
+// Options defines the flags to connect to siam-apiserver, the unset values fall back to the cached credentials.
+type Options struct {
+	Server string
+	Token  string
+	Tenant string
+}
+
+// NewOptions creates an Options instance with default values.
+func NewOptions() *Options {
+	return &Options{}
+}
+
+// Flags adds flags for the client options to the specified FlagSet.
+func (o *Options) Flags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Server, "server", o.Server,
+		"Address of siam-apiserver, like http://127.0.0.1:8080. Defaults to the server of the last login.")
+	fs.StringVar(&o.Token, "token", o.Token, "Bearer token to authenticate with. Defaults to the token of the last login.")
+	fs.StringVar(&o.Tenant, "tenant", o.Tenant,
+		"Tenant of the objects, the requests are sent to its API paths. Defaults to the tenant of the last login.")
+}
+
+// Validate checks the client options and returns all of the found errors.
+func (o *Options) Validate() []error {
+	var errs []error
+	if o.Server != "" {
+		if u, err := url.Parse(o.Server); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("server %q must be an absolute URL", o.Server))
+		}
+	}
+	return errs
+}
+
+// Complete fills the unset options with the cached credentials and returns the username of the last login.
+func (o *Options) Complete() (string, error) {
+	creds, err := LoadCredentials()
+	if err != nil {
+		return "", err
+	}
+	if o.Server == "" {
+		o.Server = creds.Server
+	}
+	if o.Tenant == "" {
+		o.Tenant = creds.Tenant
+	}
+	if o.Token == "" && creds.Token != "" {
+		if time.Now().After(creds.ExpiresAt) {
+			return "", errors.New("the cached token is expired, please run login again")
+		}
+		o.Token = creds.Token
+	}
+	if o.Server == "" {
+		return "", errors.New("server is not set, please run login or use --server")
+	}
+	return creds.Username, nil
+}
+
+// NewClient completes the options and creates a client, the username of the last login is returned too.
+func (o *Options) NewClient() (*clientv1.Client, string, error) {
+	username, err := o.Complete()
+	if err != nil {
+		return nil, "", err
+	}
+	c, err := clientv1.New(clientv1.Config{Server: o.Server, Tenant: o.Tenant, Token: o.Token})
+	if err != nil {
+		return nil, "", err
+	}
+	return c, username, nil
+}
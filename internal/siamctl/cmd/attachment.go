@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+const (
+	principalUsage = "PRINCIPAL is user/NAME, group/NAME or role/NAME"
+	policyUsage    = "POLICY is OWNER/NAME"
+)
+
+// NewAttachmentCommand creates the attachment command and its sub commands.
+func NewAttachmentCommand() *app.Command {
+	cmd := app.NewCommand("attachment", "Manage the policies attached to the users, the groups and the roles.")
+	cmd.AddCommand(
+		newAttachmentCreateCommand(),
+		newAttachmentListCommand(),
+		newAttachmentDeleteCommand(),
+	)
+	return cmd
+}
+
+func attachmentRows(attachments ...*apiv1.PolicyAttachment) printer.Rows {
+	rows := printer.Rows{{"PRINCIPAL", "POLICY", "ATTACHED"}}
+	for _, a := range attachments {
+		rows = append(rows, []string{a.PrincipalKind + "/" + a.PrincipalName, a.PolicyOwner + "/" + a.PolicyName,
+			formatTime(a.CreatedAt)})
+	}
+	return rows
+}
+
+// splitPrincipal splits the principal in the form of `kind/name`.
+func splitPrincipal(principal string) (kind, name string, err error) {
+	kind, name, ok := strings.Cut(principal, "/")
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("principal %q is not in the form of KIND/NAME", principal)
+	}
+	switch kind {
+	case apiv1.PrincipalUser, apiv1.PrincipalGroup, apiv1.PrincipalRole:
+		return kind, name, nil
+	default:
+		return "", "", fmt.Errorf("principal kind %q is not one of user, group and role", kind)
+	}
+}
+
+// splitPolicy splits the policy in the form of `owner/name`.
+func splitPolicy(policy string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(policy, "/")
+	if !ok || owner == "" || name == "" {
+		return "", "", fmt.Errorf("policy %q is not in the form of OWNER/NAME", policy)
+	}
+	return owner, name, nil
+}
+
+func newAttachmentCreateCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("create PRINCIPAL POLICY", "Attach a policy to a principal, "+principalUsage+" and "+policyUsage+".",
+		o, []string{"PRINCIPAL", "POLICY"}, func(ctx context.Context, args []string) error {
+			kind, principal, err := splitPrincipal(args[0])
+			if err != nil {
+				return err
+			}
+			owner, name, err := splitPolicy(args[1])
+			if err != nil {
+				return err
+			}
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			attachment, err := c.Attachments(kind, principal).Create(ctx, owner, name)
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(attachment, attachmentRows(attachment))
+		})
+}
+
+func newAttachmentListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list PRINCIPAL", "List the policies attached to a principal, "+principalUsage+".",
+		o, []string{"PRINCIPAL"}, func(ctx context.Context, args []string) error {
+			kind, principal, err := splitPrincipal(args[0])
+			if err != nil {
+				return err
+			}
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			list, err := c.Attachments(kind, principal).List(ctx, page.listOptions())
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(list, attachmentRows(list.Items...))
+		})
+}
+
+func newAttachmentDeleteCommand() *app.Command {
+	o := newOptions()
+	return newCommand("delete PRINCIPAL POLICY", "Detach a policy from a principal, "+principalUsage+" and "+policyUsage+".",
+		o, []string{"PRINCIPAL", "POLICY"}, func(ctx context.Context, args []string) error {
+			kind, principal, err := splitPrincipal(args[0])
+			if err != nil {
+				return err
+			}
+			owner, name, err := splitPolicy(args[1])
+			if err != nil {
+				return err
+			}
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			if err := c.Attachments(kind, principal).Delete(ctx, owner, name); err != nil {
+				return err
+			}
+			return printDeleted("attachment", args[1])
+		})
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	clientv1 "github.com/strayca7/siam/staging/src/client/apiserver/v1"
+)
+
+// NewAuditCommand creates the audit command and its sub commands.
+func NewAuditCommand() *app.Command {
+	cmd := app.NewCommand("audit", "Query the audit events, only the admins are allowed.")
+	cmd.AddCommand(newAuditListCommand())
+	return cmd
+}
+
+func auditRows(events ...*apiv1.AuditEvent) printer.Rows {
+	rows := printer.Rows{{"ID", "ACTOR", "ACTION", "RESOURCE", "STATUS", "CHANGED", "TIME"}}
+	for _, e := range events {
+		changed := make([]string, 0, len(e.Diff))
+		for field := range e.Diff {
+			changed = append(changed, field)
+		}
+		slices.Sort(changed)
+		rows = append(rows, []string{
+			strconv.FormatUint(e.ID, 10), e.Actor, e.Action, e.Resource, strconv.Itoa(e.Status),
+			strings.Join(changed, ","), formatTime(e.CreatedAt),
+		})
+	}
+	return rows
+}
+
+func newAuditListCommand() *app.Command {
+	var actor, resource, since, until string
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&actor, "actor", "", "List the events of the actor only.")
+		fs.StringVar(&resource, "resource", "", "List the events of the resource and its sub resources only, like users/alice.")
+		fs.StringVar(&since, "since", "", "List the events since the time, a duration ago like 24h or an RFC 3339 time.")
+		fs.StringVar(&until, "until", "", "List the events before the time, a duration ago like 1h or an RFC 3339 time.")
+		page.addFlags(fs)
+	}, func() []error {
+		errs := page.validate()
+		for name, value := range map[string]string{"since": since, "until": until} {
+			if _, err := timeFlag(value); err != nil {
+				errs = append(errs, errors.New(name+": "+err.Error()))
+			}
+		}
+		return errs
+	}))
+
+	return newCommand("list", "List the audit events, the latest events first.", o, nil,
+		func(ctx context.Context, _ []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			filter := &clientv1.AuditFilter{Actor: actor, Resource: resource}
+			filter.Since, _ = timeFlag(since)
+			filter.Until, _ = timeFlag(until)
+
+			list, err := c.Audit().List(ctx, filter, page.listOptions())
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(list, auditRows(list.Items...))
+		})
+}
+
+// timeFlag parses a time flag, which is either a duration ago or an RFC 3339 time. The empty value is the zero time.
+func timeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// NewGroupCommand creates the group command and its sub commands.
+func NewGroupCommand() *app.Command {
+	cmd := app.NewCommand("group", "Manage the groups and their members.")
+	cmd.AddCommand(
+		newGroupCreateCommand(),
+		newGroupGetCommand(),
+		newGroupListCommand(),
+		newGroupUpdateCommand(),
+		newGroupDeleteCommand(),
+		newGroupAddMemberCommand(),
+		newGroupMembersCommand(),
+		newGroupRemoveMemberCommand(),
+	)
+	return cmd
+}
+
+func groupRows(groups ...*apiv1.Group) printer.Rows {
+	rows := printer.Rows{{"NAME", "DESCRIPTION", "CREATED"}}
+	for _, g := range groups {
+		rows = append(rows, []string{g.Name, g.Description, formatTime(g.CreatedAt)})
+	}
+	return rows
+}
+
+func memberRows(members ...*apiv1.GroupMember) printer.Rows {
+	rows := printer.Rows{{"GROUP", "USER", "ADDED"}}
+	for _, m := range members {
+		rows = append(rows, []string{m.Group, m.Username, formatTime(m.CreatedAt)})
+	}
+	return rows
+}
+
+func newGroupCreateCommand() *app.Command {
+	var description string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the group.")
+	}, nil))
+
+	return newCommand("create NAME", "Create a group.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		group, err := c.Groups().Create(ctx, &apiv1.Group{Name: args[0], Description: description})
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(group, groupRows(group))
+	})
+}
+
+func newGroupGetCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("get NAME", "Show a group.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		group, err := c.Groups().Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(group, groupRows(group))
+	})
+}
+
+func newGroupListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the groups.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Groups().List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, groupRows(list.Items...))
+	})
+}
+
+func newGroupUpdateCommand() *app.Command {
+	var description string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the group.")
+	}, nil))
+
+	return newCommand("update NAME", "Update a group, only the set flags are updated.", o, []string{"NAME"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			group, err := c.Groups().Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("description") {
+				group.Description = description
+			}
+
+			if group, err = c.Groups().Update(ctx, group); err != nil {
+				return err
+			}
+			return o.printer.Print(group, groupRows(group))
+		})
+}
+
+func newGroupDeleteCommand() *app.Command {
+	o := newOptions()
+	return newCommand("delete NAME", "Delete a group.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		if err := c.Groups().Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		return printDeleted("group", args[0])
+	})
+}
+
+func newGroupAddMemberCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("add-member GROUP USER", "Add a user to a group.", o, []string{"GROUP", "USER"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			member, err := c.Groups().AddMember(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(member, memberRows(member))
+		})
+}
+
+func newGroupMembersCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("members GROUP", "List the members of a group.", o, []string{"GROUP"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			list, err := c.Groups().ListMembers(ctx, args[0], page.listOptions())
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(list, memberRows(list.Items...))
+		})
+}
+
+func newGroupRemoveMemberCommand() *app.Command {
+	o := newOptions()
+	return newCommand("remove-member GROUP USER", "Remove a user from a group.", o, []string{"GROUP", "USER"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			if err := c.Groups().RemoveMember(ctx, args[0], args[1]); err != nil {
+				return err
+			}
+			return printDeleted("member", args[1])
+		})
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/client"
+	"github.com/strayca7/siam/pkg/app"
+	clientv1 "github.com/strayca7/siam/staging/src/client/apiserver/v1"
+)
+
+// NewLoginCommand creates the login command which caches the token under $HOME/.siam.
+// The users of the other tenants than the default one login with --tenant, which is cached too.
+func NewLoginCommand() *app.Command {
+	var username, password string
+	o := newOptions(withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVarP(&username, "username", "u", "", "Name of the user to login.")
+		fs.StringVarP(&password, "password", "p", "", "Password of the user, it is read from stdin if not set.")
+	}, func() []error {
+		if username == "" {
+			return []error{errors.New("username must not be empty")}
+		}
+		return nil
+	}))
+
+	return newCommand("login", "Login to siam-apiserver and cache the token under $HOME/.siam.", o, nil,
+		func(ctx context.Context, _ []string) error {
+			if o.client.Server == "" {
+				creds, err := client.LoadCredentials()
+				if err != nil {
+					return err
+				}
+				o.client.Server = creds.Server
+			}
+			if o.client.Server == "" {
+				return errors.New("server is not set, please use --server")
+			}
+			if password == "" {
+				p, err := readPassword()
+				if err != nil {
+					return err
+				}
+				password = p
+			}
+
+			c, err := clientv1.New(clientv1.Config{Server: o.client.Server, Tenant: o.client.Tenant})
+			if err != nil {
+				return err
+			}
+			token, err := c.Login(ctx, username, password)
+			if err != nil {
+				return err
+			}
+
+			creds := &client.Credentials{
+				Server:    o.client.Server,
+				Tenant:    o.client.Tenant,
+				Username:  username,
+				Token:     token.Token,
+				ExpiresAt: token.ExpiresAt,
+			}
+			if err := creds.Save(); err != nil {
+				return fmt.Errorf("cache the token: %w", err)
+			}
+			fmt.Printf("logged in as %q, the token expires at %s\n", username, formatTime(token.ExpiresAt))
+			return nil
+		})
+}
+
+// readPassword reads the password from the first line of stdin.
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
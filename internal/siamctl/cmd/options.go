@@ -0,0 +1,134 @@
+// Package cmd implements the sub commands of siamctl.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/client"
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	clientv1 "github.com/strayca7/siam/staging/src/client/apiserver/v1"
+	cliflag "github.com/strayca7/siam/staging/src/component-base/cli/flag"
+)
+
+// requestTimeout bounds every request sent by a command.
+const requestTimeout = 30 * time.Second
+
+// options are the options of a single command: the command specific flags,
+// the client flags and, for the commands printing objects, the output flags.
+type options struct {
+	client  *client.Options
+	printer *printer.Options
+	// user is the owner of the secrets and policies, it defaults to the user of the last login.
+	user string
+	// withUser adds the --user flag.
+	withUser bool
+	// addFlags adds the command specific flags.
+	addFlags func(fs *pflag.FlagSet)
+	// validate checks the command specific flags.
+	validate func() []error
+	// fs holds the command specific flags, it is used to find out which flags are set.
+	fs *pflag.FlagSet
+}
+
+// option configures the options of a command.
+type option func(*options)
+
+// withPrinter adds the output flags.
+func withPrinter() option {
+	return func(o *options) {
+		o.printer = printer.NewOptions()
+	}
+}
+
+// withUser adds the --user flag.
+func withUser() option {
+	return func(o *options) {
+		o.withUser = true
+	}
+}
+
+// withFlags adds the command specific flags and their validation, validate may be nil.
+func withFlags(addFlags func(fs *pflag.FlagSet), validate func() []error) option {
+	return func(o *options) {
+		o.addFlags = addFlags
+		o.validate = validate
+	}
+}
+
+func newOptions(opts ...option) *options {
+	o := &options{client: client.NewOptions()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Flags returns flags for the command grouped by section name.
+func (o *options) Flags() (fss cliflag.NamedFlagSets) {
+	o.fs = fss.FlagSet("command")
+	if o.withUser {
+		o.fs.StringVarP(&o.user, "user", "u", o.user, "Owner of the objects. Defaults to the user of the last login.")
+	}
+	if o.addFlags != nil {
+		o.addFlags(o.fs)
+	}
+	o.client.Flags(fss.FlagSet("client"))
+	if o.printer != nil {
+		o.printer.Flags(fss.FlagSet("output"))
+	}
+	return fss
+}
+
+// Validate checks all of the options and returns the found errors.
+func (o *options) Validate() []error {
+	var errs []error
+	errs = append(errs, o.client.Validate()...)
+	if o.printer != nil {
+		errs = append(errs, o.printer.Validate()...)
+	}
+	if o.validate != nil {
+		errs = append(errs, o.validate()...)
+	}
+	return errs
+}
+
+// changed reports whether the command specific flag is set on the command line.
+func (o *options) changed(name string) bool {
+	return o.fs != nil && o.fs.Changed(name)
+}
+
+// newClient creates the client and resolves the owner of the objects.
+func (o *options) newClient() (*clientv1.Client, error) {
+	c, username, err := o.client.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	if o.withUser && o.user == "" {
+		if username == "" {
+			return nil, fmt.Errorf("user is not set, please run login or use --user")
+		}
+		o.user = username
+	}
+	return c, nil
+}
+
+// newCommand creates a command whose run function receives the positional arguments,
+// the number of arguments is checked against the argument names.
+func newCommand(usage, desc string, o *options, argNames []string, run func(ctx context.Context, args []string) error) *app.Command {
+	return app.NewCommand(usage, desc,
+		app.WithCommandOptions(o),
+		app.WithCommandRunFunc(func(args []string) error {
+			if len(args) != len(argNames) {
+				return fmt.Errorf("expected arguments %v, got %q", argNames, args)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			defer cancel()
+			return run(ctx, args)
+		}),
+	)
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	"github.com/strayca7/siam/pkg/policy"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// NewPolicyCommand creates the policy command and its sub commands.
+func NewPolicyCommand() *app.Command {
+	cmd := app.NewCommand("policy", "Manage the policies of a user.")
+	cmd.AddCommand(
+		newPolicyCreateCommand(),
+		newPolicyGetCommand(),
+		newPolicyListCommand(),
+		newPolicyUpdateCommand(),
+		newPolicyDeleteCommand(),
+	)
+	return cmd
+}
+
+func policyRows(policies ...*apiv1.Policy) printer.Rows {
+	rows := printer.Rows{{"NAME", "USER", "DESCRIPTION", "STATEMENTS", "UPDATED"}}
+	for _, p := range policies {
+		rows = append(rows, []string{p.Name, p.Username, p.Description, strconv.Itoa(len(p.Document.Statements)),
+			formatTime(p.UpdatedAt)})
+	}
+	return rows
+}
+
+// readDocument reads the JSON policy document from the file, `-` reads from stdin.
+func readDocument(file string) (*policy.Document, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &policy.Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("parse policy document %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+func addDocumentFlag(fs *pflag.FlagSet, file *string) {
+	fs.StringVarP(file, "file", "f", "", "JSON file of the policy document, - reads from stdin.")
+}
+
+func newPolicyCreateCommand() *app.Command {
+	var description, file string
+	var labels, annotations map[string]string
+	o := newOptions(withUser(), withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the policy.")
+		addDocumentFlag(fs, &file)
+		addMetaFlags(fs, &labels, &annotations)
+	}, func() []error {
+		if file == "" {
+			return []error{errors.New("file of the policy document must not be empty")}
+		}
+		return nil
+	}))
+
+	return newCommand("create NAME", "Create a policy.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		doc, err := readDocument(file)
+		if err != nil {
+			return err
+		}
+		p := &apiv1.Policy{ObjectMeta: metav1.ObjectMeta{Name: args[0]}, Description: description, Document: *doc}
+		setMeta(o, &p.ObjectMeta, labels, annotations)
+
+		if p, err = c.Policies(o.user).Create(ctx, p); err != nil {
+			return err
+		}
+		return o.printer.Print(p, policyRows(p))
+	})
+}
+
+func newPolicyGetCommand() *app.Command {
+	o := newOptions(withUser(), withPrinter())
+	return newCommand("get NAME", "Show a policy.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		p, err := c.Policies(o.user).Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(p, policyRows(p))
+	})
+}
+
+func newPolicyListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withUser(), withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the policies of a user.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Policies(o.user).List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, policyRows(list.Items...))
+	})
+}
+
+func newPolicyUpdateCommand() *app.Command {
+	var description, file string
+	var labels, annotations map[string]string
+	o := newOptions(withUser(), withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the policy.")
+		addDocumentFlag(fs, &file)
+		addMetaFlags(fs, &labels, &annotations)
+	}, nil))
+
+	return newCommand("update NAME", "Update a policy, only the set flags are updated.", o, []string{"NAME"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			// the update fails with a conflict instead of overwriting a change made since the policy was read
+			p, err := c.Policies(o.user).Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("description") {
+				p.Description = description
+			}
+			if file != "" {
+				doc, err := readDocument(file)
+				if err != nil {
+					return err
+				}
+				p.Document = *doc
+			}
+			setMeta(o, &p.ObjectMeta, labels, annotations)
+
+			if p, err = c.Policies(o.user).Update(ctx, p); err != nil {
+				return err
+			}
+			return o.printer.Print(p, policyRows(p))
+		})
+}
+
+func newPolicyDeleteCommand() *app.Command {
+	o := newOptions(withUser())
+	return newCommand("delete NAME", "Delete a policy.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		if err := c.Policies(o.user).Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		return printDeleted("policy", args[0])
+	})
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// NewRoleCommand creates the role command and its sub commands.
+func NewRoleCommand() *app.Command {
+	cmd := app.NewCommand("role", "Manage and assume the roles.")
+	cmd.AddCommand(
+		newRoleCreateCommand(),
+		newRoleGetCommand(),
+		newRoleListCommand(),
+		newRoleUpdateCommand(),
+		newRoleDeleteCommand(),
+		newRoleAssumeCommand(),
+	)
+	return cmd
+}
+
+func roleRows(roles ...*apiv1.Role) printer.Rows {
+	rows := printer.Rows{{"NAME", "DESCRIPTION", "TRUSTED USERS", "TRUSTED GROUPS", "UPDATED"}}
+	for _, r := range roles {
+		rows = append(rows, []string{r.Name, r.Description, strings.Join(r.TrustedUsers, ","),
+			strings.Join(r.TrustedGroups, ","), formatTime(r.UpdatedAt)})
+	}
+	return rows
+}
+
+// addTrustFlags adds the flags of the trusted principals of a role.
+func addTrustFlags(fs *pflag.FlagSet, users, groups *[]string) {
+	fs.StringSliceVar(users, "trusted-users", nil, "Users allowed to assume the role.")
+	fs.StringSliceVar(groups, "trusted-groups", nil, "Groups whose members are allowed to assume the role.")
+}
+
+func newRoleCreateCommand() *app.Command {
+	var description string
+	var users, groups []string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the role.")
+		addTrustFlags(fs, &users, &groups)
+	}, nil))
+
+	return newCommand("create NAME", "Create a role.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		role := &apiv1.Role{Name: args[0], Description: description, TrustedUsers: users, TrustedGroups: groups}
+		if role, err = c.Roles().Create(ctx, role); err != nil {
+			return err
+		}
+		return o.printer.Print(role, roleRows(role))
+	})
+}
+
+func newRoleGetCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("get NAME", "Show a role.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		role, err := c.Roles().Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(role, roleRows(role))
+	})
+}
+
+func newRoleListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the roles.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Roles().List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, roleRows(list.Items...))
+	})
+}
+
+func newRoleUpdateCommand() *app.Command {
+	var description string
+	var users, groups []string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the role.")
+		addTrustFlags(fs, &users, &groups)
+	}, nil))
+
+	return newCommand("update NAME", "Update a role, only the set flags are updated and the trusted principals are replaced.",
+		o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			role, err := c.Roles().Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("description") {
+				role.Description = description
+			}
+			if o.changed("trusted-users") {
+				role.TrustedUsers = users
+			}
+			if o.changed("trusted-groups") {
+				role.TrustedGroups = groups
+			}
+
+			if role, err = c.Roles().Update(ctx, role); err != nil {
+				return err
+			}
+			return o.printer.Print(role, roleRows(role))
+		})
+}
+
+func newRoleDeleteCommand() *app.Command {
+	o := newOptions()
+	return newCommand("delete NAME", "Delete a role.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		if err := c.Roles().Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		return printDeleted("role", args[0])
+	})
+}
+
+func newRoleAssumeCommand() *app.Command {
+	var duration time.Duration
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.DurationVar(&duration, "duration", 0, "Duration of the session. Defaults to the server side duration.")
+	}, func() []error {
+		if duration < 0 || duration%time.Second != 0 {
+			return []error{errors.New("duration must be a non-negative number of seconds")}
+		}
+		return nil
+	}))
+
+	return newCommand("assume NAME", "Assume a role and print the token of the session.", o, []string{"NAME"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			session, err := c.Roles().Assume(ctx, args[0], duration)
+			if err != nil {
+				return err
+			}
+			rows := printer.Rows{{"SUBJECT", "EXPIRES", "TOKEN"}, {session.Subject, formatTime(session.ExpiresAt), session.Token}}
+			return o.printer.Print(session, rows)
+		})
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// NewSecretCommand creates the secret command and its sub commands.
+func NewSecretCommand() *app.Command {
+	cmd := app.NewCommand("secret", "Manage the AccessKey/SecretKey pairs of a user.")
+	cmd.AddCommand(
+		newSecretCreateCommand(),
+		newSecretGetCommand(),
+		newSecretListCommand(),
+		newSecretUpdateCommand(),
+		newSecretDeleteCommand(),
+	)
+	return cmd
+}
+
+func secretRows(secrets ...*apiv1.Secret) printer.Rows {
+	rows := printer.Rows{{"ACCESS KEY", "NAME", "USER", "DESCRIPTION", "EXPIRES", "CREATED"}}
+	for _, s := range secrets {
+		expires := "never"
+		if s.ExpiresAt != nil {
+			expires = formatTime(*s.ExpiresAt)
+		}
+		rows = append(rows, []string{s.AccessKey, s.Name, s.Username, s.Description, expires, formatTime(s.CreatedAt)})
+	}
+	return rows
+}
+
+// expiresFlag parses the --expires flag, which is either a duration from now or an RFC 3339 time.
+func expiresFlag(value string) (*time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		t := time.Now().Add(d)
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func addExpiresFlag(fs *pflag.FlagSet, expires *string) {
+	fs.StringVar(expires, "expires", "",
+		"Expiration of the secret, a duration from now like 720h or an RFC 3339 time.")
+}
+
+func validateExpiresFlag(expires string) []error {
+	if expires == "" {
+		return nil
+	}
+	if _, err := expiresFlag(expires); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func newSecretCreateCommand() *app.Command {
+	var name, description, expires string
+	var labels, annotations map[string]string
+	o := newOptions(withUser(), withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&name, "name", "", "Optional name of the secret.")
+		fs.StringVar(&description, "description", "", "Description of the secret.")
+		addExpiresFlag(fs, &expires)
+		addMetaFlags(fs, &labels, &annotations)
+	}, func() []error {
+		return validateExpiresFlag(expires)
+	}))
+
+	return newCommand("create", "Create a secret, the secret key is only shown once.", o, nil,
+		func(ctx context.Context, _ []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			s := &apiv1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}, Description: description}
+			if expires != "" {
+				s.ExpiresAt, _ = expiresFlag(expires)
+			}
+			setMeta(o, &s.ObjectMeta, labels, annotations)
+
+			secret, err := c.Secrets(o.user).Create(ctx, s)
+			if err != nil {
+				return err
+			}
+			rows := secretRows(secret.Secret)
+			rows[0] = append(rows[0], "SECRET KEY")
+			rows[1] = append(rows[1], secret.SecretKey)
+			return o.printer.Print(secret, rows)
+		})
+}
+
+func newSecretGetCommand() *app.Command {
+	o := newOptions(withUser(), withPrinter())
+	return newCommand("get ACCESS_KEY", "Show a secret.", o, []string{"ACCESS_KEY"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			secret, err := c.Secrets(o.user).Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return o.printer.Print(secret, secretRows(secret))
+		})
+}
+
+func newSecretListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withUser(), withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the secrets of a user.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Secrets(o.user).List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, secretRows(list.Items...))
+	})
+}
+
+func newSecretUpdateCommand() *app.Command {
+	var description, expires string
+	var labels, annotations map[string]string
+	o := newOptions(withUser(), withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the secret.")
+		addExpiresFlag(fs, &expires)
+		addMetaFlags(fs, &labels, &annotations)
+	}, func() []error {
+		return validateExpiresFlag(expires)
+	}))
+
+	return newCommand("update ACCESS_KEY", "Update a secret, only the set flags are updated.", o, []string{"ACCESS_KEY"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			// the update fails with a conflict instead of overwriting a change made since the secret was read
+			secret, err := c.Secrets(o.user).Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("description") {
+				secret.Description = description
+			}
+			if o.changed("expires") && expires != "" {
+				secret.ExpiresAt, _ = expiresFlag(expires)
+			}
+			setMeta(o, &secret.ObjectMeta, labels, annotations)
+
+			if secret, err = c.Secrets(o.user).Update(ctx, secret); err != nil {
+				return err
+			}
+			return o.printer.Print(secret, secretRows(secret))
+		})
+}
+
+func newSecretDeleteCommand() *app.Command {
+	o := newOptions(withUser())
+	return newCommand("delete ACCESS_KEY", "Delete a secret.", o, []string{"ACCESS_KEY"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			if err := c.Secrets(o.user).Delete(ctx, args[0]); err != nil {
+				return err
+			}
+			return printDeleted("secret", args[0])
+		})
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// NewTenantCommand creates the tenant command and its sub commands, they are allowed to the system admins only.
+func NewTenantCommand() *app.Command {
+	cmd := app.NewCommand("tenant", "Manage the tenants.")
+	cmd.AddCommand(
+		newTenantCreateCommand(),
+		newTenantGetCommand(),
+		newTenantListCommand(),
+		newTenantUpdateCommand(),
+		newTenantDeleteCommand(),
+	)
+	return cmd
+}
+
+func tenantRows(tenants ...*apiv1.Tenant) printer.Rows {
+	rows := printer.Rows{{"NAME", "DESCRIPTION", "CREATED"}}
+	for _, t := range tenants {
+		rows = append(rows, []string{t.Name, t.Description, formatTime(t.CreatedAt)})
+	}
+	return rows
+}
+
+func newTenantCreateCommand() *app.Command {
+	var description string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the tenant.")
+	}, nil))
+
+	return newCommand("create NAME", "Create a tenant.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		tenant, err := c.Tenants().Create(ctx, &apiv1.Tenant{Name: args[0], Description: description})
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(tenant, tenantRows(tenant))
+	})
+}
+
+func newTenantGetCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("get NAME", "Show a tenant.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		tenant, err := c.Tenants().Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(tenant, tenantRows(tenant))
+	})
+}
+
+func newTenantListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the tenants.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Tenants().List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, tenantRows(list.Items...))
+	})
+}
+
+func newTenantUpdateCommand() *app.Command {
+	var description string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&description, "description", "", "Description of the tenant.")
+	}, nil))
+
+	return newCommand("update NAME", "Update a tenant, only the set flags are updated.", o, []string{"NAME"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			tenant, err := c.Tenants().Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("description") {
+				tenant.Description = description
+			}
+
+			if tenant, err = c.Tenants().Update(ctx, tenant); err != nil {
+				return err
+			}
+			return o.printer.Print(tenant, tenantRows(tenant))
+		})
+}
+
+func newTenantDeleteCommand() *app.Command {
+	o := newOptions()
+	return newCommand("delete NAME", "Delete a tenant.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		if err := c.Tenants().Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		return printDeleted("tenant", args[0])
+	})
+}
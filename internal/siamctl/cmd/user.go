@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/spf13/pflag"
+
+	"github.com/strayca7/siam/internal/siamctl/printer"
+	"github.com/strayca7/siam/pkg/app"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// NewUserCommand creates the user command and its sub commands.
+func NewUserCommand() *app.Command {
+	cmd := app.NewCommand("user", "Manage the users.")
+	cmd.AddCommand(
+		newUserCreateCommand(),
+		newUserGetCommand(),
+		newUserListCommand(),
+		newUserUpdateCommand(),
+		newUserDeleteCommand(),
+	)
+	return cmd
+}
+
+func userRows(users ...*apiv1.User) printer.Rows {
+	rows := printer.Rows{{"NAME", "NICKNAME", "EMAIL", "PHONE", "ADMIN", "CREATED"}}
+	for _, u := range users {
+		rows = append(rows, []string{u.Name, u.Nickname, u.Email, u.Phone, strconv.FormatBool(u.IsAdmin),
+			formatTime(u.CreatedAt)})
+	}
+	return rows
+}
+
+func newUserCreateCommand() *app.Command {
+	var password string
+	user := &apiv1.User{}
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&user.Nickname, "nickname", "", "Nickname of the user.")
+		fs.StringVar(&password, "password", "", "Password of the user, at least 8 characters.")
+		fs.StringVar(&user.Email, "email", "", "Email of the user.")
+		fs.StringVar(&user.Phone, "phone", "", "Phone number of the user in E.164 format.")
+		fs.BoolVar(&user.IsAdmin, "admin", false, "Whether the user is an administrator, only the admins create admins.")
+		addMetaFlags(fs, &user.Labels, &user.Annotations)
+	}, func() []error {
+		if password == "" {
+			return []error{errors.New("password must not be empty")}
+		}
+		return nil
+	}))
+
+	return newCommand("create NAME", "Create a user.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		user.Name = args[0]
+		created, err := c.Users().Create(ctx, user, password)
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(created, userRows(created))
+	})
+}
+
+func newUserGetCommand() *app.Command {
+	o := newOptions(withPrinter())
+	return newCommand("get NAME", "Show a user.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		user, err := c.Users().Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(user, userRows(user))
+	})
+}
+
+func newUserListCommand() *app.Command {
+	page := &pageOptions{}
+	o := newOptions(withPrinter(), withFlags(page.addFlags, page.validate))
+	return newCommand("list", "List the users.", o, nil, func(ctx context.Context, _ []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		list, err := c.Users().List(ctx, page.listOptions())
+		if err != nil {
+			return err
+		}
+		return o.printer.Print(list, userRows(list.Items...))
+	})
+}
+
+func newUserUpdateCommand() *app.Command {
+	var nickname, email, phone string
+	var isAdmin bool
+	var labels, annotations map[string]string
+	o := newOptions(withPrinter(), withFlags(func(fs *pflag.FlagSet) {
+		fs.StringVar(&nickname, "nickname", "", "Nickname of the user.")
+		fs.StringVar(&email, "email", "", "Email of the user.")
+		fs.StringVar(&phone, "phone", "", "Phone number of the user in E.164 format.")
+		fs.BoolVar(&isAdmin, "admin", false, "Whether the user is an administrator.")
+		addMetaFlags(fs, &labels, &annotations)
+	}, nil))
+
+	return newCommand("update NAME", "Update a user, only the set flags are updated.", o, []string{"NAME"},
+		func(ctx context.Context, args []string) error {
+			c, err := o.newClient()
+			if err != nil {
+				return err
+			}
+			// the update fails with a conflict instead of overwriting a change made since the user was read
+			user, err := c.Users().Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.changed("nickname") {
+				user.Nickname = nickname
+			}
+			if o.changed("email") {
+				user.Email = email
+			}
+			if o.changed("phone") {
+				user.Phone = phone
+			}
+			if o.changed("admin") {
+				user.IsAdmin = isAdmin
+			}
+			setMeta(o, &user.ObjectMeta, labels, annotations)
+
+			if user, err = c.Users().Update(ctx, user); err != nil {
+				return err
+			}
+			return o.printer.Print(user, userRows(user))
+		})
+}
+
+func newUserDeleteCommand() *app.Command {
+	o := newOptions()
+	return newCommand("delete NAME", "Delete a user.", o, []string{"NAME"}, func(ctx context.Context, args []string) error {
+		c, err := o.newClient()
+		if err != nil {
+			return err
+		}
+		if err := c.Users().Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		return printDeleted("user", args[0])
+	})
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// pageOptions defines the pagination and the selector flags of the list commands.
+type pageOptions struct {
+	offset        int
+	limit         int
+	token         string
+	labelSelector string
+	fieldSelector string
+}
+
+func (p *pageOptions) addFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&p.offset, "offset", 0, "Number of objects to skip.")
+	fs.IntVar(&p.limit, "limit", 0, "Maximum number of objects to list. Defaults to the server side limit.")
+	fs.StringVar(&p.token, "continue", "", "Continue token of the previous page, it takes precedence over --offset.")
+	fs.StringVarP(&p.labelSelector, "selector", "l", "", "Label selector to filter on, like env=prod,tier in (web,api).")
+	fs.StringVar(&p.fieldSelector, "field-selector", "", "Field selector to filter on, like name!=alice.")
+}
+
+func (p *pageOptions) validate() []error {
+	var errs []error
+	if p.offset < 0 {
+		errs = append(errs, errors.New("offset must not be negative"))
+	}
+	if p.limit < 0 {
+		errs = append(errs, errors.New("limit must not be negative"))
+	}
+	return errs
+}
+
+func (p *pageOptions) listOptions() *metav1.ListOptions {
+	return &metav1.ListOptions{
+		LabelSelector: p.labelSelector,
+		FieldSelector: p.fieldSelector,
+		Limit:         p.limit,
+		Offset:        p.offset,
+		Continue:      p.token,
+	}
+}
+
+// addMetaFlags adds the flags of the labels and the annotations of the objects.
+func addMetaFlags(fs *pflag.FlagSet, labels, annotations *map[string]string) {
+	fs.StringToStringVar(labels, "labels", nil, "Labels of the object, like env=prod,tier=web.")
+	fs.StringToStringVar(annotations, "annotations", nil, "Annotations of the object, like owner=alice.")
+}
+
+// setMeta sets the labels and the annotations of the object if their flags are set,
+// they replace the whole labels and annotations of the object.
+func setMeta(o *options, meta *metav1.ObjectMeta, labels, annotations map[string]string) {
+	if o.changed("labels") {
+		meta.Labels = labels
+	}
+	if o.changed("annotations") {
+		meta.Annotations = annotations
+	}
+}
+
+// formatTime formats the time in the table output, the zero time is printed as `-`.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+func printDeleted(kind, name string) error {
+	_, err := fmt.Printf("%s %q deleted\n", kind, name)
+	return err
+}
@@ -0,0 +1,84 @@
+// Package printer prints the api objects of siamctl as a table, JSON or YAML.
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"go.yaml.in/yaml/v3"
+)
+
+// Supported output formats.
+const (
+	Table = "table"
+	JSON  = "json"
+	YAML  = "yaml"
+)
+
+// Options defines the output format flag of a command.
+type Options struct {
+	Output string
+}
+
+// NewOptions creates an Options instance with default values.
+func NewOptions() *Options {
+	return &Options{Output: Table}
+}
+
+// Flags adds flags for the output options to the specified FlagSet.
+func (o *Options) Flags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.Output, "output", "o", o.Output, "Output format, one of table, json and yaml.")
+}
+
+// Validate checks the output options and returns all of the found errors.
+func (o *Options) Validate() []error {
+	switch o.Output {
+	case Table, JSON, YAML:
+		return nil
+	default:
+		return []error{fmt.Errorf("output %q is not supported", o.Output)}
+	}
+}
+
+// Rows is the table view of an object, the first row is the header.
+type Rows [][]string
+
+// Print prints the object to stdout in the selected format, rows is the table view of the object.
+func (o *Options) Print(obj any, rows Rows) error {
+	return o.Fprint(os.Stdout, obj, rows)
+}
+
+// Fprint prints the object to w in the selected format, rows is the table view of the object.
+func (o *Options) Fprint(w io.Writer, obj any, rows Rows) error {
+	switch o.Output {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(obj)
+	case YAML:
+		// go through JSON to keep the field names of the json tags
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(v)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
@@ -13,6 +14,8 @@ import (
 	"k8s.io/component-base/term"
 
 	"github.com/strayca7/siam/internal/pkg/config"
+	"github.com/strayca7/siam/internal/pkg/options"
+	"github.com/strayca7/siam/internal/pkg/util"
 	"github.com/strayca7/siam/pkg/logger"
 	"github.com/strayca7/siam/pkg/serrors"
 	cliflag "github.com/strayca7/siam/staging/src/component-base/cli/flag"
@@ -95,6 +98,13 @@ func WithNoConfig() Option {
 	}
 }
 
+// WithCommands adds the sub commands to the application.
+func WithCommands(cmds ...*Command) Option {
+	return func(a *App) {
+		a.commands = append(a.commands, cmds...)
+	}
+}
+
 // WithDefaultValidArgs set default validation function to valid non-flag arguments.
 func WithDefaultValidArgs() Option {
 	return func(a *App) {
@@ -242,8 +252,13 @@ func (a *App) Command() *cobra.Command {
 
 // Run launches the application and returns an exit code.
 func Run(a *App) int {
+	globalOpts, err := loadGlobal()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
 	// TODO: logger should be initialized after parsing command line args successfully and before main logic execution
-	logger.Init(context.Background(), config.GlobalOptions.Log, logger.WithName(a.basename))
+	logger.Init(context.Background(), globalOpts.Log, logger.WithName(a.basename))
 	defer logger.L().Sync()
 	if err := a.cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -252,6 +267,22 @@ func Run(a *App) int {
 	return 0
 }
 
+// loadGlobal loads the global configuration file before the logger is initialized. The default options are used
+// with a warning on stderr if the file does not exist, and a file which can not be read or parsed is an error.
+func loadGlobal() (*options.Global, error) {
+	opts, err := config.LoadGlobal()
+	if err == nil {
+		return opts, nil
+	}
+	var notFound viper.ConfigFileNotFoundError
+	if !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("load global configuration: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: global configuration file is not found in %s, the default options are used\n",
+		util.BaseConfigPath)
+	return options.NewGlobal(), nil
+}
+
 // FormatBasename formats the binary name of the application according to the operating system.
 // It will lowercase the name. If the OS is Windows, it will also remove the ".exe" suffix.
 func FormatBasename(basename string) string {
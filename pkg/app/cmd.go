@@ -6,6 +6,12 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"k8s.io/component-base/term"
+
+	"github.com/strayca7/siam/pkg/serrors"
+	cliflag "github.com/strayca7/siam/staging/src/component-base/cli/flag"
 )
 
 // Command is a sub command structure of a cli application.
@@ -18,6 +24,8 @@ type Command struct {
 	// runFunc is the command's startup callback function.
 	// If runFunc is not nil, it will be called in Run of cobra.Command.
 	runFunc RunCommandFunc
+	// config indicates the options are also read from the configuration file of the application.
+	config bool
 }
 
 // CommandOption defines optional parameters for initializing the command structure.
@@ -38,6 +46,14 @@ func WithCommandRunFunc(runFunc RunCommandFunc) CommandOption {
 	}
 }
 
+// WithCommandConfig reads the options of the command from the configuration file of the application,
+// the flags set in the command line take precedence. The application must provide the config flag.
+func WithCommandConfig() CommandOption {
+	return func(c *Command) {
+		c.config = true
+	}
+}
+
 // NewCommand creates a new sub command instance based on the given command name and other options.
 func NewCommand(usage, desc string, opts ...CommandOption) *Command {
 	c := &Command{
@@ -72,18 +88,45 @@ func (c *Command) cobraCommand() *cobra.Command {
 	if c.runFunc != nil {
 		cc.Run = c.runCommand
 	}
+	var namedFlagSets cliflag.NamedFlagSets
 	if c.options != nil {
-		for _, f := range c.options.Flags().FlagSets {
+		namedFlagSets = c.options.Flags()
+		for _, f := range namedFlagSets.FlagSets {
 			cc.Flags().AddFlagSet(f)
 		}
 	}
-	addHelpCommandFlag(c.usage, cc.Flags())
+	if c.config {
+		// the config flag is registered by the application
+		namedFlagSets.FlagSet("global").AddFlag(pflag.Lookup(cliflag.ConfigFlagName))
+	}
+	addHelpCommandFlag(c.usage, namedFlagSets.FlagSet("global"))
+	cc.Flags().AddFlagSet(namedFlagSets.FlagSet("global"))
+
+	col, _, _ := term.TerminalSize(cc.OutOrStdout())
+	// the usage and help function of the parent only prints the flags of the parent
+	cliflag.SetUsageAndHelpFunc(cc, namedFlagSets, col)
 
 	return cc
 }
 
 // runCommand is the callback function for executing the command.
 func (c *Command) runCommand(cmd *cobra.Command, args []string) {
+	if c.options != nil && c.config {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
+			os.Exit(1)
+		}
+		if err := viper.Unmarshal(c.options); err != nil {
+			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
+			os.Exit(1)
+		}
+	}
+	if c.options != nil {
+		if errs := c.options.Validate(); len(errs) > 0 {
+			fmt.Printf("%v %v\n", color.RedString("Error:"), serrors.NewAggregate(errs))
+			os.Exit(1)
+		}
+	}
 	if c.runFunc != nil {
 		if err := c.runFunc(args); err != nil {
 			fmt.Printf("%v %v\n", color.RedString("Error:"), err)
@@ -0,0 +1,77 @@
+// Package auth provides the password hashing and token primitives used to authenticate users.
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// ErrMismatchedPassword is returned by Compare when the password does not match the hash.
+var ErrMismatchedPassword = errors.New("auth: password does not match the hash")
+
+// Encrypt encrypts the plain text with bcrypt.
+func Encrypt(source string) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(source), bcrypt.DefaultCost)
+	return string(hashedBytes), err
+}
+
+// Compare compares the encrypted text with the plain text if it's the same.
+// The encrypted text can be a bcrypt hash or an argon2id hash in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>.
+func Compare(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return compareArgon2id(hashedPassword, password)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedPassword
+		}
+		return err
+	}
+	return nil
+}
+
+func compareArgon2id(hashedPassword, password string) error {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=4", salt, hash
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return fmt.Errorf("auth: malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return fmt.Errorf("auth: malformed argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return fmt.Errorf("auth: unsupported argon2id version %d", version)
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return fmt.Errorf("auth: malformed argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return fmt.Errorf("auth: malformed argon2id salt: %w", err)
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return fmt.Errorf("auth: malformed argon2id hash: %w", err)
+	}
+
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(hash)))
+	if subtle.ConstantTimeCompare(hash, other) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signing algorithms supported by JWT.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var (
+	// ErrTokenInvalid is returned by JWT.Parse when the token is malformed or its signature is invalid.
+	ErrTokenInvalid = errors.New("auth: token is invalid")
+	// ErrTokenExpired is returned by JWT.Parse when the token is expired.
+	ErrTokenExpired = errors.New("auth: token is expired")
+)
+
+// Claims are the claims of the tokens issued by siam, the subject is the username,
+// or the role of the session for the tokens issued by SignSession.
+type Claims struct {
+	// Tenant is the tenant of the subject, it is empty in the tokens issued before the tenants.
+	Tenant string `json:"tenant,omitempty"`
+	// AssumedBy is the user who assumed the role of the session.
+	AssumedBy string `json:"assumedBy,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWT signs and verifies the JSON web tokens with a single algorithm.
+type JWT struct {
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	issuer    string
+	timeout   time.Duration
+}
+
+// NewJWT creates a JWT which issues tokens valid for timeout.
+// For HS256 the key is the shared secret, for RS256 and EdDSA the key is the PEM encoded private key
+// and the public key used for verification is derived from it.
+func NewJWT(algorithm string, key []byte, issuer string, timeout time.Duration) (*JWT, error) {
+	j := &JWT{issuer: issuer, timeout: timeout}
+	switch algorithm {
+	case HS256:
+		if len(key) == 0 {
+			return nil, fmt.Errorf("auth: %s key must not be empty", algorithm)
+		}
+		j.method, j.signKey, j.verifyKey = jwt.SigningMethodHS256, key, key
+	case RS256:
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(key)
+		if err != nil {
+			return nil, fmt.Errorf("auth: parse %s private key: %w", algorithm, err)
+		}
+		j.method, j.signKey, j.verifyKey = jwt.SigningMethodRS256, privateKey, &privateKey.PublicKey
+	case EdDSA:
+		privateKey, err := jwt.ParseEdPrivateKeyFromPEM(key)
+		if err != nil {
+			return nil, fmt.Errorf("auth: parse %s private key: %w", algorithm, err)
+		}
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("auth: %s private key has no public key", algorithm)
+		}
+		j.method, j.signKey, j.verifyKey = jwt.SigningMethodEdDSA, privateKey, signer.Public()
+	default:
+		return nil, fmt.Errorf("auth: signing algorithm %q is not supported", algorithm)
+	}
+	return j, nil
+}
+
+// Sign issues a token for the subject of the tenant, and returns it with its expiration time.
+func (j *JWT) Sign(tenant, subject string) (string, time.Time, error) {
+	return j.sign(tenant, subject, "", j.timeout)
+}
+
+// SignSession issues a token of the role session assumed by the user, which is valid for ttl
+// instead of the timeout of the JWT. It returns the token with its expiration time.
+func (j *JWT) SignSession(tenant, subject, assumedBy string, ttl time.Duration) (string, time.Time, error) {
+	return j.sign(tenant, subject, assumedBy, ttl)
+}
+
+func (j *JWT) sign(tenant, subject, assumedBy string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := &Claims{
+		Tenant:    tenant,
+		AssumedBy: assumedBy,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        rand.Text(),
+			Issuer:    j.issuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(j.method, claims).SignedString(j.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("auth: sign token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Parse verifies the token and returns its claims.
+// It returns ErrTokenExpired if the token is expired, and ErrTokenInvalid for other failures.
+func (j *JWT) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(*jwt.Token) (any, error) { return j.verifyKey, nil },
+		jwt.WithValidMethods([]string{j.method.Alg()}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: %w", ErrTokenExpired, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: subject is missing", ErrTokenInvalid)
+	}
+	return claims, nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+const secretKeyBytes = 32
+
+// NewAccessKey generates a random access key which identifies a secret publicly.
+// It is 26 characters of the base32 alphabet and carries 128 bits of randomness.
+func NewAccessKey() string {
+	return rand.Text()
+}
+
+// NewSecretKey generates a random secret key with 256 bits of randomness encoded in unpadded base64url.
+func NewSecretKey() (string, error) {
+	b := make([]byte, secretKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// KeyCipher seals the signing keys of the secrets at rest with a server key. The signing keys of HMAC
+// must be known to verify the signatures, so a digest of them is as good as the plain keys, while the sealed
+// keys are useless to sign the requests without the server key.
+type KeyCipher struct {
+	aead cipher.AEAD
+}
+
+// NewKeyCipher creates a KeyCipher of AES-256-GCM, whose key is the SHA-256 digest of the server key.
+func NewKeyCipher(serverKey string) (*KeyCipher, error) {
+	if serverKey == "" {
+		return nil, errors.New("server key must not be empty")
+	}
+	key := sha256.Sum256([]byte(serverKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyCipher{aead: aead}, nil
+}
+
+// Seal encrypts the signing key and returns it in unpadded base64. The additional data, like the access key
+// of the secret, is authenticated but not encrypted, so that a sealed key is opened only for its secret.
+func (c *KeyCipher) Seal(signingKey, additionalData []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(signingKey)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, signingKey, additionalData)), nil
+}
+
+// Open decrypts the signing key sealed by Seal with the same additional data.
+func (c *KeyCipher) Open(sealed string, additionalData []byte) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < c.aead.NonceSize() {
+		return nil, errors.New("sealed key is too short")
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,158 @@
+// Package authz implements the authorization decision engine of siam.
+//
+// The engine evaluates the policy documents applying to a subject with the
+// following semantics:
+//
+//   - A statement matches a request if one of its actions and one of its
+//     resources match the request, and all of its conditions are satisfied.
+//     Actions and resources support the '*' and '?' wildcards.
+//   - If any matching statement denies the request, the request is denied
+//     (explicit deny wins).
+//   - Otherwise, if any matching statement allows the request, the request
+//     is allowed.
+//   - Otherwise, the request is denied implicitly.
+package authz
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/strayca7/siam/pkg/policy"
+)
+
+// Well-known context keys which the conditions can refer to.
+const (
+	// ContextKeySourceIP is the IP address of the client who makes the request.
+	ContextKeySourceIP = "sourceIp"
+	// ContextKeyCurrentTime is the RFC 3339 time of the request, it is filled by the engine if absent.
+	ContextKeyCurrentTime = "currentTime"
+)
+
+// Request is an authorization request which asks whether the subject can do the action on the resource.
+type Request struct {
+	Subject  string            `json:"subject"  binding:"required"`
+	Action   string            `json:"action"   binding:"required"`
+	Resource string            `json:"resource" binding:"required"`
+	Context  map[string]string `json:"context,omitempty"`
+}
+
+// Policy is a named policy document to be evaluated.
+type Policy struct {
+	Name     string
+	Document policy.Document
+}
+
+// StatementRef identifies a statement of a policy for auditing.
+type StatementRef struct {
+	Policy string `json:"policy"`
+	// Statement is the statement ID, or "#<index>" if the statement has no ID.
+	Statement string `json:"statement"`
+}
+
+// Decision is the result of an authorization request.
+type Decision struct {
+	Allowed bool `json:"allowed"`
+	// Matched lists the statements which decided the result: the denying statements if the request is denied
+	// explicitly, the allowing statements if it is allowed, and nothing if it is denied implicitly.
+	Matched []StatementRef `json:"matched"`
+}
+
+// PolicyGetter returns the policies applying to the subject.
+type PolicyGetter interface {
+	GetPolicies(ctx context.Context, subject string) ([]Policy, error)
+}
+
+// Authorizer evaluates the requests with the policies returned by its PolicyGetter.
+type Authorizer struct {
+	getter PolicyGetter
+	now    func() time.Time
+}
+
+// NewAuthorizer creates an Authorizer with the given PolicyGetter.
+func NewAuthorizer(getter PolicyGetter) *Authorizer {
+	return &Authorizer{getter: getter, now: time.Now}
+}
+
+// Authorize makes the decision for a single request.
+func (a *Authorizer) Authorize(ctx context.Context, req *Request) (*Decision, error) {
+	decisions, err := a.AuthorizeBatch(ctx, []*Request{req})
+	if err != nil {
+		return nil, err
+	}
+	return decisions[0], nil
+}
+
+// AuthorizeBatch makes the decisions for the requests in order,
+// the policies of each subject are fetched only once.
+func (a *Authorizer) AuthorizeBatch(ctx context.Context, reqs []*Request) ([]*Decision, error) {
+	now := a.now()
+	cache := map[string][]Policy{}
+	decisions := make([]*Decision, 0, len(reqs))
+	for _, req := range reqs {
+		policies, ok := cache[req.Subject]
+		if !ok {
+			var err error
+			policies, err = a.getter.GetPolicies(ctx, req.Subject)
+			if err != nil {
+				return nil, err
+			}
+			cache[req.Subject] = policies
+		}
+		decisions = append(decisions, evaluate(policies, req, now))
+	}
+	return decisions, nil
+}
+
+// Evaluate makes the decision for the request with the given policies.
+func Evaluate(policies []Policy, req *Request) *Decision {
+	return evaluate(policies, req, time.Now())
+}
+
+func evaluate(policies []Policy, req *Request, now time.Time) *Decision {
+	reqCtx := make(map[string]string, len(req.Context)+1)
+	for k, v := range req.Context {
+		reqCtx[k] = v
+	}
+	if _, ok := reqCtx[ContextKeyCurrentTime]; !ok {
+		reqCtx[ContextKeyCurrentTime] = now.Format(time.RFC3339)
+	}
+
+	var allowed, denied []StatementRef
+	for _, pol := range policies {
+		for i := range pol.Document.Statements {
+			st := &pol.Document.Statements[i]
+			if !matchStatement(st, req, reqCtx) {
+				continue
+			}
+			ref := StatementRef{Policy: pol.Name, Statement: statementID(st, i)}
+			if st.Effect == policy.Deny {
+				denied = append(denied, ref)
+			} else {
+				allowed = append(allowed, ref)
+			}
+		}
+	}
+
+	switch {
+	case len(denied) > 0:
+		return &Decision{Allowed: false, Matched: denied}
+	case len(allowed) > 0:
+		return &Decision{Allowed: true, Matched: allowed}
+	default:
+		return &Decision{Allowed: false, Matched: []StatementRef{}}
+	}
+}
+
+func matchStatement(st *policy.Statement, req *Request, reqCtx map[string]string) bool {
+	return matchAny(st.Actions, req.Action) &&
+		matchAny(st.Resources, req.Resource) &&
+		matchConditions(st.Conditions, reqCtx)
+}
+
+func statementID(st *policy.Statement, index int) string {
+	if st.ID != "" {
+		return st.ID
+	}
+	return "#" + strconv.Itoa(index)
+}
@@ -0,0 +1,185 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/strayca7/siam/pkg/policy"
+)
+
+func document(statements ...policy.Statement) policy.Document {
+	return policy.Document{Version: policy.Version20251001, Statements: statements}
+}
+
+func TestEvaluate(t *testing.T) {
+	allowRead := Policy{Name: "read", Document: document(policy.Statement{
+		ID:        "AllowRead",
+		Effect:    policy.Allow,
+		Actions:   []string{"article:get", "article:list"},
+		Resources: []string{"article/*"},
+	})}
+	allowAll := Policy{Name: "all", Document: document(policy.Statement{
+		Effect:    policy.Allow,
+		Actions:   []string{"*"},
+		Resources: []string{"*"},
+	})}
+	denySecret := Policy{Name: "secret", Document: document(policy.Statement{
+		ID:        "DenySecret",
+		Effect:    policy.Deny,
+		Actions:   []string{"*"},
+		Resources: []string{"article/secret-*"},
+	})}
+	denyOutside := Policy{Name: "network", Document: document(policy.Statement{
+		ID:         "DenyOutside",
+		Effect:     policy.Deny,
+		Actions:    []string{"*"},
+		Resources:  []string{"*"},
+		Conditions: policy.Conditions{policy.NotIpAddress: {ContextKeySourceIP: {"10.0.0.0/8"}}},
+	})}
+
+	tests := []struct {
+		name     string
+		policies []Policy
+		req      *Request
+		want     *Decision
+	}{
+		{
+			name: "no policies",
+			req:  &Request{Subject: "alice", Action: "article:get", Resource: "article/1"},
+			want: &Decision{Matched: []StatementRef{}},
+		},
+		{
+			name:     "allowed",
+			policies: []Policy{allowRead},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "article/1"},
+			want:     &Decision{Allowed: true, Matched: []StatementRef{{Policy: "read", Statement: "AllowRead"}}},
+		},
+		{
+			name:     "action not matched",
+			policies: []Policy{allowRead},
+			req:      &Request{Subject: "alice", Action: "article:delete", Resource: "article/1"},
+			want:     &Decision{Matched: []StatementRef{}},
+		},
+		{
+			name:     "resource not matched",
+			policies: []Policy{allowRead},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "user/1"},
+			want:     &Decision{Matched: []StatementRef{}},
+		},
+		{
+			name:     "statement without id",
+			policies: []Policy{allowAll},
+			req:      &Request{Subject: "alice", Action: "user:delete", Resource: "user/1"},
+			want:     &Decision{Allowed: true, Matched: []StatementRef{{Policy: "all", Statement: "#0"}}},
+		},
+		{
+			name:     "all of the allowing statements",
+			policies: []Policy{allowRead, allowAll},
+			req:      &Request{Subject: "alice", Action: "article:list", Resource: "article/1"},
+			want: &Decision{Allowed: true, Matched: []StatementRef{
+				{Policy: "read", Statement: "AllowRead"},
+				{Policy: "all", Statement: "#0"},
+			}},
+		},
+		{
+			name:     "deny wins",
+			policies: []Policy{allowAll, denySecret},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "article/secret-1"},
+			want:     &Decision{Matched: []StatementRef{{Policy: "secret", Statement: "DenySecret"}}},
+		},
+		{
+			name:     "deny wins regardless of the order",
+			policies: []Policy{denySecret, allowAll},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "article/secret-1"},
+			want:     &Decision{Matched: []StatementRef{{Policy: "secret", Statement: "DenySecret"}}},
+		},
+		{
+			name:     "deny not matched",
+			policies: []Policy{allowAll, denySecret},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "article/public-1"},
+			want:     &Decision{Allowed: true, Matched: []StatementRef{{Policy: "all", Statement: "#0"}}},
+		},
+		{
+			name:     "conditional deny",
+			policies: []Policy{allowAll, denyOutside},
+			req: &Request{Subject: "alice", Action: "article:get", Resource: "article/1",
+				Context: map[string]string{ContextKeySourceIP: "192.168.1.1"}},
+			want: &Decision{Matched: []StatementRef{{Policy: "network", Statement: "DenyOutside"}}},
+		},
+		{
+			name:     "conditional deny not matched",
+			policies: []Policy{allowAll, denyOutside},
+			req: &Request{Subject: "alice", Action: "article:get", Resource: "article/1",
+				Context: map[string]string{ContextKeySourceIP: "10.0.0.1"}},
+			want: &Decision{Allowed: true, Matched: []StatementRef{{Policy: "all", Statement: "#0"}}},
+		},
+		{
+			name:     "conditional deny without the context key",
+			policies: []Policy{allowAll, denyOutside},
+			req:      &Request{Subject: "alice", Action: "article:get", Resource: "article/1"},
+			want:     &Decision{Allowed: true, Matched: []StatementRef{{Policy: "all", Statement: "#0"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Evaluate(tt.policies, tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// getter returns the policies of the subjects and counts the calls.
+type getter struct {
+	policies map[string][]Policy
+	calls    map[string]int
+	err      error
+}
+
+func (g *getter) GetPolicies(_ context.Context, subject string) ([]Policy, error) {
+	g.calls[subject]++
+	return g.policies[subject], g.err
+}
+
+func TestAuthorizeBatch(t *testing.T) {
+	business := Policy{Name: "business", Document: document(policy.Statement{
+		Effect:     policy.Allow,
+		Actions:    []string{"*"},
+		Resources:  []string{"*"},
+		Conditions: policy.Conditions{policy.DateLessThan: {ContextKeyCurrentTime: {"2025-10-01T18:00:00Z"}}},
+	})}
+	g := &getter{policies: map[string][]Policy{"alice": {business}}, calls: map[string]int{}}
+	a := NewAuthorizer(g)
+	a.now = func() time.Time { return time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC) }
+
+	reqs := []*Request{
+		{Subject: "alice", Action: "article:get", Resource: "article/1"},
+		{Subject: "bob", Action: "article:get", Resource: "article/1"},
+		{Subject: "alice", Action: "article:get", Resource: "article/1",
+			Context: map[string]string{ContextKeyCurrentTime: "2025-10-01T20:00:00Z"}},
+	}
+	decisions, err := a.AuthorizeBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatalf("AuthorizeBatch() error = %v", err)
+	}
+	want := []bool{true, false, false}
+	if len(decisions) != len(want) {
+		t.Fatalf("AuthorizeBatch() returned %d decisions, want %d", len(decisions), len(want))
+	}
+	for i, d := range decisions {
+		if d.Allowed != want[i] {
+			t.Errorf("decision %d allowed = %v, want %v", i, d.Allowed, want[i])
+		}
+	}
+	if want := map[string]int{"alice": 1, "bob": 1}; !reflect.DeepEqual(g.calls, want) {
+		t.Errorf("GetPolicies calls = %v, want %v", g.calls, want)
+	}
+
+	g.err = errors.New("store is down")
+	if _, err := a.Authorize(context.Background(), reqs[0]); !errors.Is(err, g.err) {
+		t.Errorf("Authorize() error = %v, want %v", err, g.err)
+	}
+}
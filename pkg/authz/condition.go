@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"net"
+	"time"
+
+	"github.com/strayca7/siam/pkg/policy"
+)
+
+// matchConditions reports whether all of the conditions are satisfied by the request context.
+// A condition on a key absent from the context is never satisfied.
+func matchConditions(conds policy.Conditions, reqCtx map[string]string) bool {
+	for op, kvs := range conds {
+		for key, values := range kvs {
+			actual, ok := reqCtx[key]
+			if !ok || !matchCondition(op, actual, values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchCondition(op, actual string, values []string) bool {
+	switch op {
+	case policy.StringEquals:
+		return containsString(values, actual)
+	case policy.StringNotEquals:
+		return !containsString(values, actual)
+	case policy.StringLike:
+		return matchAny(values, actual)
+	case policy.IpAddress:
+		ip := net.ParseIP(actual)
+		return ip != nil && containsIP(values, ip)
+	case policy.NotIpAddress:
+		ip := net.ParseIP(actual)
+		return ip != nil && !containsIP(values, ip)
+	case policy.DateGreaterThan:
+		return compareTime(actual, values, func(a, v time.Time) bool { return a.After(v) })
+	case policy.DateLessThan:
+		return compareTime(actual, values, func(a, v time.Time) bool { return a.Before(v) })
+	default:
+		// unknown operators are rejected by the document validation, never match them
+		return false
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsIP reports whether the ip is equal to any of the IPs or inside any of the CIDR blocks.
+func containsIP(values []string, ip net.IP) bool {
+	for _, v := range values {
+		if _, block, err := net.ParseCIDR(v); err == nil {
+			if block.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if other := net.ParseIP(v); other != nil && other.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareTime reports whether the actual time satisfies cmp with any of the RFC 3339 values.
+func compareTime(actual string, values []string, cmp func(a, v time.Time) bool) bool {
+	at, err := time.Parse(time.RFC3339, actual)
+	if err != nil {
+		return false
+	}
+	for _, v := range values {
+		vt, err := time.Parse(time.RFC3339, v)
+		if err == nil && cmp(at, vt) {
+			return true
+		}
+	}
+	return false
+}
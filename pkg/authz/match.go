@@ -0,0 +1,39 @@
+package authz
+
+// matchAny reports whether the value matches any of the glob patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the value matches the pattern, in which '*' matches any sequence of characters
+// (including '/' and ':') and '?' matches exactly one character. Other characters match themselves.
+func matchGlob(pattern, value string) bool {
+	// iterative wildcard matching with backtracking to the last '*'
+	p, v := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, v
+			p++
+		case star != -1:
+			p = star + 1
+			mark++
+			v = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/strayca7/siam/pkg/policy"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "article/1", true},
+		{"article:get", "article:get", true},
+		{"article:get", "article:list", false},
+		{"article:*", "article:get", true},
+		{"article:*", "user:get", false},
+		{"article/*", "article/a/b:c", true},
+		{"*:get", "article:get", true},
+		{"*:get", "article:list", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"a*bc", "abcbc", true},
+		{"article/?", "article/1", true},
+		{"article/?", "article/12", false},
+		{"article/?", "article/", false},
+		{"??", "ab", true},
+		{"a**", "a", true},
+		{"*?", "", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatchConditions(t *testing.T) {
+	reqCtx := map[string]string{
+		ContextKeySourceIP:    "10.1.2.3",
+		ContextKeyCurrentTime: "2025-10-01T12:00:00Z",
+		"department":          "sales",
+	}
+	tests := []struct {
+		name  string
+		conds policy.Conditions
+		want  bool
+	}{
+		{"no conditions", nil, true},
+		{"string equals", policy.Conditions{policy.StringEquals: {"department": {"hr", "sales"}}}, true},
+		{"string equals mismatch", policy.Conditions{policy.StringEquals: {"department": {"hr"}}}, false},
+		{"string not equals", policy.Conditions{policy.StringNotEquals: {"department": {"hr"}}}, true},
+		{"string not equals mismatch", policy.Conditions{policy.StringNotEquals: {"department": {"sales"}}}, false},
+		{"string like", policy.Conditions{policy.StringLike: {"department": {"s*"}}}, true},
+		{"string like mismatch", policy.Conditions{policy.StringLike: {"department": {"h?"}}}, false},
+		{"ip in block", policy.Conditions{policy.IpAddress: {ContextKeySourceIP: {"10.0.0.0/8"}}}, true},
+		{"ip equal", policy.Conditions{policy.IpAddress: {ContextKeySourceIP: {"10.1.2.3"}}}, true},
+		{"ip outside block", policy.Conditions{policy.IpAddress: {ContextKeySourceIP: {"192.168.0.0/16"}}}, false},
+		{"not ip outside block", policy.Conditions{policy.NotIpAddress: {ContextKeySourceIP: {"192.168.0.0/16"}}}, true},
+		{"not ip in block", policy.Conditions{policy.NotIpAddress: {ContextKeySourceIP: {"10.0.0.0/8"}}}, false},
+		{"ip of a string", policy.Conditions{policy.IpAddress: {"department": {"10.0.0.0/8"}}}, false},
+		{"date after", policy.Conditions{policy.DateGreaterThan: {
+			ContextKeyCurrentTime: {"2025-01-01T00:00:00Z"}}}, true},
+		{"date not after", policy.Conditions{policy.DateGreaterThan: {
+			ContextKeyCurrentTime: {"2026-01-01T00:00:00Z"}}}, false},
+		{"date before", policy.Conditions{policy.DateLessThan: {
+			ContextKeyCurrentTime: {"2026-01-01T00:00:00Z"}}}, true},
+		{"date not before", policy.Conditions{policy.DateLessThan: {
+			ContextKeyCurrentTime: {"2025-10-01T12:00:00Z"}}}, false},
+		{"absent key", policy.Conditions{policy.StringNotEquals: {"team": {"a"}}}, false},
+		{"unknown operator", policy.Conditions{"Bool": {"department": {"true"}}}, false},
+		{"all of the operators", policy.Conditions{
+			policy.StringEquals: {"department": {"sales"}},
+			policy.IpAddress:    {ContextKeySourceIP: {"10.0.0.0/8"}},
+		}, true},
+		{"one of the operators fails", policy.Conditions{
+			policy.StringEquals: {"department": {"sales"}},
+			policy.IpAddress:    {ContextKeySourceIP: {"172.16.0.0/12"}},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchConditions(tt.conds, reqCtx); got != tt.want {
+				t.Errorf("matchConditions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
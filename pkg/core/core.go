@@ -0,0 +1,46 @@
+// Package core provides the common helpers shared by the gin based HTTP servers.
+package core
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/response"
+)
+
+// ErrResponse defines the return messages when an error occurred, see response.ErrorResponse.
+type ErrResponse = response.ErrorResponse
+
+// WriteResponse writes an error or the response data into the http response body.
+// The data is written as it is, only the errors are enveloped, see package response.
+// It uses serrors.ParseCoder to parse any error into serrors.Coder,
+// the HTTP status and message of the response are driven by the registered code,
+// and the message is translated by the Accept-Language header of the request.
+// The stack of the error is included in the response only in the debug mode of gin.
+// The server errors are logged with their stacks, the client errors are logged at the debug level.
+func WriteResponse(c *gin.Context, err error, data any) {
+	if err != nil {
+		status, resp := ErrorResponse(c, err)
+		if status >= http.StatusInternalServerError {
+			logger.L().Error("request failed", zap.String("error", fmt.Sprintf("%#+v", err)))
+		} else {
+			logger.L().Debug("request failed", zap.Int("status", status), zap.Error(err))
+		}
+		resp.SetRetryAfter(c.Writer.Header())
+		c.JSON(status, resp)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// ErrorResponse returns the HTTP status and the error response of the error of the request,
+// it is used to report the error in the other forms than a response body.
+func ErrorResponse(c *gin.Context, err error) (int, *ErrResponse) {
+	return response.NewError(c.Request, err, gin.IsDebugging())
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Supported database drivers.
+const (
+	Postgres = "postgres"
+	MySQL    = "mysql"
+	SQLite   = "sqlite"
+)
+
+// Options defines the configuration options to connect to a database.
+type Options struct {
+	Driver   string
+	Host     string
+	User     string
+	Password string
+	Database string
+	// Port is the port of the database service, 0 means the default port of the driver.
+	Port int
+	// SSLMode is the sslmode of Postgres, or the tls parameter of MySQL.
+	SSLMode  string
+	TimeZone string
+	// Path is the database file of SQLite.
+	Path            string
+	MaxIdleConns    int
+	MaxOpenConns    int
+	ConnMaxIdleTime int
+	ConnMaxLifetime int
+	// ConnectTimeout is the deadline of the connection retries when the database is opened,
+	// 0 means the connection is tried only once.
+	ConnectTimeout time.Duration
+	// Replicas are the `host[:port]` addresses of the read-only replicas of Postgres or MySQL,
+	// which share the other options with the primary, see WithPrimary for the routing.
+	Replicas []string
+	// Logger logs the connection retries, nil disables the logging.
+	Logger *zap.Logger
+}
+
+// Backoff of the connection retries, the interval doubles after every failed attempt.
+const (
+	initialRetryInterval = 500 * time.Millisecond
+	maxRetryInterval     = 8 * time.Second
+)
+
+// dialector returns the gorm dialector of the driver.
+func dialector(opts *Options) (gorm.Dialector, error) {
+	switch opts.Driver {
+	case Postgres:
+		return postgresDialector(opts), nil
+	case MySQL:
+		return mysqlDialector(opts)
+	case SQLite:
+		return sqliteDialector(opts), nil
+	default:
+		return nil, fmt.Errorf("database driver %q is not supported", opts.Driver)
+	}
+}
+
+// New create a new gorm db instance with the given options. The connection is retried with
+// an exponential backoff until it succeeds or the ConnectTimeout is exceeded.
+func New(opts *Options) (*gorm.DB, error) {
+	d, err := dialector(opts)
+	if err != nil {
+		return nil, err
+	}
+	db, err := open(d, opts)
+	if err != nil {
+		return nil, err
+	}
+	sqldb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	configurePool(sqldb, opts)
+
+	if len(opts.Replicas) > 0 {
+		r, err := newResolver(opts)
+		if err != nil {
+			_ = sqldb.Close()
+			return nil, err
+		}
+		if err := db.Use(r); err != nil {
+			_ = r.close()
+			_ = sqldb.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Close closes the primary and the replicas of the database.
+func Close(db *gorm.DB) error {
+	var errs []error
+	if r := resolverOf(db); r != nil {
+		errs = append(errs, r.close())
+	}
+	sqldb, err := db.DB()
+	if err != nil {
+		return err
+	}
+	errs = append(errs, sqldb.Close())
+	return errors.Join(errs...)
+}
+
+func configurePool(sqldb *sql.DB, opts *Options) {
+	sqldb.SetMaxIdleConns(opts.MaxIdleConns)
+	sqldb.SetMaxOpenConns(opts.MaxOpenConns)
+	sqldb.SetConnMaxIdleTime(time.Duration(opts.ConnMaxIdleTime) * time.Minute)
+	sqldb.SetConnMaxLifetime(time.Duration(opts.ConnMaxLifetime) * time.Minute)
+}
+
+// logger returns the logger of the options, or a no-op logger if it is not set.
+func (opts *Options) logger() *zap.Logger {
+	if opts.Logger == nil {
+		return zap.NewNop()
+	}
+	return opts.Logger
+}
+
+// open opens the database and pings it, retrying until it succeeds or the deadline is exceeded.
+func open(d gorm.Dialector, opts *Options) (*gorm.DB, error) {
+	log := opts.logger()
+	deadline := time.Now().Add(opts.ConnectTimeout)
+	interval := initialRetryInterval
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(d, &gorm.Config{TranslateError: true})
+		if err == nil {
+			return db, nil
+		}
+		// the connection pool may have been created before the ping failed
+		if db != nil {
+			if sqldb, dbErr := db.DB(); dbErr == nil {
+				_ = sqldb.Close()
+			}
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			log.Error("Failed to connect to database, giving up",
+				zap.String("driver", opts.Driver),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("connect to %s database after %d attempts: %w", opts.Driver, attempt, err)
+		}
+		log.Warn("Failed to connect to database, retrying",
+			zap.String("driver", opts.Driver),
+			zap.Int("attempt", attempt),
+			zap.Duration("retryIn", interval),
+			zap.Error(err),
+		)
+		time.Sleep(interval)
+		interval = min(interval*2, maxRetryInterval)
+	}
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// pingTimeout is the maximum time to wait for a health check ping.
+const pingTimeout = 5 * time.Second
+
+// HealthCheck pings the database every interval and logs its health with the connection pool stats
+// to the logger, it blocks until the context is canceled. The unhealthy replicas are ejected from
+// the read routing, and they are readmitted once they are healthy again.
+func HealthCheck(ctx context.Context, db *gorm.DB, interval time.Duration, log *zap.Logger) {
+	sqldb, err := db.DB()
+	if err != nil {
+		log.Error("Failed to get the database connection pool", zap.Error(err))
+		return
+	}
+	var replicas []*replica
+	if r := resolverOf(db); r != nil {
+		replicas = r.replicas
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := ping(ctx, sqldb, interval)
+		if ctx.Err() != nil {
+			return
+		}
+		fields := statsFields(sqldb.Stats())
+		switch {
+		case err != nil:
+			log.Error("Database is unhealthy", append(fields, zap.Error(err))...)
+		case !healthy:
+			log.Info("Database is healthy again", fields...)
+		default:
+			log.Info("Database connection pool stats", fields...)
+		}
+		healthy = err == nil
+
+		for _, rep := range replicas {
+			checkReplica(ctx, rep, interval, log)
+		}
+	}
+}
+
+// checkReplica pings the replica, ejects it if it fails and readmits it if it succeeds again.
+func checkReplica(ctx context.Context, rep *replica, interval time.Duration, log *zap.Logger) {
+	err := ping(ctx, rep.db, interval)
+	if ctx.Err() != nil {
+		return
+	}
+	fields := append(statsFields(rep.db.Stats()), zap.String("replica", rep.addr))
+	switch {
+	case err != nil:
+		if rep.healthy.Swap(false) {
+			log.Error("Database replica is unhealthy, it is ejected", append(fields, zap.Error(err))...)
+		} else {
+			log.Warn("Database replica is still unhealthy", append(fields, zap.Error(err))...)
+		}
+	case !rep.healthy.Swap(true):
+		log.Info("Database replica is healthy again, it is readmitted", fields...)
+	default:
+		log.Info("Database replica connection pool stats", fields...)
+	}
+}
+
+func ping(ctx context.Context, db *sql.DB, interval time.Duration) error {
+	pingCtx, cancel := context.WithTimeout(ctx, min(interval, pingTimeout))
+	defer cancel()
+	return db.PingContext(pingCtx)
+}
+
+func statsFields(stats sql.DBStats) []zap.Field {
+	return []zap.Field{
+		zap.Int("maxOpen", stats.MaxOpenConnections),
+		zap.Int("open", stats.OpenConnections),
+		zap.Int("inUse", stats.InUse),
+		zap.Int("idle", stats.Idle),
+		zap.Int64("waitCount", stats.WaitCount),
+		zap.Duration("waitDuration", stats.WaitDuration),
+	}
+}
@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// lockName identifies the migration lock of siam in the database.
+const lockName = "siam:" + Table
+
+// Dialect is the SQL differences of a database which the migrator depends on.
+type Dialect struct {
+	// Name is the name of the database driver.
+	Name string
+	// timestamp is the column type of the applied time.
+	timestamp string
+	// rebind converts the `?` placeholders of the query to the ones of the database.
+	rebind func(query string) string
+	// lock acquires the migration lock of the session, nil if the database does not need it.
+	lock   func(ctx context.Context, conn *sql.Conn) error
+	unlock func(ctx context.Context, conn *sql.Conn) error
+}
+
+var (
+	// Postgres holds an advisory lock of the session, the DDL of Postgres is transactional,
+	// so a failed migration leaves nothing behind.
+	Postgres = &Dialect{
+		Name:      "postgres",
+		timestamp: "TIMESTAMPTZ",
+		rebind:    dollarPlaceholders,
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockID())
+			return err
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockID())
+			return err
+		},
+	}
+
+	// MySQL holds a named lock of the session. The DDL of MySQL commits implicitly,
+	// so a migration should contain a single statement to be applied atomically.
+	MySQL = &Dialect{
+		Name:      "mysql",
+		timestamp: "DATETIME(6)",
+		rebind:    func(query string) string { return query },
+		lock: func(ctx context.Context, conn *sql.Conn) error {
+			var ok sql.NullInt64
+			if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, -1)", lockName).Scan(&ok); err != nil {
+				return err
+			}
+			if ok.Int64 != 1 {
+				return fmt.Errorf("lock %q is not granted", lockName)
+			}
+			return nil
+		},
+		unlock: func(ctx context.Context, conn *sql.Conn) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName)
+			return err
+		},
+	}
+
+	// SQLite has no session lock, the database file is expected to be owned by a single server,
+	// and the concurrent writers are serialized by the database anyway.
+	SQLite = &Dialect{
+		Name:      "sqlite",
+		timestamp: "DATETIME",
+		rebind:    func(query string) string { return query },
+	}
+)
+
+// LookupDialect returns the dialect of the database driver.
+func LookupDialect(driver string) (*Dialect, error) {
+	for _, d := range []*Dialect{Postgres, MySQL, SQLite} {
+		if d.Name == driver {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("migrate: database driver %q is not supported", driver)
+}
+
+// advisoryLockID derives the Postgres advisory lock key from the lock name.
+func advisoryLockID() int64 {
+	h := fnv.New64a()
+	h.Write([]byte(lockName))
+	return int64(h.Sum64())
+}
+
+// dollarPlaceholders converts the `?` placeholders to `$1`, `$2`, ...
+func dollarPlaceholders(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
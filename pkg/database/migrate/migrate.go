@@ -0,0 +1,99 @@
+// Package migrate applies the versioned SQL migrations to a Postgres, MySQL or SQLite database.
+//
+// A migration is a pair of scripts named `<version>_<name>.up.sql` and `<version>_<name>.down.sql`,
+// the version is a positive integer and the migrations are applied in the ascending order of the versions.
+// The applied migrations are recorded in the schema_migrations table together with the checksum of the up
+// script, so that an applied migration which has been edited afterwards is detected.
+//
+// Every migration runs in its own transaction, and all of the operations hold a lock of the database
+// session where the Dialect supports it, so that multiple replicas can run the migrations concurrently on start.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrChecksumMismatch is returned when the up script of an applied migration has been changed.
+	ErrChecksumMismatch = errors.New("migrate: checksum of an applied migration does not match")
+	// ErrUnknownVersion is returned when the database has an applied version which is not found in the migrations,
+	// or the target version does not exist.
+	ErrUnknownVersion = errors.New("migrate: unknown version")
+	// ErrNoDownScript is returned when a migration to roll back has no down script.
+	ErrNoDownScript = errors.New("migrate: no down script")
+)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the hex encoded SHA-256 of the up script.
+	Checksum string
+}
+
+var filenameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load loads the migrations from the root directory of fsys, the other files are ignored.
+// It returns the migrations sorted by version.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		matches := filenameRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migrate: invalid version of %s", entry.Name())
+		}
+		data, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		}
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("migrate: version %d has different names %q and %q", version, m.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrate: version %d has no up script", m.Version)
+		}
+		m.Checksum = checksum(m.Up)
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
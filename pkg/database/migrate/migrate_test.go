@@ -0,0 +1,370 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/glebarez/sqlite"
+
+	"github.com/strayca7/siam/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "migrate")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// testMigrations records the order they are applied and rolled back in the applied table.
+var testMigrations = fstest.MapFS{
+	"1_create_applied.up.sql":   {Data: []byte("CREATE TABLE applied (version INTEGER NOT NULL)")},
+	"1_create_applied.down.sql": {Data: []byte("DROP TABLE applied")},
+	"2_second.up.sql":           {Data: []byte("INSERT INTO applied (version) VALUES (2)")},
+	"2_second.down.sql":         {Data: []byte("INSERT INTO applied (version) VALUES (-2)")},
+	"10_tenth.up.sql":           {Data: []byte("INSERT INTO applied (version) VALUES (10)")},
+	"10_tenth.down.sql":         {Data: []byte("INSERT INTO applied (version) VALUES (-10)")},
+	"9_ninth.up.sql":            {Data: []byte("INSERT INTO applied (version) VALUES (9)")},
+	"9_ninth.down.sql":          {Data: []byte("INSERT INTO applied (version) VALUES (-9)")},
+	"README.md":                 {Data: []byte("ignored")},
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func load(t *testing.T, fsys fstest.MapFS) []*Migration {
+	t.Helper()
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return migrations
+}
+
+// history returns the versions in the applied table in the order they were recorded.
+func history(t *testing.T, db *sql.DB) []int {
+	t.Helper()
+	rows, err := db.Query("SELECT version FROM applied ORDER BY rowid")
+	if err != nil {
+		t.Fatalf("query applied: %v", err)
+	}
+	defer rows.Close()
+	var versions []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			t.Fatalf("scan applied: %v", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// appliedVersions returns the versions recorded in the migration table.
+func appliedVersions(t *testing.T, m *Migrator) []uint64 {
+	t.Helper()
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	var versions []uint64
+	for _, s := range statuses {
+		if s.Applied {
+			versions = append(versions, s.Version)
+		}
+	}
+	return versions
+}
+
+func TestLoad(t *testing.T) {
+	migrations := load(t, testMigrations)
+	var versions []uint64
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+		if m.Checksum != checksum(m.Up) {
+			t.Errorf("checksum of version %d = %q, want the checksum of its up script", m.Version, m.Checksum)
+		}
+	}
+	if want := []uint64{1, 2, 9, 10}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("Load() versions = %v, want %v", versions, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no up script", fstest.MapFS{"1_init.down.sql": {Data: []byte("DROP TABLE t")}}},
+		{"zero version", fstest.MapFS{"0_init.up.sql": {Data: []byte("CREATE TABLE t (id INT)")}}},
+		{"different names", fstest.MapFS{
+			"1_init.up.sql":  {Data: []byte("CREATE TABLE t (id INT)")},
+			"1_other.up.sql": {Data: []byte("CREATE TABLE u (id INT)")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Errorf("Load() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	tests := []struct {
+		name        string
+		run         func(ctx context.Context, m *Migrator) error
+		wantHistory []int
+		wantApplied []uint64
+	}{
+		{
+			name:        "up applies in the order of the versions",
+			run:         func(ctx context.Context, m *Migrator) error { return m.Up(ctx) },
+			wantHistory: []int{2, 9, 10},
+			wantApplied: []uint64{1, 2, 9, 10},
+		},
+		{
+			name: "up is idempotent",
+			run: func(ctx context.Context, m *Migrator) error {
+				if err := m.Up(ctx); err != nil {
+					return err
+				}
+				return m.Up(ctx)
+			},
+			wantHistory: []int{2, 9, 10},
+			wantApplied: []uint64{1, 2, 9, 10},
+		},
+		{
+			name:        "to a version",
+			run:         func(ctx context.Context, m *Migrator) error { return m.To(ctx, 9) },
+			wantHistory: []int{2, 9},
+			wantApplied: []uint64{1, 2, 9},
+		},
+		{
+			name: "to a lower version rolls back in the reverse order",
+			run: func(ctx context.Context, m *Migrator) error {
+				if err := m.Up(ctx); err != nil {
+					return err
+				}
+				return m.To(ctx, 2)
+			},
+			wantHistory: []int{2, 9, 10, -10, -9},
+			wantApplied: []uint64{1, 2},
+		},
+		{
+			name: "down steps",
+			run: func(ctx context.Context, m *Migrator) error {
+				if err := m.Up(ctx); err != nil {
+					return err
+				}
+				return m.Down(ctx, 2)
+			},
+			wantHistory: []int{2, 9, 10, -10, -9},
+			wantApplied: []uint64{1, 2},
+		},
+		{
+			name: "down skips the pending migrations",
+			run: func(ctx context.Context, m *Migrator) error {
+				if err := m.To(ctx, 9); err != nil {
+					return err
+				}
+				return m.Down(ctx, 1)
+			},
+			wantHistory: []int{2, 9, -9},
+			wantApplied: []uint64{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openDB(t)
+			m := New(db, SQLite, load(t, testMigrations))
+			if err := tt.run(context.Background(), m); err != nil {
+				t.Fatalf("run error = %v", err)
+			}
+			if got := history(t, db); !reflect.DeepEqual(got, tt.wantHistory) {
+				t.Errorf("history = %v, want %v", got, tt.wantHistory)
+			}
+			if got := appliedVersions(t, m); !reflect.DeepEqual(got, tt.wantApplied) {
+				t.Errorf("applied versions = %v, want %v", got, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestMigratorErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown target version", func(t *testing.T) {
+		m := New(openDB(t), SQLite, load(t, testMigrations))
+		if err := m.To(ctx, 3); !errors.Is(err, ErrUnknownVersion) {
+			t.Errorf("To() error = %v, want %v", err, ErrUnknownVersion)
+		}
+	})
+
+	t.Run("applied version not found", func(t *testing.T) {
+		db := openDB(t)
+		if err := New(db, SQLite, load(t, testMigrations)).Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		fsys := fstest.MapFS{}
+		for name, file := range testMigrations {
+			if name != "10_tenth.up.sql" && name != "10_tenth.down.sql" {
+				fsys[name] = file
+			}
+		}
+		if err := New(db, SQLite, load(t, fsys)).Up(ctx); !errors.Is(err, ErrUnknownVersion) {
+			t.Errorf("Up() error = %v, want %v", err, ErrUnknownVersion)
+		}
+	})
+
+	t.Run("applied migration modified", func(t *testing.T) {
+		db := openDB(t)
+		if err := New(db, SQLite, load(t, testMigrations)).Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		fsys := fstest.MapFS{}
+		for name, file := range testMigrations {
+			fsys[name] = file
+		}
+		fsys["9_ninth.up.sql"] = &fstest.MapFile{Data: []byte("INSERT INTO applied (version) VALUES (99)")}
+		m := New(db, SQLite, load(t, fsys))
+		if err := m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("Up() error = %v, want %v", err, ErrChecksumMismatch)
+		}
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		for _, s := range statuses {
+			if s.Modified != (s.Version == 9) {
+				t.Errorf("status of version %d modified = %v", s.Version, s.Modified)
+			}
+		}
+	})
+
+	t.Run("no down script", func(t *testing.T) {
+		fsys := fstest.MapFS{"1_init.up.sql": {Data: []byte("CREATE TABLE t (id INTEGER)")}}
+		m := New(openDB(t), SQLite, load(t, fsys))
+		if err := m.Up(ctx); err != nil {
+			t.Fatalf("Up() error = %v", err)
+		}
+		if err := m.Down(ctx, 1); !errors.Is(err, ErrNoDownScript) {
+			t.Errorf("Down() error = %v, want %v", err, ErrNoDownScript)
+		}
+	})
+
+	t.Run("failed migration is not recorded", func(t *testing.T) {
+		fsys := fstest.MapFS{}
+		for name, file := range testMigrations {
+			fsys[name] = file
+		}
+		fsys["9_ninth.up.sql"] = &fstest.MapFile{Data: []byte("INSERT INTO missing (version) VALUES (9)")}
+		db := openDB(t)
+		m := New(db, SQLite, load(t, fsys))
+		if err := m.Up(ctx); err == nil {
+			t.Fatalf("Up() error = nil, want an error")
+		}
+		if got, want := history(t, db), []int{2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("history = %v, want %v", got, want)
+		}
+		if got, want := appliedVersions(t, m), []uint64{1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("applied versions = %v, want %v", got, want)
+		}
+	})
+}
+
+// lockingDialect is SQLite with a lock of the session, which records the connections of the lock operations.
+func lockingDialect(mu *sync.Mutex, events *[]string) *Dialect {
+	held := make(chan struct{}, 1)
+	var locked *sql.Conn
+	d := *SQLite
+	d.lock = func(ctx context.Context, conn *sql.Conn) error {
+		select {
+		case held <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		locked = conn
+		*events = append(*events, "lock")
+		return nil
+	}
+	d.unlock = func(_ context.Context, conn *sql.Conn) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if conn != locked {
+			*events = append(*events, "unlock on another connection")
+		} else {
+			*events = append(*events, "unlock")
+		}
+		<-held
+		return nil
+	}
+	return &d
+}
+
+func TestMigratorLock(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	db := openDB(t)
+	dialect := lockingDialect(&mu, &events)
+
+	// the concurrent migrators apply every migration once
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Go(func() {
+			errs[i] = New(db, dialect, load(t, testMigrations)).Up(context.Background())
+		})
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Up() of migrator %d error = %v", i, err)
+		}
+	}
+	if got, want := history(t, db), []int{2, 9, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("history = %v, want %v", got, want)
+	}
+	if want := []string{"lock", "unlock", "lock", "unlock", "lock", "unlock", "lock", "unlock"}; !reflect.DeepEqual(
+		events, want) {
+		t.Errorf("lock events = %v, want %v", events, want)
+	}
+
+	// the lock is released when the migration fails
+	events = nil
+	fsys := fstest.MapFS{"1_init.up.sql": {Data: []byte("CREATE TABLE applied (version INTEGER)")}}
+	if err := New(db, dialect, load(t, fsys)).Up(context.Background()); err == nil {
+		t.Fatalf("Up() error = nil, want an error")
+	}
+	if want := []string{"lock", "unlock"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("lock events = %v, want %v", events, want)
+	}
+}
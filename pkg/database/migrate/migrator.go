@@ -0,0 +1,256 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/pkg/logger"
+)
+
+// Table is the table which records the applied migrations.
+const Table = "schema_migrations"
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Modified indicates the up script has been changed after it was applied.
+	Modified bool
+}
+
+// record is a row of the schema_migrations table.
+type record struct {
+	version   uint64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies and rolls back the migrations.
+type Migrator struct {
+	db         *sql.DB
+	dialect    *Dialect
+	migrations []*Migration
+}
+
+// New creates a Migrator of the database with the dialect of its driver,
+// the migrations must be sorted by version, see Load.
+func New(db *sql.DB, dialect *Dialect, migrations []*Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}
+}
+
+// Latest returns the highest version of the migrations, 0 if there is none.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all of the pending migrations.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down rolls back the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, true, func(conn *sql.Conn, applied map[uint64]*record) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.rollback(ctx, conn, mig); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// To migrates the database to the version, the migrations above it are rolled back
+// and the pending migrations up to it are applied. Version 0 rolls back all of the migrations.
+func (m *Migrator) To(ctx context.Context, version uint64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, true, func(conn *sql.Conn, applied map[uint64]*record) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.rollback(ctx, conn, mig); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				if err := m.apply(ctx, conn, mig); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status returns the status of all of the migrations sorted by version.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	var statuses []*Status
+	err := m.withLock(ctx, false, func(_ *sql.Conn, applied map[uint64]*record) error {
+		for _, mig := range m.migrations {
+			s := &Status{Version: mig.Version, Name: mig.Name}
+			if r, ok := applied[mig.Version]; ok {
+				s.Applied = true
+				s.AppliedAt = r.appliedAt
+				s.Modified = r.checksum != mig.Checksum
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs fn on a single connection holding the migration lock with the applied migrations,
+// which are verified against the known migrations if verify is true.
+func (m *Migrator) withLock(ctx context.Context, verify bool, fn func(*sql.Conn, map[uint64]*record) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// the lock belongs to the session, so it must be released on the same connection
+	if m.dialect.lock != nil {
+		if err := m.dialect.lock(ctx, conn); err != nil {
+			return fmt.Errorf("migrate: acquire lock: %w", err)
+		}
+		defer func() {
+			// the context may be canceled, the lock must be released anyway
+			if err := m.dialect.unlock(context.Background(), conn); err != nil {
+				logger.L().Error("Failed to release the migration lock", zap.Error(err))
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+	version    BIGINT PRIMARY KEY,
+	name       VARCHAR(255) NOT NULL,
+	checksum   VARCHAR(64) NOT NULL,
+	applied_at `+m.dialect.timestamp+` NOT NULL
+)`); err != nil {
+		return fmt.Errorf("migrate: create %s: %w", Table, err)
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if verify {
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+	}
+	return fn(conn, applied)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[uint64]*record, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM "+Table)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: query %s: %w", Table, err)
+	}
+	defer rows.Close()
+
+	applied := map[uint64]*record{}
+	for rows.Next() {
+		r := &record{}
+		if err := rows.Scan(&r.version, &r.name, &r.checksum, &r.appliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: scan %s: %w", Table, err)
+		}
+		applied[r.version] = r
+	}
+	return applied, rows.Err()
+}
+
+// verify checks every applied migration is known and unchanged.
+func (m *Migrator) verify(applied map[uint64]*record) error {
+	for version, r := range applied {
+		mig := m.find(version)
+		if mig == nil {
+			return fmt.Errorf("%w: version %d (%s) is applied but not found", ErrUnknownVersion, version, r.name)
+		}
+		if mig.Checksum != r.checksum {
+			return fmt.Errorf("%w: version %d (%s)", ErrChecksumMismatch, version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version uint64) *Migration {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig *Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			m.dialect.rebind("INSERT INTO "+Table+" (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)"),
+			mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migrate: apply version %d (%s): %w", mig.Version, mig.Name, err)
+	}
+	logger.L().Info("Applied migration", zap.Uint64("version", mig.Version), zap.String("name", mig.Name))
+	return nil
+}
+
+func (m *Migrator) rollback(ctx context.Context, conn *sql.Conn, mig *Migration) error {
+	if mig.Down == "" {
+		return fmt.Errorf("%w: version %d (%s)", ErrNoDownScript, mig.Version, mig.Name)
+	}
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, m.dialect.rebind("DELETE FROM "+Table+" WHERE version = ?"), mig.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migrate: roll back version %d (%s): %w", mig.Version, mig.Name, err)
+	}
+	logger.L().Info("Rolled back migration", zap.Uint64("version", mig.Version), zap.String("name", mig.Name))
+	return nil
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(*sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
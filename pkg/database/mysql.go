@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+const defaultMySQLPort = 3306
+
+// MySQLDSN builds the data source name of MySQL, the times are parsed in the TimeZone.
+// SSLMode `disable` turns off TLS, the other values are passed as the tls parameter.
+func MySQLDSN(opts *Options) (string, error) {
+	port := opts.Port
+	if port == 0 {
+		port = defaultMySQLPort
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = opts.User
+	cfg.Passwd = opts.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(opts.Host, strconv.Itoa(port))
+	cfg.DBName = opts.Database
+	cfg.ParseTime = true
+	cfg.Params = map[string]string{"charset": "utf8mb4"}
+	if opts.TimeZone != "" {
+		loc, err := time.LoadLocation(opts.TimeZone)
+		if err != nil {
+			return "", fmt.Errorf("load time zone %q: %w", opts.TimeZone, err)
+		}
+		cfg.Loc = loc
+	}
+	if opts.SSLMode != "" && opts.SSLMode != "disable" {
+		cfg.Params["tls"] = opts.SSLMode
+	}
+	return cfg.FormatDSN(), nil
+}
+
+func mysqlDialector(opts *Options) (gorm.Dialector, error) {
+	dsn, err := MySQLDSN(opts)
+	if err != nil {
+		return nil, err
+	}
+	return gormmysql.Open(dsn), nil
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// resolverName is the name of the gorm plugin and callbacks of the replica routing.
+const resolverName = "siam:resolver"
+
+type primaryKey struct{}
+
+// WithPrimary returns a copy of the context which routes the reads to the primary database,
+// so that the caller reads its own writes which may not have been replicated yet.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// usePrimary reports whether the reads of the context must go to the primary database.
+func usePrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// Snapshot runs fn in a read-only transaction on a single database, a healthy replica unless the reads of
+// the context must stay on the primary, so that all of the reads of fn see the same state of the database
+// rather than those of the replicas the separate reads would be routed to.
+func Snapshot(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	db = db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	if r := resolverOf(db); r != nil && !usePrimary(ctx) {
+		if rep := r.pick(); rep != nil {
+			db.Statement.ConnPool = rep.db
+		}
+	}
+	return db.Transaction(fn, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// replica is a read-only copy of the primary database.
+type replica struct {
+	addr    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// resolver is a gorm plugin which routes the reads to the healthy replicas in round-robin.
+// The writes, the transactions, the locking reads and the reads of a WithPrimary context
+// stay on the primary, and so do all of the reads if none of the replicas is healthy.
+type resolver struct {
+	primary  gorm.ConnPool
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+var _ gorm.Plugin = (*resolver)(nil)
+
+// newResolver opens the replicas of the options, a replica which can not be connected
+// is ejected until the health check readmits it.
+func newResolver(opts *Options) (*resolver, error) {
+	log := opts.logger()
+	r := &resolver{}
+	for _, addr := range opts.Replicas {
+		ropts, err := replicaOptions(opts, addr)
+		if err != nil {
+			_ = r.close()
+			return nil, err
+		}
+		d, err := dialector(ropts)
+		if err != nil {
+			_ = r.close()
+			return nil, err
+		}
+		db, err := gorm.Open(d, &gorm.Config{TranslateError: true, DisableAutomaticPing: true})
+		if err != nil {
+			_ = r.close()
+			return nil, fmt.Errorf("open replica %s: %w", addr, err)
+		}
+		sqldb, err := db.DB()
+		if err != nil {
+			_ = r.close()
+			return nil, err
+		}
+		configurePool(sqldb, opts)
+
+		rep := &replica{addr: addr, db: sqldb}
+		if err := sqldb.Ping(); err != nil {
+			log.Warn("Failed to connect to database replica, it is ejected", zap.String("replica", addr), zap.Error(err))
+		} else {
+			rep.healthy.Store(true)
+		}
+		r.replicas = append(r.replicas, rep)
+	}
+	return r, nil
+}
+
+// replicaOptions returns the options of the replica at the `host[:port]` address.
+func replicaOptions(opts *Options, addr string) (*Options, error) {
+	ropts := *opts
+	ropts.Replicas = nil
+	ropts.Host, ropts.Port = addr, 0
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("replica %q has an invalid port", addr)
+		}
+		ropts.Host, ropts.Port = host, p
+	}
+	return &ropts, nil
+}
+
+// resolverOf returns the resolver plugin of the db, nil if it has no replicas.
+func resolverOf(db *gorm.DB) *resolver {
+	r, _ := db.Config.Plugins[resolverName].(*resolver)
+	return r
+}
+
+func (r *resolver) Name() string {
+	return resolverName
+}
+
+func (r *resolver) Initialize(db *gorm.DB) error {
+	r.primary = db.ConnPool
+	return errors.Join(
+		db.Callback().Query().Before("gorm:query").Register(resolverName, r.routeRead),
+		db.Callback().Row().Before("gorm:row").Register(resolverName, r.routeRead),
+		db.Callback().Create().Before("gorm:create").Register(resolverName, r.routeWrite),
+		db.Callback().Update().Before("gorm:update").Register(resolverName, r.routeWrite),
+		db.Callback().Delete().Before("gorm:delete").Register(resolverName, r.routeWrite),
+		db.Callback().Raw().Before("gorm:raw").Register(resolverName, r.routeWrite),
+	)
+}
+
+// routeRead sends the read to a healthy replica unless it must stay on the primary.
+func (r *resolver) routeRead(db *gorm.DB) {
+	stmt := db.Statement
+	if inTransaction(stmt) {
+		return
+	}
+	if _, locking := stmt.Clauses["FOR"]; locking || usePrimary(stmt.Context) {
+		stmt.ConnPool = r.primary
+		return
+	}
+	if rep := r.pick(); rep != nil {
+		stmt.ConnPool = rep.db
+		return
+	}
+	stmt.ConnPool = r.primary
+}
+
+// routeWrite sends the write to the primary, the statement may have been routed to a replica by a read before.
+func (r *resolver) routeWrite(db *gorm.DB) {
+	if !inTransaction(db.Statement) {
+		db.Statement.ConnPool = r.primary
+	}
+}
+
+// inTransaction reports whether the statement runs in a transaction, which is always on the primary.
+func inTransaction(stmt *gorm.Statement) bool {
+	_, ok := stmt.ConnPool.(gorm.TxCommitter)
+	return ok
+}
+
+// pick returns the next healthy replica in round-robin, nil if none of them is healthy.
+func (r *resolver) pick() *replica {
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := range n {
+		if rep := r.replicas[(start+i)%n]; rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
+
+func (r *resolver) close() error {
+	var errs []error
+	for _, rep := range r.replicas {
+		errs = append(errs, rep.db.Close())
+	}
+	return errors.Join(errs...)
+}
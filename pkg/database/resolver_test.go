@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// source is a table which tells the database a read is served by.
+type source struct {
+	Name string
+}
+
+// newTestDB opens a SQLite primary with the replicas, every database has a source row of its own name.
+func newTestDB(t *testing.T, replicas ...string) (*gorm.DB, *resolver) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := New(&Options{Driver: SQLite, Path: filepath.Join(dir, "primary.db")})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+	if err := db.Exec("CREATE TABLE sources (name TEXT)").Error; err != nil {
+		t.Fatalf("create sources: %v", err)
+	}
+	if err := db.Exec("INSERT INTO sources (name) VALUES ('primary')").Error; err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+
+	r := &resolver{}
+	for _, name := range replicas {
+		sqldb, err := sql.Open("sqlite", SQLiteDSN(&Options{Path: filepath.Join(dir, name+".db")}))
+		if err != nil {
+			t.Fatalf("open replica %s: %v", name, err)
+		}
+		if _, err := sqldb.Exec("CREATE TABLE sources (name TEXT)"); err != nil {
+			t.Fatalf("create sources of replica %s: %v", name, err)
+		}
+		if _, err := sqldb.Exec("INSERT INTO sources (name) VALUES (?)", name); err != nil {
+			t.Fatalf("insert source of replica %s: %v", name, err)
+		}
+		rep := &replica{addr: name, db: sqldb}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	if err := db.Use(r); err != nil {
+		t.Fatalf("use resolver: %v", err)
+	}
+	return db, r
+}
+
+// sourceOf returns the database which serves a read of the db.
+func sourceOf(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var s source
+	if err := db.Take(&s).Error; err != nil {
+		t.Fatalf("read source: %v", err)
+	}
+	return s.Name
+}
+
+func TestResolverRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		unhealthy []string
+		read      func(t *testing.T, db *gorm.DB) []string
+		want      []string
+	}{
+		{
+			name: "reads in round-robin",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				return []string{sourceOf(t, db), sourceOf(t, db), sourceOf(t, db), sourceOf(t, db)}
+			},
+			want: []string{"replica2", "replica1", "replica2", "replica1"},
+		},
+		{
+			name:      "unhealthy replicas are skipped",
+			unhealthy: []string{"replica1"},
+			read: func(t *testing.T, db *gorm.DB) []string {
+				return []string{sourceOf(t, db), sourceOf(t, db)}
+			},
+			want: []string{"replica2", "replica2"},
+		},
+		{
+			name:      "primary if none of the replicas is healthy",
+			unhealthy: []string{"replica1", "replica2"},
+			read: func(t *testing.T, db *gorm.DB) []string {
+				return []string{sourceOf(t, db)}
+			},
+			want: []string{"primary"},
+		},
+		{
+			name: "primary context",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				db = db.WithContext(WithPrimary(context.Background()))
+				return []string{sourceOf(t, db), sourceOf(t, db)}
+			},
+			want: []string{"primary", "primary"},
+		},
+		{
+			name: "locking read",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				return []string{sourceOf(t, db.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}))}
+			},
+			want: []string{"primary"},
+		},
+		{
+			name: "transaction",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				var sources []string
+				err := db.Transaction(func(tx *gorm.DB) error {
+					sources = append(sources, sourceOf(t, tx), sourceOf(t, tx))
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Transaction() error = %v", err)
+				}
+				return sources
+			},
+			want: []string{"primary", "primary"},
+		},
+		{
+			name: "snapshot on a single replica",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				var sources []string
+				err := Snapshot(context.Background(), db, func(tx *gorm.DB) error {
+					sources = append(sources, sourceOf(t, tx), sourceOf(t, tx), sourceOf(t, tx))
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Snapshot() error = %v", err)
+				}
+				return sources
+			},
+			want: []string{"replica2", "replica2", "replica2"},
+		},
+		{
+			name: "snapshot of a primary context",
+			read: func(t *testing.T, db *gorm.DB) []string {
+				var sources []string
+				err := Snapshot(WithPrimary(context.Background()), db, func(tx *gorm.DB) error {
+					sources = append(sources, sourceOf(t, tx), sourceOf(t, tx))
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("Snapshot() error = %v", err)
+				}
+				return sources
+			},
+			want: []string{"primary", "primary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := newTestDB(t, "replica1", "replica2")
+			for _, rep := range r.replicas {
+				rep.healthy.Store(!slices.Contains(tt.unhealthy, rep.addr))
+			}
+			if got := tt.read(t, db); !slices.Equal(got, tt.want) {
+				t.Errorf("sources = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverWrites(t *testing.T) {
+	db, r := newTestDB(t, "replica1")
+	if err := db.Create(&source{Name: "created"}).Error; err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := db.Exec("INSERT INTO sources (name) VALUES ('executed')").Error; err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if err := db.Where("name = ?", "created").Updates(&source{Name: "updated"}).Error; err != nil {
+		t.Fatalf("Updates() error = %v", err)
+	}
+
+	var names []string
+	primary := db.WithContext(WithPrimary(context.Background()))
+	if err := primary.Model(&source{}).Order("name").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("Pluck() error = %v", err)
+	}
+	if want := []string{"executed", "primary", "updated"}; !slices.Equal(names, want) {
+		t.Errorf("names of the primary = %v, want %v", names, want)
+	}
+	var n int
+	if err := r.replicas[0].db.QueryRow("SELECT COUNT(*) FROM sources").Scan(&n); err != nil || n != 1 {
+		t.Errorf("rows of the replica = %d, %v, want 1, nil", n, err)
+	}
+}
+
+func TestSnapshotWithoutReplicas(t *testing.T) {
+	db, err := New(&Options{Driver: SQLite, Path: filepath.Join(t.TempDir(), "primary.db")})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { _ = Close(db) })
+
+	var n int
+	err = Snapshot(context.Background(), db, func(tx *gorm.DB) error {
+		return tx.Raw("SELECT 1").Scan(&n).Error
+	})
+	if err != nil || n != 1 {
+		t.Errorf("Snapshot() = %d, %v, want 1, nil", n, err)
+	}
+}
+
+func TestReplicaOptions(t *testing.T) {
+	tests := []struct {
+		addr     string
+		wantHost string
+		wantPort int
+		wantErr  bool
+	}{
+		{addr: "db-replica", wantHost: "db-replica"},
+		{addr: "db-replica:5433", wantHost: "db-replica", wantPort: 5433},
+		{addr: "[::1]:3307", wantHost: "::1", wantPort: 3307},
+		{addr: "db-replica:port", wantErr: true},
+	}
+	for _, tt := range tests {
+		primary := &Options{Driver: Postgres, Host: "db", Port: 5432, Replicas: []string{tt.addr}}
+		got, err := replicaOptions(primary, tt.addr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("replicaOptions(%q) error = %v, want error %v", tt.addr, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.Host != tt.wantHost || got.Port != tt.wantPort || got.Replicas != nil {
+			t.Errorf("replicaOptions(%q) = host %q, port %d, replicas %v, want host %q, port %d, no replicas",
+				tt.addr, got.Host, got.Port, got.Replicas, tt.wantHost, tt.wantPort)
+		}
+		if primary.Host != "db" || primary.Port != 5432 {
+			t.Errorf("replicaOptions(%q) modified the options of the primary", tt.addr)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package database
+
+import (
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// SQLiteDSN builds the data source name of the SQLite file, it waits for the locks held by
+// the other connections instead of failing immediately, and enables the WAL journal and the foreign keys.
+func SQLiteDSN(opts *Options) string {
+	return opts.Path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)"
+}
+
+func sqliteDialector(opts *Options) gorm.Dialector {
+	return sqlite.Open(SQLiteDSN(opts))
+}
@@ -0,0 +1,182 @@
+// Package policy defines the policy document of siam and its schema validation.
+//
+// A policy document is a list of statements, each statement allows or denies
+// a set of actions on a set of resources, optionally under some conditions:
+//
+//	{
+//	  "version": "2025-10-01",
+//	  "statements": [{
+//	    "id": "AllowReadArticles",
+//	    "effect": "allow",
+//	    "actions": ["article:get", "article:list"],
+//	    "resources": ["article/*"],
+//	    "conditions": {"IpAddress": {"sourceIp": ["10.0.0.0/8"]}}
+//	  }]
+//	}
+package policy
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// Version20251001 is the current and the only supported policy document version.
+const Version20251001 = "2025-10-01"
+
+// Effect is the effect of a statement when it matches a request.
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Condition operators supported by the statements.
+const (
+	// StringEquals matches if the context value equals any of the values.
+	StringEquals = "StringEquals"
+	// StringNotEquals matches if the context value equals none of the values.
+	StringNotEquals = "StringNotEquals"
+	// StringLike matches if the context value matches any of the glob patterns.
+	StringLike = "StringLike"
+	// IpAddress matches if the context value is an IP inside any of the CIDR blocks or IPs.
+	IpAddress = "IpAddress"
+	// NotIpAddress matches if the context value is an IP outside all of the CIDR blocks or IPs.
+	NotIpAddress = "NotIpAddress"
+	// DateGreaterThan matches if the context value is a time after the RFC 3339 value.
+	DateGreaterThan = "DateGreaterThan"
+	// DateLessThan matches if the context value is a time before the RFC 3339 value.
+	DateLessThan = "DateLessThan"
+)
+
+// Conditions maps an operator to the context keys and their expected values.
+// All of the operators and keys must match for the statement to apply.
+type Conditions map[string]map[string][]string
+
+// Statement is a single permission rule of a policy document.
+type Statement struct {
+	// ID identifies the statement inside the document, it is reported when the statement matches.
+	ID         string     `json:"id,omitempty"`
+	Effect     Effect     `json:"effect"`
+	Actions    []string   `json:"actions"`
+	Resources  []string   `json:"resources"`
+	Conditions Conditions `json:"conditions,omitempty"`
+}
+
+// Document is a versioned list of statements.
+type Document struct {
+	Version    string      `json:"version"`
+	Statements []Statement `json:"statements"`
+}
+
+// Validate checks the document against the schema and returns all of the violations as an Aggregate
+// of *serrors.FieldViolation, the fields are the JSON paths in the document like `statements[0].effect`.
+// It returns nil if the document is valid.
+func (d *Document) Validate() error {
+	var errs []error
+	if d.Version != Version20251001 {
+		errs = append(errs, serrors.NewFieldViolation("version", "version %q is not supported", d.Version))
+	}
+	if len(d.Statements) == 0 {
+		errs = append(errs, serrors.NewFieldViolation("statements", "statements must not be empty"))
+	}
+
+	ids := serrors.NewString()
+	for i := range d.Statements {
+		st := &d.Statements[i]
+		path := fmt.Sprintf("statements[%d]", i)
+		if st.ID != "" {
+			if ids.Has(st.ID) {
+				errs = append(errs, serrors.NewFieldViolation(path+".id", "duplicated id %q", st.ID))
+			}
+			ids.Insert(st.ID)
+		}
+		errs = append(errs, st.validate(path)...)
+	}
+
+	return serrors.NewAggregate(errs)
+}
+
+// validate returns the violations of the statement at the path of the document.
+func (st *Statement) validate(path string) []error {
+	var errs []error
+	if st.Effect != Allow && st.Effect != Deny {
+		errs = append(errs, serrors.NewFieldViolation(path+".effect", "effect %q must be %q or %q",
+			st.Effect, Allow, Deny))
+	}
+	if len(st.Actions) == 0 {
+		errs = append(errs, serrors.NewFieldViolation(path+".actions", "actions must not be empty"))
+	}
+	for j, action := range st.Actions {
+		if action == "" {
+			errs = append(errs, serrors.NewFieldViolation(fmt.Sprintf("%s.actions[%d]", path, j),
+				"action must not be empty"))
+		}
+	}
+	if len(st.Resources) == 0 {
+		errs = append(errs, serrors.NewFieldViolation(path+".resources", "resources must not be empty"))
+	}
+	for j, resource := range st.Resources {
+		if resource == "" {
+			errs = append(errs, serrors.NewFieldViolation(fmt.Sprintf("%s.resources[%d]", path, j),
+				"resource must not be empty"))
+		}
+	}
+	for _, op := range sortedKeys(st.Conditions) {
+		kvs := st.Conditions[op]
+		for _, key := range sortedKeys(kvs) {
+			values := kvs[key]
+			field := fmt.Sprintf("%s.conditions.%s.%s", path, op, key)
+			if key == "" {
+				errs = append(errs, serrors.NewFieldViolation(fmt.Sprintf("%s.conditions.%s", path, op),
+					"key must not be empty"))
+			}
+			if len(values) == 0 {
+				errs = append(errs, serrors.NewFieldViolation(field, "values must not be empty"))
+			}
+			for _, v := range values {
+				if err := validateConditionValue(op, v); err != nil {
+					errs = append(errs, serrors.NewFieldViolation(field, "%v", err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validateConditionValue checks if the value is well-formed for the operator.
+func validateConditionValue(op, value string) error {
+	switch op {
+	case StringEquals, StringNotEquals, StringLike:
+		return nil
+	case IpAddress, NotIpAddress:
+		if _, _, err := net.ParseCIDR(value); err == nil {
+			return nil
+		}
+		if net.ParseIP(value) == nil {
+			return fmt.Errorf("%q is neither an IP nor a CIDR block", value)
+		}
+		return nil
+	case DateGreaterThan, DateLessThan:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("%q is not a RFC 3339 time", value)
+		}
+		return nil
+	default:
+		return fmt.Errorf("operator %q is not supported", op)
+	}
+}
+
+// sortedKeys returns the keys of the map in order, so that the violations are reported deterministically.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+func validStatement() Statement {
+	return Statement{
+		ID:        "AllowRead",
+		Effect:    Allow,
+		Actions:   []string{"article:get"},
+		Resources: []string{"article/*"},
+	}
+}
+
+func TestDocumentValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *Document)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			modify: func(d *Document) {},
+		},
+		{
+			name: "valid conditions",
+			modify: func(d *Document) {
+				d.Statements[0].Conditions = Conditions{
+					StringLike:      {"department": {"s*"}},
+					IpAddress:       {"sourceIp": {"10.0.0.0/8", "192.168.1.1"}},
+					NotIpAddress:    {"sourceIp": {"::1"}},
+					DateGreaterThan: {"currentTime": {"2025-10-01T00:00:00Z"}},
+				}
+			},
+		},
+		{
+			name:   "unsupported version",
+			modify: func(d *Document) { d.Version = "2012-10-17" },
+			want:   []string{"version"},
+		},
+		{
+			name:   "no statements",
+			modify: func(d *Document) { d.Statements = nil },
+			want:   []string{"statements"},
+		},
+		{
+			name: "duplicated ids",
+			modify: func(d *Document) {
+				d.Statements = append(d.Statements, validStatement(), Statement{
+					Effect: Deny, Actions: []string{"*"}, Resources: []string{"*"},
+				})
+			},
+			want: []string{"statements[1].id"},
+		},
+		{
+			name: "invalid statement",
+			modify: func(d *Document) {
+				d.Statements[0] = Statement{Effect: "Allow", Actions: []string{""}, Resources: []string{}}
+			},
+			want: []string{"statements[0].effect", "statements[0].actions[0]", "statements[0].resources"},
+		},
+		{
+			name: "invalid conditions",
+			modify: func(d *Document) {
+				d.Statements[0].Conditions = Conditions{
+					StringEquals: {"": {"a"}, "team": {}},
+					IpAddress:    {"sourceIp": {"10.0.0.0/33"}},
+					DateLessThan: {"currentTime": {"2025-10-01"}},
+					"Bool":       {"secure": {"true"}},
+				}
+			},
+			want: []string{
+				"statements[0].conditions.Bool.secure",
+				"statements[0].conditions.DateLessThan.currentTime",
+				"statements[0].conditions.IpAddress.sourceIp",
+				"statements[0].conditions.StringEquals",
+				"statements[0].conditions.StringEquals.team",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Document{Version: Version20251001, Statements: []Statement{validStatement()}}
+			tt.modify(d)
+			var got []string
+			for _, v := range serrors.DetailsOf[*serrors.FieldViolation](d.Validate()) {
+				got = append(got, v.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
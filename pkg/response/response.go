@@ -0,0 +1,118 @@
+// Package response writes the JSON responses of the HTTP servers. The error responses are driven by the codes
+// of package serrors: serrors.ParseCoder resolves the code of any error, and the HTTP status, the message and
+// the reference of the response are the ones registered with the code. The message is translated into the
+// locale of the Accept-Language header of the request, see serrors.Localize.
+//
+// Only the errors are enveloped. The successful responses are the bare objects of the API with the status
+// 200, so the clients decode them into the API types directly, and tell them apart from the errors by the
+// status. Their trace is in the traceparent header of the response instead of the trace_id of the envelope.
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// ErrorResponse is the envelope of the error responses.
+type ErrorResponse struct {
+	// Code is the business error code.
+	Code int `json:"code"`
+
+	// Message is the external message of the code, it is suitable to be exposed to the clients.
+	Message string `json:"message"`
+
+	// Reference is the document which may be useful to solve the error, it is omitted if it does not exist.
+	Reference string `json:"reference,omitempty"`
+
+	// TraceID is the trace of the request, it helps to find the logs of the error.
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Details are the additional information of the error, which are the serrors.Detail of the error,
+	// like the field violations of an invalid request, and the DebugInfo in the debug mode.
+	Details []any `json:"details,omitempty"`
+}
+
+// RetryAfter returns the duration after which the request can be retried, which is the first
+// serrors.RetryInfo of the details. It is 0 if there is none.
+func (r *ErrorResponse) RetryAfter() time.Duration {
+	for _, d := range r.Details {
+		if info, ok := d.(*serrors.RetryInfo); ok {
+			return info.RetryAfter
+		}
+	}
+	return 0
+}
+
+// SetRetryAfter sets the Retry-After header of the response if the request can be retried, see RetryAfter.
+func (r *ErrorResponse) SetRetryAfter(h http.Header) {
+	if after := r.RetryAfter(); after > 0 {
+		h.Set("Retry-After", strconv.FormatInt(int64((after+time.Second-1)/time.Second), 10))
+	}
+}
+
+// DebugInfo is the detail of an error in the debug mode, it must never be exposed in production.
+type DebugInfo struct {
+	// Stack is the errors of the chain with their callers, which is formatted by `%#+v`.
+	Stack string `json:"stack"`
+}
+
+// NewError returns the HTTP status and the envelope of the error of the request r.
+// The messages of the 5xx errors are never taken from the error itself, since they may reveal the internals
+// of the server. The details of the error are collected by serrors.Details, and the DebugInfo of the error
+// is added to them only if debug is true.
+func NewError(r *http.Request, err error, debug bool) (int, *ErrorResponse) {
+	coder := serrors.ParseCoder(err)
+	status := coder.HTTPStatus()
+
+	message, locale, ok := serrors.Localize(coder, r.Header.Get("Accept-Language"))
+	if !ok {
+		logger.L().Warn("Error message is not translated, falling back to the default locale",
+			zap.Int("code", coder.Code()), zap.String("locale", locale))
+	}
+	if message == "" {
+		if status >= http.StatusInternalServerError {
+			message = http.StatusText(status)
+		} else {
+			message = err.Error()
+		}
+	}
+
+	resp := &ErrorResponse{
+		Code:      coder.Code(),
+		Message:   message,
+		Reference: coder.Reference(),
+		TraceID:   logger.TraceID(r.Context()),
+	}
+	for _, d := range serrors.Details(err) {
+		resp.Details = append(resp.Details, d)
+	}
+	if debug {
+		resp.Details = append(resp.Details, DebugInfo{Stack: fmt.Sprintf("%#+v", err)})
+	}
+	return status, resp
+}
+
+// WriteError writes the error response of the error of the request r, see NewError.
+func WriteError(w http.ResponseWriter, r *http.Request, err error, debug bool) {
+	status, resp := NewError(r, err, debug)
+	resp.SetRetryAfter(w.Header())
+	write(w, status, resp)
+}
+
+func write(w http.ResponseWriter, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		status, data = http.StatusInternalServerError, []byte(`{"code":1,"message":"Internal Server Error"}`)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
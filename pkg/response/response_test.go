@@ -0,0 +1,174 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+func TestMain(m *testing.M) {
+	// the logger creates its directory in the working directory, keep it out of the source tree
+	dir, err := os.MkdirTemp("", "response")
+	if err != nil {
+		panic(err)
+	}
+	wd, _ := os.Getwd()
+	_ = os.Chdir(dir)
+	logger.Init(context.Background(), nil, logger.WithLevel("error"))
+	_ = os.Chdir(wd)
+
+	exit := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(exit)
+}
+
+// The codes of the tests, they are out of the ranges of the services.
+const (
+	errWidgetNotFound = 990001
+	errWidgetInvalid  = 990002
+	errWidgetStore    = 990003
+	errWidgetExists   = 990004
+)
+
+func init() {
+	serrors.Register(serrors.Code{C: errWidgetNotFound, HTTP: http.StatusNotFound, Ext: "Widget not found."})
+	serrors.Register(serrors.Code{C: errWidgetInvalid, HTTP: http.StatusBadRequest})
+	serrors.Register(serrors.Code{C: errWidgetStore, HTTP: http.StatusServiceUnavailable})
+	serrors.Register(serrors.Code{C: errWidgetExists, HTTP: http.StatusConflict, Ext: "Widget already exists."})
+	serrors.MustRegisterMessages("fr", map[int]string{errWidgetNotFound: "Widget introuvable."})
+}
+
+func TestNewError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		acceptLanguage string
+		wantStatus     int
+		wantCode       int
+		wantMessage    string
+	}{
+		{
+			name:        "registered message",
+			err:         serrors.WithCode(errWidgetNotFound, "widget 42 not found"),
+			wantStatus:  http.StatusNotFound,
+			wantCode:    errWidgetNotFound,
+			wantMessage: "Widget not found.",
+		},
+		{
+			name:           "translated message",
+			err:            serrors.WithCode(errWidgetNotFound, "widget 42 not found"),
+			acceptLanguage: "fr-FR,fr;q=0.9,en;q=0.8",
+			wantStatus:     http.StatusNotFound,
+			wantCode:       errWidgetNotFound,
+			wantMessage:    "Widget introuvable.",
+		},
+		{
+			name:           "untranslated message",
+			err:            serrors.WithCode(errWidgetExists, "widget 42 already exists"),
+			acceptLanguage: "fr",
+			wantStatus:     http.StatusConflict,
+			wantCode:       errWidgetExists,
+			wantMessage:    "Widget already exists.",
+		},
+		{
+			name:        "message of a client error",
+			err:         serrors.WithCode(errWidgetInvalid, "widget name is empty"),
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    errWidgetInvalid,
+			wantMessage: "widget name is empty",
+		},
+		{
+			name:        "message of a server error is hidden",
+			err:         serrors.WithCode(errWidgetStore, "dial tcp 10.0.0.1:5432: refused"),
+			wantStatus:  http.StatusServiceUnavailable,
+			wantCode:    errWidgetStore,
+			wantMessage: http.StatusText(http.StatusServiceUnavailable),
+		},
+		{
+			name:        "error without a code",
+			err:         errors.New("password authentication failed"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    1,
+			wantMessage: "An internal server error occurred",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/widgets/42", nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			status, resp := NewError(r, tt.err, false)
+			if status != tt.wantStatus || resp.Code != tt.wantCode || resp.Message != tt.wantMessage {
+				t.Errorf("NewError() = %d, %d %q, want %d, %d %q", status, resp.Code, resp.Message,
+					tt.wantStatus, tt.wantCode, tt.wantMessage)
+			}
+			if len(resp.Details) != 0 {
+				t.Errorf("NewError() details = %v, want none", resp.Details)
+			}
+		})
+	}
+}
+
+func TestNewErrorDetails(t *testing.T) {
+	violation := serrors.NewFieldViolation("name", "must not be empty")
+	err := serrors.WithDetails(serrors.WithCode(errWidgetInvalid, "invalid widget"), violation)
+	r := httptest.NewRequest(http.MethodPost, "/widgets", nil)
+
+	_, resp := NewError(r, err, false)
+	if len(resp.Details) != 1 || resp.Details[0] != violation {
+		t.Errorf("NewError() details = %v, want the violation", resp.Details)
+	}
+
+	// the stack is only exposed in the debug mode
+	_, resp = NewError(r, err, true)
+	if len(resp.Details) != 2 {
+		t.Fatalf("NewError() details in the debug mode = %v, want the violation and the debug info", resp.Details)
+	}
+	if info, ok := resp.Details[1].(DebugInfo); !ok || !strings.Contains(info.Stack, "invalid widget") {
+		t.Errorf("NewError() debug info = %v, want the stack of the error", resp.Details[1])
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name           string
+		details        []serrors.Detail
+		wantRetryAfter string
+	}{
+		{"no retry info", nil, ""},
+		{"seconds are rounded up", []serrors.Detail{&serrors.RetryInfo{RetryAfter: 1500 * time.Millisecond}}, "2"},
+		{"first retry info", []serrors.Detail{
+			&serrors.RetryInfo{RetryAfter: 3 * time.Second},
+			&serrors.RetryInfo{RetryAfter: time.Minute},
+		}, "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := serrors.WithDetails(serrors.WithCode(errWidgetStore, "widget store is down"), tt.details...)
+			w := httptest.NewRecorder()
+			WriteError(w, httptest.NewRequest(http.MethodGet, "/widgets", nil), err, false)
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("WriteError() status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("WriteError() Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+				t.Errorf("WriteError() Content-Type = %q, want JSON", got)
+			}
+			if body := w.Body.String(); strings.Contains(body, "widget store is down") {
+				t.Errorf("WriteError() body = %s, want no internal message", body)
+			}
+		})
+	}
+}
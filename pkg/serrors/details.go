@@ -0,0 +1,201 @@
+package serrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Detail is a structured detail of an error, like a FieldViolation. The details are exposed to the clients
+// in the error responses, so they must never carry the internals of the server.
+type Detail interface {
+	// DetailType returns the type of the detail, which is the `@type` field of the detail in JSON.
+	DetailType() string
+}
+
+// FieldViolation describes a field of a request which is invalid. It is also an error, so the violations
+// of a request are collected into an Aggregate, see Details.
+type FieldViolation struct {
+	// Field is the path of the field in the request, like `document.statements[0].effect`.
+	Field string `json:"field"`
+	// Description tells why the field is invalid.
+	Description string `json:"description"`
+}
+
+// NewFieldViolation returns the violation of the field with the formatted description.
+func NewFieldViolation(field, format string, args ...any) *FieldViolation {
+	return &FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)}
+}
+
+func (v *FieldViolation) Error() string {
+	if v.Field == "" {
+		return v.Description
+	}
+	return v.Field + ": " + v.Description
+}
+
+func (v *FieldViolation) DetailType() string { return "FieldViolation" }
+
+func (v *FieldViolation) MarshalJSON() ([]byte, error) {
+	type plain FieldViolation
+	return marshalDetail(v, (*plain)(v))
+}
+
+// RetryInfo tells the clients when the failed request can be retried.
+type RetryInfo struct {
+	RetryAfter time.Duration
+}
+
+func (r *RetryInfo) DetailType() string { return "RetryInfo" }
+
+func (r *RetryInfo) MarshalJSON() ([]byte, error) {
+	return marshalDetail(r, struct {
+		RetryAfterSeconds int64 `json:"retryAfterSeconds"`
+	}{int64(r.RetryAfter.Round(time.Second) / time.Second)})
+}
+
+// ResourceInfo describes the resource an error is about.
+type ResourceInfo struct {
+	// ResourceType is the kind of the resource in the API paths, like `users`.
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	// Owner is the owner of the resource, like the user of a secret, it is empty if not owned.
+	Owner       string `json:"owner,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (r *ResourceInfo) DetailType() string { return "ResourceInfo" }
+
+func (r *ResourceInfo) MarshalJSON() ([]byte, error) {
+	type plain ResourceInfo
+	return marshalDetail(r, (*plain)(r))
+}
+
+// Metadata is the arbitrary key value pairs of an error.
+type Metadata map[string]string
+
+func (m Metadata) DetailType() string { return "Metadata" }
+
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	return marshalDetail(m, struct {
+		Metadata map[string]string `json:"metadata"`
+	}{m})
+}
+
+// marshalDetail marshals the fields of the JSON object v after the `@type` of the detail d.
+func marshalDetail(d Detail, v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	typ, err := json.Marshal(d.DetailType())
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 2 {
+		return fmt.Appendf(nil, `{"@type":%s}`, typ), nil
+	}
+	return fmt.Appendf(nil, `{"@type":%s,%s`, typ, data[1:]), nil
+}
+
+// ParseDetail parses the JSON of a detail of a known type, which is marshaled by the detail itself.
+// It returns an error if the type is unknown.
+func ParseDetail(data []byte) (Detail, error) {
+	var header struct {
+		Type string `json:"@type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	switch header.Type {
+	case "FieldViolation":
+		v := &FieldViolation{}
+		return v, json.Unmarshal(data, v)
+	case "RetryInfo":
+		var r struct {
+			RetryAfterSeconds int64 `json:"retryAfterSeconds"`
+		}
+		err := json.Unmarshal(data, &r)
+		return &RetryInfo{RetryAfter: time.Duration(r.RetryAfterSeconds) * time.Second}, err
+	case "ResourceInfo":
+		r := &ResourceInfo{}
+		return r, json.Unmarshal(data, r)
+	case "Metadata":
+		var m struct {
+			Metadata Metadata `json:"metadata"`
+		}
+		err := json.Unmarshal(data, &m)
+		return m.Metadata, err
+	default:
+		return nil, fmt.Errorf("unknown detail type %q", header.Type)
+	}
+}
+
+// WithDetails attaches the details to the coded error err. If err is not a coded error, it is wrapped
+// into one of the code of its chain, which is the unknown code if there is none.
+// If err is nil, WithDetails returns nil.
+func WithDetails(err error, details ...Detail) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*withCode); ok {
+		return &withCode{
+			err:     e.err,
+			code:    e.code,
+			cause:   e.cause,
+			stack:   e.stack,
+			details: append(slices.Clip(e.details), details...),
+		}
+	}
+
+	code := unknownCoder.Code()
+	var wc *withCode
+	if errors.As(err, &wc) {
+		code = wc.code
+	}
+	return &withCode{
+		err:     errors.New(err.Error()),
+		code:    code,
+		cause:   err,
+		stack:   callers(),
+		details: details,
+	}
+}
+
+// Details returns the details of all of the errors in the chain of err, the outer ones first.
+// The errors of the Aggregates in the chain are included, and so are the errors which are details themselves
+// like FieldViolation, so that an Aggregate of the violations of a request is reported as a whole:
+//
+//	err := serrors.WrapC(serrors.NewAggregate(violations), code.ErrValidation, "invalid request")
+//	serrors.Details(err) // all of the violations
+func Details(err error) []Detail {
+	var details []Detail
+	for err != nil {
+		switch e := err.(type) {
+		case *withCode:
+			details = append(details, e.details...)
+		case Aggregate:
+			for _, err := range e.Errors() {
+				details = append(details, Details(err)...)
+			}
+			return details
+		case Detail:
+			details = append(details, e)
+		}
+		err = errors.Unwrap(err)
+	}
+	return details
+}
+
+// DetailsOf returns the details of the type T in the chain of err, like DetailsOf[*FieldViolation](err).
+func DetailsOf[T Detail](err error) []T {
+	var out []T
+	for _, d := range Details(err) {
+		if t, ok := d.(T); ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package serrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDetailJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail Detail
+		want   string
+	}{
+		{
+			"field violation",
+			NewFieldViolation("document.statements[0].effect", "must be %q or %q", "allow", "deny"),
+			`{"@type":"FieldViolation","field":"document.statements[0].effect",` +
+				`"description":"must be \"allow\" or \"deny\""}`,
+		},
+		{
+			"retry info",
+			&RetryInfo{RetryAfter: 1500 * time.Millisecond},
+			`{"@type":"RetryInfo","retryAfterSeconds":2}`,
+		},
+		{
+			"resource info",
+			&ResourceInfo{ResourceType: "secrets", ResourceName: "AKALICE", Owner: "alice"},
+			`{"@type":"ResourceInfo","resourceType":"secrets","resourceName":"AKALICE","owner":"alice"}`,
+		},
+		{"metadata", Metadata{"tenant": "acme"}, `{"@type":"Metadata","metadata":{"tenant":"acme"}}`},
+		{"empty metadata", Metadata{}, `{"@type":"Metadata","metadata":{}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.detail)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+
+			got, err := ParseDetail(data)
+			if err != nil {
+				t.Fatalf("ParseDetail() error = %v", err)
+			}
+			want := tt.detail
+			if r, ok := want.(*RetryInfo); ok {
+				// the retry is after whole seconds
+				want = &RetryInfo{RetryAfter: r.RetryAfter.Round(time.Second)}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseDetail() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseDetailErrors(t *testing.T) {
+	for _, data := range []string{`{"@type":"DebugInfo","stack":""}`, `{"field":"name"}`, `[]`, `{`} {
+		if d, err := ParseDetail([]byte(data)); err == nil {
+			t.Errorf("ParseDetail(%s) = %#v, want an error", data, d)
+		}
+	}
+}
+
+func TestDetails(t *testing.T) {
+	const errWidget = 990401
+	Register(Code{C: errWidget, HTTP: 400})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(codes, errWidget)
+	})
+
+	name := NewFieldViolation("name", "must not be empty")
+	color := NewFieldViolation("color", "must be red or blue")
+	resource := &ResourceInfo{ResourceType: "widgets", ResourceName: "w1"}
+	retry := &RetryInfo{RetryAfter: time.Second}
+
+	tests := []struct {
+		name string
+		err  error
+		want []Detail
+	}{
+		{"nil error", nil, nil},
+		{"no details", WithCode(errWidget, "invalid widget"), nil},
+		{"details of a coded error", WithDetails(WithCode(errWidget, "invalid widget"), resource), []Detail{resource}},
+		{
+			"details are appended",
+			WithDetails(WithDetails(WithCode(errWidget, "invalid widget"), resource), retry),
+			[]Detail{resource, retry},
+		},
+		{
+			"outer details first",
+			WithDetails(WrapC(WithDetails(WithCode(errWidget, "invalid widget"), resource), errWidget, "create"), retry),
+			[]Detail{retry, resource},
+		},
+		{
+			"violations of an aggregate",
+			WrapC(NewAggregate([]error{name, color}), errWidget, "invalid widget"),
+			[]Detail{name, color},
+		},
+		{"violation as the error", fmt.Errorf("bind: %w", name), []Detail{name}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Details(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Details() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	err := WithDetails(WrapC(NewAggregate([]error{name, color}), errWidget, "invalid widget"), resource)
+	if got := DetailsOf[*FieldViolation](err); !reflect.DeepEqual(got, []*FieldViolation{name, color}) {
+		t.Errorf("DetailsOf[*FieldViolation]() = %v, want the violations", got)
+	}
+	if got := DetailsOf[*RetryInfo](err); got != nil {
+		t.Errorf("DetailsOf[*RetryInfo]() = %v, want none", got)
+	}
+}
+
+func TestWithDetails(t *testing.T) {
+	const errWidget = 990402
+	Register(Code{C: errWidget, HTTP: 404})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(codes, errWidget)
+	})
+	resource := &ResourceInfo{ResourceType: "widgets", ResourceName: "w1"}
+
+	if err := WithDetails(nil, resource); err != nil {
+		t.Errorf("WithDetails(nil) = %v, want nil", err)
+	}
+
+	// the details do not change the code and the message of the error
+	coded := WithCode(errWidget, "widget w1 not found")
+	err := WithDetails(coded, resource)
+	if !IsCode(err, errWidget) || err.Error() != coded.Error() {
+		t.Errorf("WithDetails() = %v, want the code %d and the message %q", err, errWidget, coded.Error())
+	}
+	if got := Details(coded); got != nil {
+		t.Errorf("Details() of the original error = %v, want none", got)
+	}
+
+	// an error which is not coded is wrapped into the code of its chain, or the unknown code
+	wrapped := fmt.Errorf("get widget: %w", coded)
+	err = WithDetails(wrapped, resource)
+	if !IsCode(err, errWidget) || !errors.Is(err, coded) || err.Error() != wrapped.Error() {
+		t.Errorf("WithDetails() of a wrapped error = %v, want the code %d", err, errWidget)
+	}
+	err = WithDetails(errors.New("widget store is down"), resource)
+	if got := ParseCoder(err); got.Code() != unknownCoder.Code() {
+		t.Errorf("ParseCoder() of the details of a plain error = %d, want %d", got.Code(), unknownCoder.Code())
+	}
+	if got := Details(err); !reflect.DeepEqual(got, []Detail{resource}) {
+		t.Errorf("Details() of a plain error = %v, want the resource", got)
+	}
+}
@@ -301,6 +301,9 @@ type withCode struct {
 	code  int
 	cause error
 	*stack
+
+	// details are the structured details of the error, see WithDetails.
+	details []Detail
 }
 
 func WithCode(code int, format string) error {
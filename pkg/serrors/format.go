@@ -13,6 +13,7 @@ type formatInfo struct {
 	message string
 	err     string
 	stack   *stack
+	details []Detail
 }
 
 // Format implements fmt.Formatter. https://golang.org/pkg/fmt/#hdr-Printing
@@ -40,7 +41,8 @@ type formatInfo struct {
 // error for internal read A - #1 [/home/lk/workspace/golang/src/github.com/marmotedu/iam/main.go:35 (main.newErrorB)]
 // (#100104) Validation failed
 //
-//	%#v:   [{"error":"error for internal read B"}]
+//	%#v:   [{"error":"error for internal read B"}], the details of the error are the "details" field if any,
+//	  ┊   like [{"details":[{"@type":"FieldViolation","field":"name","description":"is required"}],"error":...}]
 //	%#-v:  [{"caller":"#0 /home/lk/workspace/golang/src/github.com/marmotedu/iam/main.go:12 (main.main)","error":"error
 //
 // for internal read B","message":"(#100102) Internal Server Error"}] 	%#+v:  [{"caller":"#0
@@ -127,6 +129,9 @@ func format(k int, jsonData []map[string]interface{}, str *bytes.Buffer, finfo *
 		} else {
 			data["error"] = finfo.message
 		}
+		if len(finfo.details) > 0 {
+			data["details"] = finfo.details
+		}
 		jsonData = append(jsonData, data)
 	} else {
 		if flagDetail || flagTrace {
@@ -204,6 +209,7 @@ func buildFormatInfo(e error) *formatInfo {
 			message: extMsg,
 			err:     err.err.Error(),
 			stack:   err.stack,
+			details: err.details,
 		}
 	default:
 		finfo = &formatInfo{
@@ -0,0 +1,115 @@
+package serrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale of the external messages of the registered codes.
+const DefaultLocale = "en"
+
+var (
+	// messages are the external messages of the codes by their locales, except the ones of DefaultLocale.
+	messages = map[string]map[int]string{}
+	// locales are the supported locales, the first one is DefaultLocale.
+	locales = []language.Tag{language.Make(DefaultLocale)}
+	matcher = language.NewMatcher(locales)
+)
+
+// RegisterMessages registers the external messages of the codes in the locale, like `zh-CN`.
+// The messages replace the registered ones of the same codes in the locale.
+func RegisterMessages(locale string, msgs map[int]string) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("invalid locale %q: %w", locale, err)
+	}
+	if tag == locales[0] {
+		return fmt.Errorf("messages of the default locale %q are the external messages of the codes", locale)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := tag.String()
+	if _, ok := messages[key]; !ok {
+		messages[key] = map[int]string{}
+		locales = append(locales, tag)
+		matcher = language.NewMatcher(locales)
+	}
+	for code, msg := range msgs {
+		messages[key][code] = msg
+	}
+	return nil
+}
+
+// MustRegisterMessages is like RegisterMessages but panics if the locale is invalid.
+func MustRegisterMessages(locale string, msgs map[int]string) {
+	if err := RegisterMessages(locale, msgs); err != nil {
+		panic(err)
+	}
+}
+
+// Catalog is a file of the external messages of the codes in a locale, which is YAML or JSON:
+//
+//	locale: zh-CN
+//	messages:
+//	  110001: 用户不存在。
+type Catalog struct {
+	Locale   string         `json:"locale"   yaml:"locale"`
+	Messages map[int]string `json:"messages" yaml:"messages"`
+}
+
+// LoadCatalog registers the messages of the catalog file at path, see Catalog.
+// The file is JSON if its extension is .json, otherwise it is YAML.
+func LoadCatalog(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var catalog Catalog
+	unmarshal := yaml.Unmarshal
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		// the codes are the string keys of the JSON objects, which YAML does not convert to the integers
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("parse message catalog %s: %w", path, err)
+	}
+	if err := RegisterMessages(catalog.Locale, catalog.Messages); err != nil {
+		return fmt.Errorf("register message catalog %s: %w", path, err)
+	}
+	return nil
+}
+
+// Localize returns the external message of the coder in the supported locale which best matches
+// the preferences of an Accept-Language header, like `zh-CN,zh;q=0.9,en;q=0.8`, and the locale.
+// The message is in DefaultLocale if no supported locale matches. It is also in DefaultLocale if the
+// matched locale has no message of the code, ok is false and the locale is the matched one then.
+func Localize(coder Coder, acceptLanguage string) (msg, locale string, ok bool) {
+	if acceptLanguage == "" {
+		return coder.External(), DefaultLocale, true
+	}
+	preferred, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return coder.External(), DefaultLocale, true
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, index, confidence := matcher.Match(preferred...)
+	if index == 0 || confidence == language.No {
+		return coder.External(), DefaultLocale, true
+	}
+	locale = locales[index].String()
+	if msg, ok := messages[locale][coder.Code()]; ok {
+		return msg, locale, true
+	}
+	return coder.External(), locale, false
+}
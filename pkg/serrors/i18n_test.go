@@ -0,0 +1,129 @@
+package serrors
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+// resetMessages restores the registered messages and locales after the test.
+func resetMessages(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	defer mu.Unlock()
+	saved, savedLocales := make(map[string]map[int]string, len(messages)), slices.Clone(locales)
+	for locale, msgs := range messages {
+		saved[locale] = maps.Clone(msgs)
+	}
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		messages, locales = saved, savedLocales
+		matcher = language.NewMatcher(locales)
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	resetMessages(t)
+	MustRegisterMessages("zh-CN", map[int]string{990301: "用户不存在。"})
+	MustRegisterMessages("fr", map[int]string{990301: "Utilisateur introuvable."})
+	notFound := Code{C: 990301, HTTP: 404, Ext: "User not found."}
+	exists := Code{C: 990302, HTTP: 409, Ext: "User already exists."}
+
+	tests := []struct {
+		name           string
+		coder          Coder
+		acceptLanguage string
+		wantMsg        string
+		wantLocale     string
+		wantOK         bool
+	}{
+		{"no preference", notFound, "", "User not found.", DefaultLocale, true},
+		{"malformed header", notFound, "zh-CN;q=x;;", "User not found.", DefaultLocale, true},
+		{"exact locale", notFound, "zh-CN", "用户不存在。", "zh-CN", true},
+		{"preferred locale", notFound, "fr;q=0.9,zh-CN;q=0.5", "Utilisateur introuvable.", "fr", true},
+		{"regional variant", notFound, "fr-CA", "Utilisateur introuvable.", "fr", true},
+		{"default locale preferred", notFound, "en-US,zh-CN;q=0.8", "User not found.", DefaultLocale, true},
+		{"unsupported locale", notFound, "ja", "User not found.", DefaultLocale, true},
+		{"message not translated", exists, "zh-CN", "User already exists.", "zh-CN", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, locale, ok := Localize(tt.coder, tt.acceptLanguage)
+			if msg != tt.wantMsg || locale != tt.wantLocale || ok != tt.wantOK {
+				t.Errorf("Localize(%q) = %q, %q, %v, want %q, %q, %v", tt.acceptLanguage, msg, locale, ok,
+					tt.wantMsg, tt.wantLocale, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	resetMessages(t)
+	tests := []struct {
+		name    string
+		locale  string
+		wantErr bool
+	}{
+		{"valid locale", "de", false},
+		{"invalid locale", "not a locale", true},
+		{"default locale", DefaultLocale, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterMessages(tt.locale, map[int]string{990303: "Benutzer existiert bereits."})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterMessages(%q) error = %v, wantErr %v", tt.locale, err, tt.wantErr)
+			}
+		})
+	}
+
+	// the messages of a registered locale are merged, the ones of the same codes are replaced
+	MustRegisterMessages("de", map[int]string{990303: "Benutzer existiert schon.", 990304: "Zugriff verweigert."})
+	for code, want := range map[int]string{990303: "Benutzer existiert schon.", 990304: "Zugriff verweigert."} {
+		if msg, _, _ := Localize(Code{C: code}, "de"); msg != want {
+			t.Errorf("Localize(%d) = %q, want %q", code, msg, want)
+		}
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	resetMessages(t)
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{"YAML", "zh-TW.yaml", "locale: zh-TW\nmessages:\n  990305: 使用者不存在。\n", false},
+		{"JSON", "ko.json", `{"locale": "ko", "messages": {"990305": "사용자가 없습니다."}}`, false},
+		{"malformed", "ja.yaml", "locale: [ja\n", true},
+		{"invalid locale", "xx.yaml", "locale: not a locale\nmessages: {}\n", true},
+		{"missing file", "missing.yaml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := LoadCatalog(path); (err != nil) != tt.wantErr {
+				t.Errorf("LoadCatalog(%s) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			}
+		})
+	}
+
+	coder := Code{C: 990305, Ext: "User not found."}
+	for locale, want := range map[string]string{"zh-TW": "使用者不存在。", "ko": "사용자가 없습니다."} {
+		if msg, _, ok := Localize(coder, locale); msg != want || !ok {
+			t.Errorf("Localize(%s) = %q, %v, want %q", locale, msg, ok, want)
+		}
+	}
+}
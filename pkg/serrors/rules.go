@@ -0,0 +1,82 @@
+package serrors
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Rules restrict the error codes registered by MustRegisterAll.
+type Rules struct {
+	// HTTPStatuses are the allowed HTTP statuses of the codes, all of the statuses are allowed if it is empty.
+	HTTPStatuses []int
+
+	// Ranges are the ranges of the codes of the services, the codes of a service must be in its range.
+	// The codes of all of the services are allowed if it is empty.
+	Ranges map[string]Range
+}
+
+// Range is an inclusive range of the codes, like 110000-119999.
+type Range struct {
+	Min int
+	Max int
+}
+
+// Contains reports whether the code is in the range.
+func (r Range) Contains(code int) bool {
+	return r.Min <= code && code <= r.Max
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// Validate checks the codes of the service against the rules, each other and the registered codes,
+// and returns all of the violations as an Aggregate. It returns nil if the codes are valid.
+func (r *Rules) Validate(service string, coders ...Coder) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return r.validate(service, coders)
+}
+
+func (r *Rules) validate(service string, coders []Coder) error {
+	var errs []error
+	rng, ranged := r.Ranges[service]
+	if len(r.Ranges) > 0 && !ranged {
+		errs = append(errs, fmt.Errorf("service %q has no code range", service))
+	}
+
+	seen := make(map[int]bool, len(coders))
+	for _, coder := range coders {
+		code := coder.Code()
+		if code == 0 {
+			errs = append(errs, fmt.Errorf("code 0 of %s is reserved as ErrUnknown", service))
+		}
+		if len(r.HTTPStatuses) > 0 && !slices.Contains(r.HTTPStatuses, coder.HTTPStatus()) {
+			errs = append(errs, fmt.Errorf("code %d of %s: HTTP status %d is not allowed", code, service,
+				coder.HTTPStatus()))
+		}
+		if ranged && !rng.Contains(code) {
+			errs = append(errs, fmt.Errorf("code %d of %s is out of the range %s", code, service, rng))
+		}
+		if _, ok := codes[code]; ok || seen[code] {
+			errs = append(errs, fmt.Errorf("code %d of %s already exists", code, service))
+		}
+		seen[code] = true
+	}
+	return NewAggregate(errs)
+}
+
+// MustRegisterAll registers the codes of the service after they are validated by the rules,
+// see Rules.Validate. It will panic with all of the violations if any, and no code is registered then.
+func MustRegisterAll(rules *Rules, service string, coders ...Coder) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := rules.validate(service, coders); err != nil {
+		panic(fmt.Sprintf("invalid error codes: %v", err))
+	}
+	for _, coder := range coders {
+		codes[coder.Code()] = coder
+	}
+}
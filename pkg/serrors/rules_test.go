@@ -0,0 +1,95 @@
+package serrors
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRulesValidate(t *testing.T) {
+	Register(Code{C: 990100, HTTP: http.StatusNotFound})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(codes, 990100)
+	})
+
+	rules := &Rules{
+		HTTPStatuses: []int{http.StatusOK, http.StatusBadRequest, http.StatusNotFound},
+		Ranges:       map[string]Range{"widget": {Min: 990100, Max: 990199}},
+	}
+	tests := []struct {
+		name       string
+		service    string
+		coders     []Coder
+		violations int
+	}{
+		{"valid codes", "widget", []Coder{
+			Code{C: 990101, HTTP: http.StatusNotFound},
+			Code{C: 990102, HTTP: http.StatusBadRequest},
+		}, 0},
+		{"status not allowed", "widget", []Coder{Code{C: 990101, HTTP: http.StatusTeapot}}, 1},
+		{"zero status is 500", "widget", []Coder{Code{C: 990101}}, 1},
+		{"out of the range", "widget", []Coder{Code{C: 990200, HTTP: http.StatusNotFound}}, 1},
+		{"service without a range", "gadget", []Coder{Code{C: 990101, HTTP: http.StatusNotFound}}, 1},
+		{"duplicate codes", "widget", []Coder{
+			Code{C: 990101, HTTP: http.StatusNotFound},
+			Code{C: 990101, HTTP: http.StatusBadRequest},
+		}, 1},
+		{"registered code", "widget", []Coder{Code{C: 990100, HTTP: http.StatusNotFound}}, 1},
+		{"reserved code", "widget", []Coder{Code{C: 0, HTTP: http.StatusNotFound}}, 2},
+		{"all of the violations", "widget", []Coder{
+			Code{C: 990101, HTTP: http.StatusTeapot},
+			Code{C: 990300, HTTP: http.StatusNotFound},
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Validate(tt.service, tt.coders...)
+			if tt.violations == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			agg, ok := err.(Aggregate)
+			if !ok || len(agg.Errors()) != tt.violations {
+				t.Errorf("Validate() error = %v, want %d violations", err, tt.violations)
+			}
+		})
+	}
+
+	// the rules without the statuses and the ranges allow all of them
+	if err := (&Rules{}).Validate("gadget", Code{C: 990300, HTTP: http.StatusTeapot}); err != nil {
+		t.Errorf("Validate() of empty rules error = %v, want nil", err)
+	}
+}
+
+func TestMustRegisterAll(t *testing.T) {
+	rules := &Rules{HTTPStatuses: []int{http.StatusNotFound}}
+	valid := Code{C: 990201, HTTP: http.StatusNotFound}
+	invalid := Code{C: 990202, HTTP: http.StatusTeapot}
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(codes, valid.C)
+		delete(codes, invalid.C)
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("MustRegisterAll() of an invalid code did not panic")
+			}
+		}()
+		MustRegisterAll(rules, "widget", valid, invalid)
+	}()
+	// no code is registered if any of them is invalid
+	if _, ok := Codes()[valid.C]; ok {
+		t.Errorf("MustRegisterAll() registered %d of the invalid codes", valid.C)
+	}
+
+	MustRegisterAll(rules, "widget", valid)
+	if got := ParseCoder(WithCode(valid.C, "widget not found")); got != valid {
+		t.Errorf("ParseCoder() = %v, want %v", got, valid)
+	}
+}
@@ -0,0 +1,53 @@
+package sign
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer signs the outgoing requests with an AccessKey/SecretKey pair.
+type Signer struct {
+	accessKey  string
+	signingKey []byte
+	// headers are the additional headers to sign besides the required ones.
+	headers []string
+	now     func() time.Time
+}
+
+// NewSigner creates a Signer with the AccessKey/SecretKey pair,
+// the headers are additional header names to sign, e.g. Content-Type.
+func NewSigner(accessKey, secretKey string, headers ...string) *Signer {
+	return &Signer{
+		accessKey:  accessKey,
+		signingKey: SigningKey(secretKey),
+		headers:    headers,
+		now:        time.Now,
+	}
+}
+
+// Sign sets the date, nonce, content hash and authorization headers of the request.
+// The body of the request is read and restored.
+func (s *Signer) Sign(r *http.Request) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	bodyHash := hashHex(body)
+
+	r.Header.Set(HeaderDate, strconv.FormatInt(s.now().Unix(), 10))
+	r.Header.Set(HeaderNonce, rand.Text())
+	r.Header.Set(HeaderContentSHA256, bodyHash)
+
+	signedHeaders := canonicalHeaderNames(s.headers)
+	r.Header.Set(HeaderAuthorization, fmt.Sprintf("%s Credential=%s, SignedHeaders=%s, Signature=%s",
+		Algorithm,
+		s.accessKey,
+		strings.Join(signedHeaders, ";"),
+		signature(s.signingKey, r, signedHeaders, bodyHash),
+	))
+	return nil
+}
@@ -0,0 +1,228 @@
+package sign
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned when the signature headers are missing or malformed.
+	ErrMalformed = errors.New("sign: malformed signature")
+	// ErrSignatureMismatch is returned when the signature or the content hash does not match the request.
+	ErrSignatureMismatch = errors.New("sign: signature does not match")
+	// ErrTimeSkewed is returned when the timestamp of the request is out of the allowed window.
+	ErrTimeSkewed = errors.New("sign: request time is out of the allowed window")
+	// ErrNonceReplayed is returned when the nonce has been used in the allowed window.
+	ErrNonceReplayed = errors.New("sign: nonce has been used")
+	// ErrBodyTooLarge is returned when the body of the request is larger than the limit of the Verifier.
+	ErrBodyTooLarge = errors.New("sign: request body is too large")
+)
+
+const (
+	// DefaultMaxBodySize is the largest body of the signed requests a Verifier reads by default.
+	DefaultMaxBodySize = 1 << 20
+	// MaxNonceLength is the longest nonce a Verifier accepts, the nonces a NonceStore remembers are
+	// the access keys and the nonces joined by '/'.
+	MaxNonceLength = 64
+)
+
+// KeyFunc looks up the signing key of the access key, the owner is an opaque identity returned by Verify.
+// The key is the SHA-256 digest of the secret key, see SigningKey.
+type KeyFunc func(ctx context.Context, accessKey string) (key []byte, owner string, err error)
+
+// NonceStore remembers the nonces of the verified requests until they expire.
+type NonceStore interface {
+	// Add remembers the nonce until expiresAt, it reports false if the nonce is remembered and not expired at now.
+	Add(ctx context.Context, nonce string, expiresAt, now time.Time) (bool, error)
+}
+
+// Verifier verifies the signed requests, it rejects the requests whose timestamp differs
+// from the local time by more than maxSkew, and the requests which reuse a nonce.
+//
+// The nonces are remembered in memory by default, so the replay protection covers a single process:
+// a request replayed to another instance behind the same load balancer is accepted. The Verifiers of
+// such instances must share a NonceStore, see WithNonceStore.
+type Verifier struct {
+	keys        KeyFunc
+	maxSkew     time.Duration
+	maxBodySize int64
+	nonces      NonceStore
+	now         func() time.Time
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithMaxBodySize limits the body of the requests to n bytes, the body is read to verify its hash before
+// the signature is checked, so the limit bounds the memory the unauthenticated requests take.
+// It is DefaultMaxBodySize by default.
+func WithMaxBodySize(n int64) VerifierOption {
+	return func(v *Verifier) {
+		v.maxBodySize = n
+	}
+}
+
+// WithNonceStore remembers the nonces in the store instead of the memory of the process.
+func WithNonceStore(store NonceStore) VerifierOption {
+	return func(v *Verifier) {
+		v.nonces = store
+	}
+}
+
+// NewVerifier creates a Verifier with the key lookup function and the allowed clock skew.
+func NewVerifier(keys KeyFunc, maxSkew time.Duration, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		keys:        keys,
+		maxSkew:     maxSkew,
+		maxBodySize: DefaultMaxBodySize,
+		nonces:      newNonceCache(),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify verifies the signature of the request and returns the owner of the access key.
+// The body of the request is read and restored. The errors of the KeyFunc and the NonceStore are returned as is.
+func (v *Verifier) Verify(r *http.Request) (string, error) {
+	accessKey, signedHeaders, sig, err := parseAuthorization(r.Header.Get(HeaderAuthorization))
+	if err != nil {
+		return "", err
+	}
+
+	ts, err := strconv.ParseInt(r.Header.Get(HeaderDate), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid %s header", ErrMalformed, HeaderDate)
+	}
+	now := v.now()
+	signedAt := time.Unix(ts, 0)
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return "", ErrTimeSkewed
+	}
+	nonce := r.Header.Get(HeaderNonce)
+	if nonce == "" {
+		return "", fmt.Errorf("%w: missing %s header", ErrMalformed, HeaderNonce)
+	}
+	if len(nonce) > MaxNonceLength {
+		return "", fmt.Errorf("%w: %s header is longer than %d bytes", ErrMalformed, HeaderNonce, MaxNonceLength)
+	}
+
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(nil, r.Body, v.maxBodySize)
+	}
+	body, err := readBody(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return "", fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, v.maxBodySize)
+		}
+		return "", fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	bodyHash := hashHex(body)
+	if r.Header.Get(HeaderContentSHA256) != bodyHash {
+		return "", fmt.Errorf("%w: content hash", ErrSignatureMismatch)
+	}
+
+	key, owner, err := v.keys(r.Context(), accessKey)
+	if err != nil {
+		return "", err
+	}
+	expected := signature(key, r, signedHeaders, bodyHash)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return "", ErrSignatureMismatch
+	}
+
+	// remember the nonce only after the signature is verified, so that forged requests can not burn nonces
+	fresh, err := v.nonces.Add(r.Context(), accessKey+"/"+nonce, signedAt.Add(v.maxSkew), now)
+	if err != nil {
+		return "", err
+	}
+	if !fresh {
+		return "", ErrNonceReplayed
+	}
+	return owner, nil
+}
+
+// parseAuthorization parses `SIAM-HMAC-SHA256 Credential=<ak>, SignedHeaders=<h1;h2>, Signature=<sig>`.
+func parseAuthorization(header string) (string, []string, string, error) {
+	scheme, params, ok := strings.Cut(header, " ")
+	if !ok || scheme != Algorithm {
+		return "", nil, "", fmt.Errorf("%w: authorization scheme is not %s", ErrMalformed, Algorithm)
+	}
+
+	fields := map[string]string{}
+	for _, kv := range strings.Split(params, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		if !ok {
+			return "", nil, "", fmt.Errorf("%w: invalid authorization parameter %q", ErrMalformed, kv)
+		}
+		fields[k] = v
+	}
+
+	accessKey, headers, sig := fields["Credential"], fields["SignedHeaders"], fields["Signature"]
+	if accessKey == "" || headers == "" || sig == "" {
+		return "", nil, "", fmt.Errorf("%w: missing authorization parameters", ErrMalformed)
+	}
+	signedHeaders := strings.Split(headers, ";")
+	if !containsAll(signedHeaders, requiredHeaders) {
+		return "", nil, "", fmt.Errorf("%w: signed headers must include %s", ErrMalformed,
+			strings.Join(requiredHeaders, ";"))
+	}
+	return accessKey, signedHeaders, sig, nil
+}
+
+func containsAll(set, items []string) bool {
+	for _, item := range items {
+		found := false
+		for _, s := range set {
+			if s == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// nonceCache is the NonceStore in memory.
+type nonceCache struct {
+	mu        sync.Mutex
+	nonces    map[string]time.Time
+	nextSweep time.Time
+}
+
+func newNonceCache() *nonceCache {
+	return &nonceCache{nonces: map[string]time.Time{}}
+}
+
+func (c *nonceCache) Add(_ context.Context, nonce string, expiresAt, now time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.nextSweep) {
+		for n, exp := range c.nonces {
+			if now.After(exp) {
+				delete(c.nonces, n)
+			}
+		}
+		c.nextSweep = now.Add(time.Minute)
+	}
+
+	if exp, ok := c.nonces[nonce]; ok && !now.After(exp) {
+		return false, nil
+	}
+	c.nonces[nonce] = expiresAt
+	return true, nil
+}
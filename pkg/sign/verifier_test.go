@@ -0,0 +1,322 @@
+package sign
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKTEST"
+	testSecretKey = "secret"
+)
+
+var errUnknownKey = errors.New("unknown access key")
+
+func testKeys(_ context.Context, accessKey string) ([]byte, string, error) {
+	if accessKey != testAccessKey {
+		return nil, "", errUnknownKey
+	}
+	return SigningKey(testSecretKey), "alice", nil
+}
+
+// signedRequest returns a request signed at the time by the signer of the secret key.
+func signedRequest(t *testing.T, secretKey string, at time.Time, body string) *http.Request {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "http://siam.example/v1/authz?b=2&a=1&a=0", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	s := NewSigner(testAccessKey, secretKey, "Content-Type")
+	s.now = func() time.Time { return at }
+	if err := s.Sign(r); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	return r
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1759300000, 0)
+	tests := []struct {
+		name    string
+		request func(t *testing.T) *http.Request
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			request: func(t *testing.T) *http.Request { return signedRequest(t, testSecretKey, now, `{"a":1}`) },
+		},
+		{
+			name:    "empty body",
+			request: func(t *testing.T) *http.Request { return signedRequest(t, testSecretKey, now, "") },
+		},
+		{
+			name: "clock skew in the window",
+			request: func(t *testing.T) *http.Request {
+				return signedRequest(t, testSecretKey, now.Add(-5*time.Minute), `{}`)
+			},
+		},
+		{
+			name: "signed too early",
+			request: func(t *testing.T) *http.Request {
+				return signedRequest(t, testSecretKey, now.Add(-5*time.Minute-time.Second), `{}`)
+			},
+			wantErr: ErrTimeSkewed,
+		},
+		{
+			name: "signed in the future",
+			request: func(t *testing.T) *http.Request {
+				return signedRequest(t, testSecretKey, now.Add(5*time.Minute+time.Second), `{}`)
+			},
+			wantErr: ErrTimeSkewed,
+		},
+		{
+			name: "wrong secret key",
+			request: func(t *testing.T) *http.Request {
+				return signedRequest(t, "other", now, `{}`)
+			},
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name: "tampered body",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{"admin":false}`)
+				r.Body = io.NopCloser(strings.NewReader(`{"admin":true}`))
+				return r
+			},
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name: "tampered body and content hash",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{"admin":false}`)
+				r.Body = io.NopCloser(strings.NewReader(`{"admin":true}`))
+				r.Header.Set(HeaderContentSHA256, hashHex([]byte(`{"admin":true}`)))
+				return r
+			},
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name: "tampered query",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.URL.RawQuery = "a=1"
+				return r
+			},
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name: "tampered signed header",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Set("Content-Type", "text/plain")
+				return r
+			},
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name: "missing authorization",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Del(HeaderAuthorization)
+				return r
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "required header not signed",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Set(HeaderAuthorization, strings.Replace(r.Header.Get(HeaderAuthorization),
+					";x-siam-nonce", "", 1))
+				return r
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "invalid date",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Set(HeaderDate, "yesterday")
+				return r
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "missing nonce",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Del(HeaderNonce)
+				return r
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "too long nonce",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Set(HeaderNonce, strings.Repeat("n", MaxNonceLength+1))
+				return r
+			},
+			wantErr: ErrMalformed,
+		},
+		{
+			name: "unknown access key",
+			request: func(t *testing.T) *http.Request {
+				r := signedRequest(t, testSecretKey, now, `{}`)
+				r.Header.Set(HeaderAuthorization, strings.Replace(r.Header.Get(HeaderAuthorization),
+					testAccessKey, "AKOTHER", 1))
+				return r
+			},
+			wantErr: errUnknownKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(testKeys, 5*time.Minute)
+			v.now = func() time.Time { return now }
+			owner, err := v.Verify(tt.request(t))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && owner != "alice" {
+				t.Errorf("Verify() owner = %q, want %q", owner, "alice")
+			}
+		})
+	}
+}
+
+func TestVerifyNonceReplay(t *testing.T) {
+	now := time.Unix(1759300000, 0)
+	v := NewVerifier(testKeys, 5*time.Minute)
+	v.now = func() time.Time { return now }
+
+	r := signedRequest(t, testSecretKey, now, `{}`)
+	replay := r.Clone(context.Background())
+	if _, err := v.Verify(r); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	replay.Body = io.NopCloser(strings.NewReader(`{}`))
+	if _, err := v.Verify(replay); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("Verify() of the replay error = %v, want %v", err, ErrNonceReplayed)
+	}
+
+	// a forged request with the nonce of another request does not burn it
+	forged := signedRequest(t, "other", now, `{}`)
+	genuine := signedRequest(t, testSecretKey, now, `{}`)
+	forged.Header.Set(HeaderNonce, genuine.Header.Get(HeaderNonce))
+	if _, err := v.Verify(forged); !errors.Is(err, ErrSignatureMismatch) {
+		t.Fatalf("Verify() of the forged request error = %v, want %v", err, ErrSignatureMismatch)
+	}
+	if _, err := v.Verify(genuine); err != nil {
+		t.Fatalf("Verify() of the genuine request error = %v", err)
+	}
+}
+
+func TestVerifySharedNonceStore(t *testing.T) {
+	now := time.Unix(1759300000, 0)
+	nonces := newNonceCache()
+	replica1 := NewVerifier(testKeys, 5*time.Minute, WithNonceStore(nonces))
+	replica2 := NewVerifier(testKeys, 5*time.Minute, WithNonceStore(nonces))
+	replica1.now = func() time.Time { return now }
+	replica2.now = func() time.Time { return now }
+
+	r := signedRequest(t, testSecretKey, now, `{}`)
+	replay := r.Clone(context.Background())
+	if _, err := replica1.Verify(r); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	replay.Body = io.NopCloser(strings.NewReader(`{}`))
+	if _, err := replica2.Verify(replay); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("Verify() of the replay on another replica error = %v, want %v", err, ErrNonceReplayed)
+	}
+}
+
+func TestVerifyMaxBodySize(t *testing.T) {
+	now := time.Unix(1759300000, 0)
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error
+	}{
+		{"under the limit", `{"a":1}`, nil},
+		{"at the limit", `{"a":12}`, nil},
+		{"over the limit", `{"a":123}`, ErrBodyTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(testKeys, 5*time.Minute, WithMaxBodySize(8))
+			v.now = func() time.Time { return now }
+			if _, err := v.Verify(signedRequest(t, testSecretKey, now, tt.body)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNonceCache(t *testing.T) {
+	start := time.Unix(1759300000, 0)
+	tests := []struct {
+		name  string
+		nonce string
+		at    time.Duration
+		want  bool
+	}{
+		{"first use", "n1", 0, true},
+		{"reuse in the window", "n1", time.Minute, false},
+		{"another nonce", "n2", time.Minute, true},
+		{"reuse at the expiry", "n1", 5 * time.Minute, false},
+		{"reuse after the expiry", "n1", 5*time.Minute + time.Second, true},
+	}
+	c := newNonceCache()
+	for _, tt := range tests {
+		now := start.Add(tt.at)
+		if got, err := c.Add(context.Background(), tt.nonce, now.Add(5*time.Minute), now); got != tt.want || err != nil {
+			t.Errorf("%s: Add(%q) = %v, %v, want %v, nil", tt.name, tt.nonce, got, err, tt.want)
+		}
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"b=2&a=1", "a=1&b=2"},
+		{"a=2&a=1&a=10", "a=1&a=10&a=2"},
+		{"q=hello+world&p=%2F", "p=%2F&q=hello+world"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://siam.example/?"+tt.query, nil)
+		if got := canonicalQuery(r.URL.Query()); got != tt.want {
+			t.Errorf("canonicalQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSignerHeaders(t *testing.T) {
+	at := time.Unix(1759300000, 0)
+	r := signedRequest(t, testSecretKey, at, `{}`)
+	if got, want := r.Header.Get(HeaderDate), strconv.FormatInt(at.Unix(), 10); got != want {
+		t.Errorf("%s = %q, want %q", HeaderDate, got, want)
+	}
+	if r.Header.Get(HeaderNonce) == "" {
+		t.Errorf("%s is empty", HeaderNonce)
+	}
+	if got, want := r.Header.Get(HeaderContentSHA256), hashHex([]byte(`{}`)); got != want {
+		t.Errorf("%s = %q, want %q", HeaderContentSHA256, got, want)
+	}
+	want := "SignedHeaders=content-type;host;x-siam-content-sha256;x-siam-date;x-siam-nonce,"
+	if got := r.Header.Get(HeaderAuthorization); !strings.Contains(got, want) {
+		t.Errorf("%s = %q, want it to contain %q", HeaderAuthorization, got, want)
+	}
+	body, _ := io.ReadAll(r.Body)
+	if string(body) != `{}` {
+		t.Errorf("body = %q, want it to be restored", body)
+	}
+}
@@ -0,0 +1,87 @@
+// Code generated by "codegen"; DO NOT EDIT.
+
+package v1
+
+// The error codes of base, they are the `code` fields of the error responses.
+const (
+	// ErrSuccess - 200: OK.
+	ErrSuccess = 100001
+	// ErrUnknown - 500: Internal server error.
+	ErrUnknown = 100002
+	// ErrBind - 400: Error occurred while binding the request body to the struct.
+	ErrBind = 100003
+	// ErrValidation - 400: Validation failed.
+	ErrValidation = 100004
+	// ErrPageNotFound - 404: Page not found.
+	ErrPageNotFound = 100005
+	// ErrConflict - 409: Object has been modified, please apply the changes to the latest version.
+	ErrConflict = 100006
+	// ErrResourceVersionTooOld - 410: Resource version is too old, please list the objects again.
+	ErrResourceVersionTooOld = 100007
+	// ErrDatabase - 500: Database error.
+	ErrDatabase = 100101
+	// ErrDatabaseUnavailable - 503: Database is unavailable.
+	ErrDatabaseUnavailable = 100102
+	// ErrEncrypt - 500: Error occurred while encrypting the user password.
+	ErrEncrypt = 100201
+	// ErrPasswordIncorrect - 401: Password was incorrect.
+	ErrPasswordIncorrect = 100202
+	// ErrTokenInvalid - 401: Token invalid.
+	ErrTokenInvalid = 100203
+	// ErrExpired - 401: Token expired.
+	ErrExpired = 100204
+	// ErrInvalidAuthHeader - 401: Invalid authorization header.
+	ErrInvalidAuthHeader = 100205
+	// ErrSignatureInvalid - 401: Signature is invalid.
+	ErrSignatureInvalid = 100206
+	// ErrRequestTimeSkewed - 401: Request time is out of the allowed window.
+	ErrRequestTimeSkewed = 100207
+	// ErrNonceReplayed - 401: Request nonce has been used.
+	ErrNonceReplayed = 100208
+	// ErrPermissionDenied - 403: Permission denied.
+	ErrPermissionDenied = 100209
+)
+
+// The error codes of apiserver, they are the `code` fields of the error responses.
+const (
+	// ErrUserNotFound - 404: User not found.
+	ErrUserNotFound = 110001
+	// ErrUserAlreadyExists - 409: User already exists.
+	ErrUserAlreadyExists = 110002
+	// ErrReachMaxCount - 429: Reach max count.
+	ErrReachMaxCount = 110101
+	// ErrSecretNotFound - 404: Secret not found.
+	ErrSecretNotFound = 110102
+	// ErrPolicyNotFound - 404: Policy not found.
+	ErrPolicyNotFound = 110201
+	// ErrPolicyAlreadyExists - 409: Policy already exists.
+	ErrPolicyAlreadyExists = 110202
+	// ErrAttachmentNotFound - 404: Policy attachment not found.
+	ErrAttachmentNotFound = 110203
+	// ErrAttachmentAlreadyExists - 409: Policy attachment already exists.
+	ErrAttachmentAlreadyExists = 110204
+	// ErrAuditQueryUnsupported - 400: Audit sink does not support query.
+	ErrAuditQueryUnsupported = 110301
+	// ErrGroupNotFound - 404: Group not found.
+	ErrGroupNotFound = 110401
+	// ErrGroupAlreadyExists - 409: Group already exists.
+	ErrGroupAlreadyExists = 110402
+	// ErrGroupMemberNotFound - 404: Group member not found.
+	ErrGroupMemberNotFound = 110403
+	// ErrGroupMemberAlreadyExists - 409: Group member already exists.
+	ErrGroupMemberAlreadyExists = 110404
+	// ErrRoleNotFound - 404: Role not found.
+	ErrRoleNotFound = 110501
+	// ErrRoleAlreadyExists - 409: Role already exists.
+	ErrRoleAlreadyExists = 110502
+	// ErrRoleNotTrusted - 403: Role is not allowed to be assumed.
+	ErrRoleNotTrusted = 110503
+	// ErrTenantNotFound - 404: Tenant not found.
+	ErrTenantNotFound = 110601
+	// ErrTenantAlreadyExists - 409: Tenant already exists.
+	ErrTenantAlreadyExists = 110602
+	// ErrTenantNotEmpty - 409: Tenant is not empty.
+	ErrTenantNotEmpty = 110603
+	// ErrTenantForbidden - 403: Access to the tenant is forbidden.
+	ErrTenantForbidden = 110604
+)
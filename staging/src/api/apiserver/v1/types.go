@@ -1 +1,335 @@
+// Package v1 defines the wire types of the siam-apiserver v1 API, which are shared by the server and the clients.
+// They are also used as gorm models by the server.
 package v1
+
+import (
+	"time"
+
+	"github.com/strayca7/siam/pkg/policy"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+	"github.com/strayca7/siam/staging/src/apimachinery/selector"
+)
+
+// User represents a user restful resource.
+type User struct {
+	metav1.ObjectMeta
+
+	Nickname string `json:"nickname" gorm:"size:64"`
+	// Password is the bcrypt hash of the user password, it is never exposed.
+	Password string `json:"-"        gorm:"size:255;not null"`
+	Email    string `json:"email"    gorm:"size:255"`
+	Phone    string `json:"phone"    gorm:"size:32"`
+	IsAdmin  bool   `json:"isAdmin"  gorm:"not null;default:false"`
+}
+
+// TableName maps to database table name.
+func (User) TableName() string {
+	return "users"
+}
+
+// Fields returns the fields of the user which can be selected by the field selectors.
+func (u *User) Fields() selector.Set {
+	return selector.Set{"name": u.Name, "nickname": u.Nickname, "email": u.Email, "phone": u.Phone}
+}
+
+// UserList is the whole list of all users which have been stored in storage.
+type UserList struct {
+	metav1.ListMeta
+
+	Items []*User `json:"items"`
+}
+
+// Secret represents a secret restful resource, which is an AccessKey/SecretKey pair owned by a user.
+// The name of a secret is optional, the secret is identified by its access key.
+type Secret struct {
+	metav1.ObjectMeta
+
+	Username string `json:"username" gorm:"size:64;not null"`
+	// AccessKey identifies the secret publicly.
+	AccessKey string `json:"accessKey" gorm:"size:64;not null;uniqueIndex"`
+	// SigningKey is the HMAC key derived from the secret key, which is sealed by the server key.
+	// The plain secret key is never stored, and the stored key is useless to sign without the server key.
+	SigningKey string `json:"-" gorm:"size:128;not null"`
+	// SecretKeyHash is the unsealed SHA-256 digest of the secret key of the secrets created by the earlier
+	// versions, it is sealed into SigningKey and cleared on start.
+	SecretKeyHash string     `json:"-"                   gorm:"size:64;not null"`
+	Description   string     `json:"description"         gorm:"size:255"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+}
+
+// TableName maps to database table name.
+func (Secret) TableName() string {
+	return "secrets"
+}
+
+// Expired reports whether the secret is expired at the given time.
+func (s *Secret) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// Fields returns the fields of the secret which can be selected by the field selectors.
+func (s *Secret) Fields() selector.Set {
+	return selector.Set{"name": s.Name, "username": s.Username, "accessKey": s.AccessKey}
+}
+
+// SecretWithKey is returned only when a secret key is generated,
+// it is the only chance for the owner to get the plain secret key.
+type SecretWithKey struct {
+	*Secret
+
+	SecretKey string `json:"secretKey"`
+}
+
+// SecretList is the whole list of all secrets which have been stored in storage.
+type SecretList struct {
+	metav1.ListMeta
+
+	Items []*Secret `json:"items"`
+}
+
+// Policy represents a policy restful resource, the policy document applies to the owner user.
+// The document is stored as JSON, the column type of each database is chosen by the migrations.
+type Policy struct {
+	metav1.ObjectMeta
+
+	Username    string          `json:"username"    gorm:"size:64;not null"`
+	Description string          `json:"description" gorm:"size:255"`
+	Document    policy.Document `json:"document"    gorm:"serializer:json;not null"`
+}
+
+// TableName maps to database table name.
+func (Policy) TableName() string {
+	return "policies"
+}
+
+// Fields returns the fields of the policy which can be selected by the field selectors.
+func (p *Policy) Fields() selector.Set {
+	return selector.Set{"name": p.Name, "username": p.Username}
+}
+
+// PolicyList is the whole list of all policies which have been stored in storage.
+type PolicyList struct {
+	metav1.ListMeta
+
+	Items []*Policy `json:"items"`
+}
+
+// Kinds of the principals which the policies are attached to.
+const (
+	PrincipalUser  = "user"
+	PrincipalGroup = "group"
+	PrincipalRole  = "role"
+)
+
+// Group represents a group restful resource, the policies attached to a group apply to all of its members.
+// It is also used as gorm model.
+type Group struct {
+	ID          uint64    `json:"id"          gorm:"primaryKey"`
+	Tenant      string    `json:"tenant"      gorm:"size:64;not null;default:default;uniqueIndex:idx_groups_tenant_name"`
+	Name        string    `json:"name"        gorm:"size:64;not null;uniqueIndex:idx_groups_tenant_name"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName maps to database table name.
+func (Group) TableName() string {
+	return "groups"
+}
+
+// Fields returns the fields of the group which can be selected by the field selectors.
+func (g *Group) Fields() selector.Set {
+	return selector.Set{"name": g.Name}
+}
+
+// GroupList is the whole list of all groups which have been stored in storage.
+type GroupList struct {
+	metav1.ListMeta
+
+	Items []*Group `json:"items"`
+}
+
+// GroupMember represents the membership of a user in a group. It is also used as gorm model.
+type GroupMember struct {
+	ID        uint64    `json:"id"        gorm:"primaryKey"`
+	Tenant    string    `json:"tenant"    gorm:"size:64;not null;default:default;uniqueIndex:idx_group_members_tenant_group_username"`
+	Group     string    `json:"group"     gorm:"column:group_name;size:64;not null;uniqueIndex:idx_group_members_tenant_group_username"`
+	Username  string    `json:"username"  gorm:"size:64;not null;uniqueIndex:idx_group_members_tenant_group_username;index"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName maps to database table name.
+func (GroupMember) TableName() string {
+	return "group_members"
+}
+
+// Fields returns the fields of the membership which can be selected by the field selectors.
+func (m *GroupMember) Fields() selector.Set {
+	return selector.Set{"group": m.Group, "username": m.Username}
+}
+
+// GroupMemberList is the whole list of all members of a group which have been stored in storage.
+type GroupMemberList struct {
+	metav1.ListMeta
+
+	Items []*GroupMember `json:"items"`
+}
+
+// Role represents a role restful resource. A role is assumed by the trusted users and the members of
+// the trusted groups, which get short-lived credentials of the role session carrying only the policies
+// attached to the role. It is also used as gorm model, the trusted principals are stored as JSON,
+// the column type of each database is chosen by the migrations.
+type Role struct {
+	ID            uint64    `json:"id"            gorm:"primaryKey"`
+	Tenant        string    `json:"tenant"        gorm:"size:64;not null;default:default;uniqueIndex:idx_roles_tenant_name"`
+	Name          string    `json:"name"          gorm:"size:64;not null;uniqueIndex:idx_roles_tenant_name"`
+	Description   string    `json:"description"   gorm:"size:255"`
+	TrustedUsers  []string  `json:"trustedUsers"  gorm:"serializer:json;not null"`
+	TrustedGroups []string  `json:"trustedGroups" gorm:"serializer:json;not null"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// TableName maps to database table name.
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Fields returns the fields of the role which can be selected by the field selectors.
+func (r *Role) Fields() selector.Set {
+	return selector.Set{"name": r.Name}
+}
+
+// RoleList is the whole list of all roles which have been stored in storage.
+type RoleList struct {
+	metav1.ListMeta
+
+	Items []*Role `json:"items"`
+}
+
+// PolicyAttachment attaches a policy to a user, a group or a role, the policy is identified by
+// its owner and name. Unlike the policies owned by a user, the attached policies are shared.
+// It is also used as gorm model.
+type PolicyAttachment struct {
+	ID     uint64 `json:"id"     gorm:"primaryKey"`
+	Tenant string `json:"tenant" gorm:"size:64;not null;default:default;uniqueIndex:idx_policy_attachments"`
+	// PrincipalKind is one of PrincipalUser, PrincipalGroup and PrincipalRole.
+	PrincipalKind string    `json:"principalKind" gorm:"size:16;not null;uniqueIndex:idx_policy_attachments"`
+	PrincipalName string    `json:"principalName" gorm:"size:64;not null;uniqueIndex:idx_policy_attachments"`
+	PolicyOwner   string    `json:"policyOwner"   gorm:"size:64;not null;uniqueIndex:idx_policy_attachments"`
+	PolicyName    string    `json:"policyName"    gorm:"size:64;not null;uniqueIndex:idx_policy_attachments"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// TableName maps to database table name.
+func (PolicyAttachment) TableName() string {
+	return "policy_attachments"
+}
+
+// Fields returns the fields of the attachment which can be selected by the field selectors.
+func (a *PolicyAttachment) Fields() selector.Set {
+	return selector.Set{"principalKind": a.PrincipalKind, "principalName": a.PrincipalName,
+		"policyOwner": a.PolicyOwner, "policyName": a.PolicyName}
+}
+
+// PolicyAttachmentList is the whole list of all policy attachments of a principal which have been stored in storage.
+type PolicyAttachmentList struct {
+	metav1.ListMeta
+
+	Items []*PolicyAttachment `json:"items"`
+}
+
+// Tenant represents a tenant restful resource, which isolates the users, secrets, policies, groups, roles
+// and audit events of a product from the other tenants. It is also used as gorm model.
+type Tenant struct {
+	ID          uint64    `json:"id"          gorm:"primaryKey"`
+	Name        string    `json:"name"        gorm:"size:64;not null;uniqueIndex"`
+	Description string    `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName maps to database table name.
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
+// Fields returns the fields of the tenant which can be selected by the field selectors.
+func (t *Tenant) Fields() selector.Set {
+	return selector.Set{"name": t.Name}
+}
+
+// TenantList is the whole list of all tenants which have been stored in storage.
+type TenantList struct {
+	metav1.ListMeta
+
+	Items []*Tenant `json:"items"`
+}
+
+// AuditEvent records a request which changed the resources or asked for authorization decisions.
+// It is also used as gorm model, the diff and the decisions are stored as JSON.
+type AuditEvent struct {
+	ID     uint64 `json:"id"     gorm:"primaryKey"`
+	Tenant string `json:"tenant" gorm:"size:64;not null;default:default;index"`
+	// Actor is the authenticated user, empty for the anonymous requests like the login.
+	Actor string `json:"actor" gorm:"size:64;index"`
+	// Action is the operation on the resource kind, e.g. `user:update` and `secret:rotate`.
+	Action string `json:"action" gorm:"size:64;not null"`
+	// Resource is the path of the resource without the API version, e.g. `users/alice/policies/admin`.
+	Resource string `json:"resource" gorm:"size:255;not null;index"`
+	// Status is the HTTP status of the response.
+	Status int `json:"status" gorm:"not null"`
+	// Diff maps the changed fields of the resource to their values before and after the request.
+	Diff map[string]AuditChange `json:"diff,omitempty" gorm:"serializer:json"`
+	// Decisions are the results of the authorization requests.
+	Decisions []AuditDecision `json:"decisions,omitempty" gorm:"serializer:json"`
+	TraceID   string          `json:"traceId"             gorm:"size:32"`
+	ClientIP  string          `json:"clientIp"            gorm:"size:64"`
+	CreatedAt time.Time       `json:"createdAt"           gorm:"index"`
+}
+
+// AuditChange is the value of a field before and after a request, nil if the field is absent.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditDecision is an authorization decision of the subject on the resource.
+type AuditDecision struct {
+	Subject  string `json:"subject"`
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Allowed  bool   `json:"allowed"`
+}
+
+// TableName maps to database table name.
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+// Fields returns the fields of the event which can be selected by the field selectors.
+func (e *AuditEvent) Fields() selector.Set {
+	return selector.Set{"actor": e.Actor, "action": e.Action, "resource": e.Resource,
+		"traceId": e.TraceID, "clientIp": e.ClientIP}
+}
+
+// AuditEventList is the whole list of all audit events which have been stored in storage.
+type AuditEventList struct {
+	metav1.ListMeta
+
+	Items []*AuditEvent `json:"items"`
+}
+
+// Token is a bearer token issued by the login.
+type Token struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// RoleSession is a bearer token of a session of a role, issued to a user who assumes the role.
+type RoleSession struct {
+	// Subject is the subject of the session in the authorization requests, e.g. `role:deployer`.
+	Subject   string    `json:"subject"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -1 +1,113 @@
+// Package v1 contains the API types shared by all of the API groups, like the metadata of the objects
+// and the options of the lists.
 package v1
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ObjectMeta is the metadata all of the persisted API objects must have. It is embedded in the objects
+// without a json tag, so its fields are inline in the JSON of the objects.
+// It is also used as a part of the gorm models, the column of every field is named by the default naming.
+type ObjectMeta struct {
+	// ID is the unique id of the object in the storage, it is assigned by the server.
+	ID uint64 `json:"id,omitempty" gorm:"primaryKey"`
+
+	// InstanceID is the unique id of the object in all of the deployments, like `user-3K9F...`.
+	// It is assigned by the server when the object is created, see NewInstanceID.
+	InstanceID string `json:"instanceID,omitempty" gorm:"size:64"`
+
+	// Tenant is the tenant the object belongs to, it is assigned by the server from the API path.
+	Tenant string `json:"tenant,omitempty" gorm:"size:64;not null;default:default"`
+
+	// Name is unique among the objects of the same kind and owner in a tenant,
+	// it is the identifier of the object in the API paths.
+	Name string `json:"name,omitempty" gorm:"size:64;not null"`
+
+	// Labels are the key value pairs used to organize and select the objects.
+	Labels map[string]string `json:"labels,omitempty" gorm:"serializer:json"`
+
+	// Annotations are the key value pairs to attach the arbitrary non-identifying metadata to the objects.
+	// They are not used to select the objects.
+	Annotations map[string]string `json:"annotations,omitempty" gorm:"serializer:json"`
+
+	// ResourceVersion is the version of the object, it is increased by every update of the object.
+	// The versions of the watchable objects are shared by all of the objects of the same storage.
+	// It is a string in the JSON, the clients must not interpret it but send it back to detect the conflicts
+	// of the concurrent updates.
+	ResourceVersion uint64 `json:"resourceVersion,string,omitempty" gorm:"not null"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewInstanceID returns a random instance id with the prefix of the kind, like `user-`.
+func NewInstanceID(prefix string) string {
+	return prefix + strings.ToLower(rand.Text())
+}
+
+// ListMeta is the metadata all of the lists must have, it is embedded in the lists without a json tag.
+type ListMeta struct {
+	// TotalCount is the number of the objects matching the query regardless of the pagination.
+	TotalCount int64 `json:"totalCount"`
+
+	// Continue is the token to get the next page of the list, it is empty on the last page.
+	Continue string `json:"continue,omitempty"`
+
+	// ResourceVersion is the version of the list of the watchable objects, a watch starting from it
+	// receives all of the changes after the list.
+	ResourceVersion uint64 `json:"resourceVersion,string,omitempty"`
+}
+
+// ListOptions are the query parameters of the list APIs.
+type ListOptions struct {
+	// LabelSelector selects the objects by their labels, like `env=prod,tier in (web,api)`.
+	LabelSelector string `json:"labelSelector,omitempty" form:"labelSelector"`
+
+	// FieldSelector selects the objects by their fields, like `name=alice`.
+	FieldSelector string `json:"fieldSelector,omitempty" form:"fieldSelector"`
+
+	// Limit is the maximal number of the objects in the page, 0 means the default limit of the server.
+	Limit int `json:"limit,omitempty" form:"limit" binding:"min=0,max=500"`
+
+	// Offset is the number of the objects to skip, it is ignored if Continue is set.
+	Offset int `json:"offset,omitempty" form:"offset" binding:"min=0"`
+
+	// Continue is the token returned in the ListMeta of the previous page.
+	Continue string `json:"continue,omitempty" form:"continue"`
+}
+
+// ErrInvalidContinue is returned by ListOptions.Start when the continue token is malformed.
+var ErrInvalidContinue = errors.New("continue token is invalid")
+
+// Start returns the offset of the page, which is decoded from the continue token if it is set.
+func (o *ListOptions) Start() (int, error) {
+	if o.Continue == "" {
+		return o.Offset, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(o.Continue)
+	if err != nil {
+		return 0, ErrInvalidContinue
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidContinue
+	}
+	return offset, nil
+}
+
+// SetContinue sets the continue token of the page of count objects starting at offset,
+// the token is cleared if the page is the last one.
+func (m *ListMeta) SetContinue(offset, count int) {
+	next := offset + count
+	if count == 0 || int64(next) >= m.TotalCount {
+		m.Continue = ""
+		return
+	}
+	m.Continue = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(next)))
+}
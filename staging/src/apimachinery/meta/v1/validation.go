@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Limits of the labels and the annotations.
+const (
+	maxLabels          = 32
+	maxLabelLength     = 63
+	maxAnnotationsSize = 64 * 1024
+)
+
+// labelPattern matches the label keys and the non-empty label values, they begin and end with an alphanumeric
+// character and may contain `-`, `_`, `.` and `/` in between. The keys are also the keys of the annotations.
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+
+// ValidateLabels checks the keys and the values of the labels, they can be used in the label selectors.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("labels must not have more than %d entries", maxLabels)
+	}
+	for k, v := range labels {
+		if err := validateKey(k); err != nil {
+			return fmt.Errorf("label %w", err)
+		}
+		if v != "" && (len(v) > maxLabelLength || !labelPattern.MatchString(v)) {
+			return fmt.Errorf("value %q of label %q must be at most %d alphanumeric characters, '-', '_', '.' or '/'",
+				v, k, maxLabelLength)
+		}
+	}
+	return nil
+}
+
+// ValidateAnnotations checks the keys of the annotations and their total size, the values are arbitrary.
+func ValidateAnnotations(annotations map[string]string) error {
+	size := 0
+	for k, v := range annotations {
+		if err := validateKey(k); err != nil {
+			return fmt.Errorf("annotation %w", err)
+		}
+		size += len(k) + len(v)
+	}
+	if size > maxAnnotationsSize {
+		return fmt.Errorf("annotations must not be larger than %d bytes", maxAnnotationsSize)
+	}
+	return nil
+}
+
+func validateKey(key string) error {
+	if len(key) > maxLabelLength || !labelPattern.MatchString(key) {
+		return fmt.Errorf("key %q must be 1 to %d alphanumeric characters, '-', '_', '.' or '/'", key, maxLabelLength)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package v1
+
+import "encoding/json"
+
+// EventType is the type of a watch event.
+type EventType string
+
+// The types of the watch events.
+const (
+	// Added is the event of a created object, it is also sent for the existing objects when a watch
+	// starts without a resource version.
+	Added EventType = "ADDED"
+	// Modified is the event of an updated object.
+	Modified EventType = "MODIFIED"
+	// Deleted is the event of a deleted object, the object is its last state.
+	Deleted EventType = "DELETED"
+	// Bookmark tells the watch has seen all of the changes up to the resource version of the object,
+	// which has no other fields. The watch can be resumed from it.
+	Bookmark EventType = "BOOKMARK"
+	// Error ends the watch, the object is the error response.
+	Error EventType = "ERROR"
+)
+
+// WatchEvent is an event of the watch APIs, the object is of the watched kind except for
+// the Bookmark and the Error events.
+type WatchEvent struct {
+	Type   EventType       `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// WatchOptions are the query parameters of the watch APIs.
+type WatchOptions struct {
+	// LabelSelector selects the objects by their labels, like `env=prod,tier in (web,api)`.
+	LabelSelector string `json:"labelSelector,omitempty" form:"labelSelector"`
+
+	// FieldSelector selects the objects by their fields, like `name=alice`.
+	FieldSelector string `json:"fieldSelector,omitempty" form:"fieldSelector"`
+
+	// ResourceVersion is the version the watch starts after, which is the resource version of a list
+	// or of the last event received. The watch sends the Added events of the existing objects first if it is empty.
+	ResourceVersion string `json:"resourceVersion,omitempty" form:"resourceVersion"`
+}
@@ -0,0 +1,195 @@
+// Package selector parses and matches the label selectors and the field selectors of the list APIs.
+//
+// A selector is a comma separated list of requirements which must all be satisfied:
+//
+//	key=value, key==value  the key exists and its value is value
+//	key!=value             the key does not exist or its value is not value
+//	key in (v1,v2)         the key exists and its value is one of the values
+//	key notin (v1,v2)      the key does not exist or its value is none of the values
+//	key                    the key exists
+//	!key                   the key does not exist
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Operator is the operator of a requirement.
+type Operator string
+
+// The operators of the requirements.
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+var (
+	// keyPattern matches the keys, which are the label keys or the field names.
+	keyPattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+	// setPattern matches the set based requirements, like `key in (v1,v2)`.
+	setPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\(([^()]*)\)$`)
+)
+
+// reserved are the characters which are not allowed in the values.
+const reserved = " \t,()=!"
+
+// Set is the key value pairs a selector is matched against, like the labels or the fields of an object.
+type Set map[string]string
+
+// Requirement is a single requirement of a selector. Values has exactly one value for Equals and NotEquals,
+// at least one for In and NotIn, and none for Exists and DoesNotExist.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches reports whether the set satisfies the requirement.
+func (r Requirement) Matches(set Set) bool {
+	value, ok := set[r.Key]
+	switch r.Operator {
+	case Equals:
+		return ok && value == r.Values[0]
+	case NotEquals:
+		return !ok || value != r.Values[0]
+	case In:
+		return ok && slices.Contains(r.Values, value)
+	case NotIn:
+		return !ok || !slices.Contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// String returns the requirement in the selector syntax.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case In, NotIn:
+		return r.Key + " " + string(r.Operator) + " (" + strings.Join(r.Values, ",") + ")"
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	}
+	return r.Key + string(r.Operator) + r.Values[0]
+}
+
+// Selector is a list of requirements, the empty selector matches everything.
+type Selector []Requirement
+
+// Parse parses the selector, the blank string is parsed to the empty selector.
+func Parse(s string) (Selector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	var sel Selector
+	for _, term := range split(s) {
+		r, err := parseRequirement(strings.TrimSpace(term))
+		if err != nil {
+			return nil, fmt.Errorf("parse selector %q: %w", s, err)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Empty reports whether the selector has no requirements.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches reports whether the set satisfies all of the requirements.
+func (s Selector) Matches(set Set) bool {
+	for _, r := range s {
+		if !r.Matches(set) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the selector syntax.
+func (s Selector) String() string {
+	terms := make([]string, len(s))
+	for i, r := range s {
+		terms[i] = r.String()
+	}
+	return strings.Join(terms, ",")
+}
+
+// split splits the selector into the requirements by the commas outside of the parentheses.
+func split(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, s[start:])
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	if term == "" {
+		return Requirement{}, fmt.Errorf("empty requirement")
+	}
+
+	if m := setPattern.FindStringSubmatch(term); m != nil {
+		r := Requirement{Key: m[1], Operator: Operator(m[2])}
+		for v := range strings.SplitSeq(m[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(v))
+		}
+		if len(r.Values) == 1 && r.Values[0] == "" {
+			return Requirement{}, fmt.Errorf("requirement %q must have at least one value", term)
+		}
+		return r, r.validate()
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if key, value, ok := strings.Cut(term, op); ok {
+			r := Requirement{Key: strings.TrimSpace(key), Operator: Equals, Values: []string{strings.TrimSpace(value)}}
+			if op == "!=" {
+				r.Operator = NotEquals
+			}
+			return r, r.validate()
+		}
+	}
+
+	if key, ok := strings.CutPrefix(term, "!"); ok {
+		r := Requirement{Key: strings.TrimSpace(key), Operator: DoesNotExist}
+		return r, r.validate()
+	}
+	r := Requirement{Key: term, Operator: Exists}
+	return r, r.validate()
+}
+
+func (r Requirement) validate() error {
+	if len(r.Key) > 63 || !keyPattern.MatchString(r.Key) {
+		return fmt.Errorf("key %q must be 1 to 63 alphanumeric characters, '-', '_', '.' or '/'", r.Key)
+	}
+	for _, v := range r.Values {
+		if strings.ContainsAny(v, reserved) {
+			return fmt.Errorf("value %q of key %q must not contain whitespaces or any of ',()=!'", v, r.Key)
+		}
+	}
+	return nil
+}
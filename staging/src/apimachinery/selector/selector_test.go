@@ -0,0 +1,133 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     Selector
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"env=prod", Selector{{Key: "env", Operator: Equals, Values: []string{"prod"}}}},
+		{"env==prod", Selector{{Key: "env", Operator: Equals, Values: []string{"prod"}}}},
+		{"env = prod", Selector{{Key: "env", Operator: Equals, Values: []string{"prod"}}}},
+		{"env=", Selector{{Key: "env", Operator: Equals, Values: []string{""}}}},
+		{"env!=prod", Selector{{Key: "env", Operator: NotEquals, Values: []string{"prod"}}}},
+		{"env in (prod, staging)", Selector{{Key: "env", Operator: In, Values: []string{"prod", "staging"}}}},
+		{"env notin (dev)", Selector{{Key: "env", Operator: NotIn, Values: []string{"dev"}}}},
+		{"env", Selector{{Key: "env", Operator: Exists}}},
+		{"!env", Selector{{Key: "env", Operator: DoesNotExist}}},
+		{"siam.io/team", Selector{{Key: "siam.io/team", Operator: Exists}}},
+		{"env in (prod,staging),tier=db, !canary", Selector{
+			{Key: "env", Operator: In, Values: []string{"prod", "staging"}},
+			{Key: "tier", Operator: Equals, Values: []string{"db"}},
+			{Key: "canary", Operator: DoesNotExist},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.selector)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.selector, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+	}{
+		{"empty requirement", "env=prod,"},
+		{"empty requirement in the middle", "env=prod,,tier=db"},
+		{"empty key", "=prod"},
+		{"empty key of not", "!"},
+		{"invalid key", "-env=prod"},
+		{"key with a space", "my env"},
+		{"too long key", "k234567890123456789012345678901234567890123456789012345678901234=v"},
+		{"value with a space", "env=prod east"},
+		{"value with an operator", "env=a=b"},
+		{"value with a parenthesis", "env=prod)"},
+		{"empty set", "env in ()"},
+		{"value of a set with an operator", "env in (a=b)"},
+		{"unclosed set", "env in (prod"},
+		{"unknown set operator", "env within (prod)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if sel, err := Parse(tt.selector); err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tt.selector, sel)
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	set := Set{"env": "prod", "tier": "db"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=dev", false},
+		{"team=iam", false},
+		{"env!=dev", true},
+		{"env!=prod", false},
+		{"team!=iam", true},
+		{"env in (dev,prod)", true},
+		{"env in (dev,staging)", false},
+		{"team in (iam)", false},
+		{"env notin (dev)", true},
+		{"env notin (prod)", false},
+		{"team notin (iam)", true},
+		{"env", true},
+		{"team", false},
+		{"!team", true},
+		{"!env", false},
+		{"env=prod,tier=db", true},
+		{"env=prod,tier=cache", false},
+	}
+	for _, tt := range tests {
+		sel, err := Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.selector, err)
+		}
+		if got := sel.Matches(set); got != tt.want {
+			t.Errorf("Parse(%q).Matches(%v) = %v, want %v", tt.selector, set, got, tt.want)
+		}
+	}
+}
+
+func TestSelectorString(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"", ""},
+		{"env==prod", "env=prod"},
+		{"env != prod", "env!=prod"},
+		{"env in ( prod , staging )", "env in (prod,staging)"},
+		{"env notin (dev), !canary, tier", "env notin (dev),!canary,tier"},
+	}
+	for _, tt := range tests {
+		sel, err := Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.selector, err)
+		}
+		got := sel.String()
+		if got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.selector, got, tt.want)
+		}
+		if again, err := Parse(got); err != nil || !reflect.DeepEqual(again, sel) {
+			t.Errorf("Parse(%q) = %v, %v, want %v", got, again, err, sel)
+		}
+	}
+}
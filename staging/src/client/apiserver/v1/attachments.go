@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// AttachmentClient manages the policies attached to a user, a group or a role. The changes are allowed
+// to the admins of the tenant only.
+type AttachmentClient struct {
+	c *Client
+	// kind is one of apiv1.PrincipalUser, apiv1.PrincipalGroup and apiv1.PrincipalRole.
+	kind string
+	name string
+}
+
+// path returns the path of the attachments of the principal, followed by the elements.
+func (a *AttachmentClient) path(elems ...string) (string, error) {
+	var collection string
+	switch a.kind {
+	case apiv1.PrincipalUser:
+		collection = "users"
+	case apiv1.PrincipalGroup:
+		collection = "groups"
+	case apiv1.PrincipalRole:
+		collection = "roles"
+	default:
+		return "", fmt.Errorf("principal kind %q is not one of user, group and role", a.kind)
+	}
+	return a.c.path(append([]string{collection, a.name, "attachments"}, elems...)...), nil
+}
+
+// Create attaches the policy of the owner to the principal.
+func (a *AttachmentClient) Create(ctx context.Context, policyOwner, policyName string,
+) (*apiv1.PolicyAttachment, error) {
+	path, err := a.path()
+	if err != nil {
+		return nil, err
+	}
+	body := struct {
+		PolicyOwner string `json:"policyOwner"`
+		PolicyName  string `json:"policyName"`
+	}{policyOwner, policyName}
+	out := &apiv1.PolicyAttachment{}
+	if err := a.c.do(ctx, once, http.MethodPost, path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the policies attached to the principal, opts may be nil.
+func (a *AttachmentClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.PolicyAttachmentList, error) {
+	path, err := a.path()
+	if err != nil {
+		return nil, err
+	}
+	out := &apiv1.PolicyAttachmentList{}
+	if err := a.c.do(ctx, retried, http.MethodGet, path, listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete detaches the policy of the owner from the principal.
+func (a *AttachmentClient) Delete(ctx context.Context, policyOwner, policyName string) error {
+	path, err := a.path(policyOwner, policyName)
+	if err != nil {
+		return err
+	}
+	return a.c.do(ctx, retried, http.MethodDelete, path, nil, nil, nil)
+}
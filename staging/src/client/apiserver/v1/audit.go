@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// AuditClient queries the audit events, it is allowed to the admins of the tenant only.
+type AuditClient struct {
+	c *Client
+}
+
+// AuditFilter selects the audit events, the zero values select all of them.
+type AuditFilter struct {
+	// Actor selects the events of the actor.
+	Actor string
+	// Resource selects the events of the resource and its sub resources, e.g. `users/alice`.
+	Resource string
+	// Since and Until select the events created in [Since, Until).
+	Since time.Time
+	Until time.Time
+}
+
+// List lists the audit events of the tenant selected by the filter, the latest events first.
+// Both filter and opts may be nil.
+func (a *AuditClient) List(ctx context.Context, filter *AuditFilter, opts *metav1.ListOptions,
+) (*apiv1.AuditEventList, error) {
+	query := listQuery(opts)
+	if filter != nil {
+		if filter.Actor != "" {
+			query.Set("actor", filter.Actor)
+		}
+		if filter.Resource != "" {
+			query.Set("resource", filter.Resource)
+		}
+		if !filter.Since.IsZero() {
+			query.Set("since", filter.Since.Format(time.RFC3339))
+		}
+		if !filter.Until.IsZero() {
+			query.Set("until", filter.Until.Format(time.RFC3339))
+		}
+	}
+	out := &apiv1.AuditEventList{}
+	if err := a.c.do(ctx, retried, http.MethodGet, a.c.path("audit-events"), query, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/strayca7/siam/pkg/authz"
+)
+
+// AuthzClient asks for the authorization decisions.
+type AuthzClient struct {
+	c *Client
+}
+
+// Authorize makes the decisions of the requests with the stored policies, the decisions are in the same order
+// as the requests. The decisions never change anything, so they are retried like the reads.
+func (a *AuthzClient) Authorize(ctx context.Context, requests ...*authz.Request) ([]*authz.Decision, error) {
+	body := struct {
+		Requests []*authz.Request `json:"requests"`
+	}{requests}
+	var out struct {
+		Decisions []*authz.Decision `json:"decisions"`
+	}
+	if err := a.c.do(ctx, retried, http.MethodPost, a.c.path("authz"), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out.Decisions, nil
+}
@@ -0,0 +1,370 @@
+// Package v1 is the Go client of the siam-apiserver v1 API, it sends and receives the types of package
+// github.com/strayca7/siam/staging/src/api/apiserver/v1.
+//
+// The requests are authenticated with a bearer token or signed with an AccessKey/SecretKey pair, and they carry
+// the trace of their context in the traceparent header. The reads and the idempotent writes are retried on the
+// network failures and the temporary server errors, after the Retry-After of the server if any. The error responses
+// of the server are returned as the coded errors of package serrors, so that serrors.IsCode tells them apart
+// by the error codes of package github.com/strayca7/siam/staging/src/api/apiserver/v1:
+//
+//	user, err := client.Users().Get(ctx, "alice")
+//	if serrors.IsCode(err, apiv1.ErrUserNotFound) {
+//		...
+//	}
+//
+// The codes are not registered in the process of the client, the errors print the messages of the server,
+// which are also the Message of the StatusError in the chain.
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/strayca7/siam/pkg/logger"
+	"github.com/strayca7/siam/pkg/serrors"
+	"github.com/strayca7/siam/pkg/sign"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+const (
+	// DefaultMaxRetries is the number of the retries of a request which is safe to retry if Config.MaxRetries is 0.
+	DefaultMaxRetries = 3
+	// DefaultTimeout is the timeout of every attempt of a request if Config.HTTPClient is nil.
+	DefaultTimeout = 30 * time.Second
+
+	// defaultTenant is the tenant of the API paths without a tenant.
+	defaultTenant = "default"
+	// minBackoff and maxBackoff bound the interval between the attempts, which doubles after every attempt.
+	minBackoff = 200 * time.Millisecond
+	maxBackoff = 5 * time.Second
+)
+
+// Config defines the server and the credentials of a Client.
+type Config struct {
+	// Server is the address of siam-apiserver, like `http://127.0.0.1:8080`.
+	Server string
+
+	// Tenant scopes the requests to the API paths of the tenant, empty is the default tenant.
+	Tenant string
+
+	// Token is the bearer token to authenticate with, like the token returned by the login.
+	Token string
+
+	// AccessKey and SecretKey sign the requests with SIAM-HMAC-SHA256, they are used if Token is empty.
+	AccessKey string
+	SecretKey string
+
+	// MaxRetries is the number of the retries of a request which is safe to retry, 0 is DefaultMaxRetries
+	// and a negative value disables the retries.
+	MaxRetries int
+
+	// HTTPClient sends the requests, it is a client of DefaultTimeout if nil.
+	HTTPClient *http.Client
+}
+
+// Client is the client of the siam-apiserver v1 API, it is safe for the concurrent use.
+type Client struct {
+	server     string
+	tenant     string
+	token      string
+	signer     *sign.Signer
+	maxRetries int
+	http       *http.Client
+}
+
+// New creates a Client with the config.
+func New(cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.Server)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("server %q must be an absolute URL", cfg.Server)
+	}
+	if (cfg.AccessKey == "") != (cfg.SecretKey == "") {
+		return nil, errors.New("access key and secret key must be set together")
+	}
+
+	c := &Client{
+		server:     strings.TrimSuffix(cfg.Server, "/"),
+		tenant:     cfg.Tenant,
+		token:      cfg.Token,
+		maxRetries: cfg.MaxRetries,
+		http:       cfg.HTTPClient,
+	}
+	if c.token == "" && cfg.AccessKey != "" {
+		c.signer = sign.NewSigner(cfg.AccessKey, cfg.SecretKey)
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = DefaultMaxRetries
+	}
+	if c.http == nil {
+		c.http = &http.Client{Timeout: DefaultTimeout}
+	}
+	return c, nil
+}
+
+// Users returns the client of the users.
+func (c *Client) Users() *UserClient {
+	return &UserClient{c: c}
+}
+
+// Secrets returns the client of the secrets of the user.
+func (c *Client) Secrets(username string) *SecretClient {
+	return &SecretClient{c: c, username: username}
+}
+
+// Policies returns the client of the policies of the user.
+func (c *Client) Policies(username string) *PolicyClient {
+	return &PolicyClient{c: c, username: username}
+}
+
+// Groups returns the client of the groups.
+func (c *Client) Groups() *GroupClient {
+	return &GroupClient{c: c}
+}
+
+// Roles returns the client of the roles.
+func (c *Client) Roles() *RoleClient {
+	return &RoleClient{c: c}
+}
+
+// Attachments returns the client of the policies attached to the principal, the kind of the principal
+// is one of apiv1.PrincipalUser, apiv1.PrincipalGroup and apiv1.PrincipalRole.
+func (c *Client) Attachments(kind, name string) *AttachmentClient {
+	return &AttachmentClient{c: c, kind: kind, name: name}
+}
+
+// Tenants returns the client of the tenants.
+func (c *Client) Tenants() *TenantClient {
+	return &TenantClient{c: c}
+}
+
+// Audit returns the client of the audit events.
+func (c *Client) Audit() *AuditClient {
+	return &AuditClient{c: c}
+}
+
+// Authz returns the client of the authorization decisions.
+func (c *Client) Authz() *AuthzClient {
+	return &AuthzClient{c: c}
+}
+
+// StatusError is the error response of the server, it is the cause of the coded error returned by the requests.
+type StatusError struct {
+	// HTTPStatus is the status of the response.
+	HTTPStatus int `json:"-"`
+	// Code is the error code, like apiv1.ErrUserNotFound.
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Reference string `json:"reference,omitempty"`
+	// TraceID is the trace of the request, it helps to find the logs of the error on the server.
+	TraceID string `json:"trace_id,omitempty"`
+	// Details are the details of the error, the ones of the known types are the serrors.Details of the
+	// coded error, like the field violations of an invalid request.
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+func (e *StatusError) Error() string {
+	if e.Reference != "" {
+		return fmt.Sprintf("%s (code: %d, reference: %s)", e.Message, e.Code, e.Reference)
+	}
+	return fmt.Sprintf("%s (code: %d)", e.Message, e.Code)
+}
+
+// path returns the API path of the elements, which are escaped, in the tenant of the client.
+func (c *Client) path(elems ...string) string {
+	return c.pathOf(c.tenant != "" && c.tenant != defaultTenant, elems...)
+}
+
+// tenantPath is like path but the path is always under the tenant, even if it is the default one.
+// Some of the routes are allowed to the admins under the tenant paths only, like the creation of the admins.
+func (c *Client) tenantPath(elems ...string) string {
+	return c.pathOf(true, elems...)
+}
+
+func (c *Client) pathOf(scoped bool, elems ...string) string {
+	var b strings.Builder
+	b.WriteString("/v1")
+	if scoped {
+		tenant := c.tenant
+		if tenant == "" {
+			tenant = defaultTenant
+		}
+		b.WriteString("/tenants/" + url.PathEscape(tenant))
+	}
+	for _, e := range elems {
+		b.WriteString("/" + url.PathEscape(e))
+	}
+	return b.String()
+}
+
+// retryPolicy tells whether a request is sent again on the network failures and the temporary server errors,
+// it is chosen by every call since the method alone does not tell whether sending a request twice is safe.
+type retryPolicy bool
+
+const (
+	// retried requests have the same effect however many times they are sent, like the reads and the updates.
+	retried retryPolicy = true
+	// once requests are sent once, like the creations and the password changes, whose second attempt would
+	// fail or change the state again after the first one succeeded without the client knowing it.
+	once retryPolicy = false
+)
+
+// retryable reports whether the status of the response is temporary.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// do sends the request with the JSON encoded body in and decodes the response into out, both may be nil.
+// The retried requests are sent until they succeed, fail permanently or the retries are exhausted.
+func (c *Client) do(ctx context.Context, policy retryPolicy, method, path string, query url.Values, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+	}
+	// all of the attempts are a single span of the trace of the caller
+	ctx, _ = logger.StartSpan(ctx)
+
+	backoff := minBackoff
+	for attempt := 0; ; attempt++ {
+		status, header, data, err := c.send(ctx, method, path, query, body)
+		retry := policy == retried && attempt < c.maxRetries && ctx.Err() == nil &&
+			(err != nil || retryable(status))
+		if !retry {
+			if err != nil {
+				return err
+			}
+			return decode(method, path, status, data, out)
+		}
+
+		// the server knows better when it is able to serve the request again
+		wait := backoff
+		if after, ok := retryAfter(header, time.Now()); ok && err == nil {
+			wait = after
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// retryAfter returns the duration of the Retry-After header of the response at now, which is either
+// the seconds or the HTTP date to retry after. ok is false if the header is absent or malformed.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, seconds >= 0
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// send sends a single attempt of the request and returns the status, the header and the body of the response.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body []byte,
+) (int, http.Header, []byte, error) {
+	u := c.server + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	logger.InjectTraceParent(ctx, req.Header)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.signer != nil {
+		if err := c.signer.Sign(req); err != nil {
+			return 0, nil, nil, fmt.Errorf("sign request: %w", err)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("read response body: %w", err)
+	}
+	return resp.StatusCode, resp.Header, data, nil
+}
+
+// decode decodes the successful response into out, or the error response into a coded error.
+func decode(method, path string, status int, data []byte, out any) error {
+	if status != http.StatusOK {
+		e := &StatusError{HTTPStatus: status}
+		if err := json.Unmarshal(data, e); err != nil || e.Code == 0 {
+			return fmt.Errorf("%s %s: unexpected response status %d", method, path, status)
+		}
+		var details []serrors.Detail
+		for _, raw := range e.Details {
+			// the debug information of the server is not a detail of the error
+			if d, err := serrors.ParseDetail(raw); err == nil {
+				details = append(details, d)
+			}
+		}
+		// the codes of the server are not registered in the process of the client, so the coded error is
+		// annotated with the message of the server, which it would print as an unknown error otherwise
+		return serrors.WithMessage(serrors.WithDetails(serrors.WrapC(e, e.Code, "%s", e.Message), details...),
+			e.Error())
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+	return nil
+}
+
+// listQuery returns the query parameters of the list options.
+func listQuery(opts *metav1.ListOptions) url.Values {
+	query := url.Values{}
+	if opts == nil {
+		return query
+	}
+	if opts.LabelSelector != "" {
+		query.Set("labelSelector", opts.LabelSelector)
+	}
+	if opts.FieldSelector != "" {
+		query.Set("fieldSelector", opts.FieldSelector)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Continue != "" {
+		query.Set("continue", opts.Continue)
+	}
+	return query
+}
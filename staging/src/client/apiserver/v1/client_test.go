@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/strayca7/siam/pkg/serrors"
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// newTestClient returns a client of a server which serves the requests with the handler,
+// and the number of the requests served.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	c, err := New(Config{Server: server.URL, Token: "token"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return c, &requests
+}
+
+// unavailable responds 503 with the Retry-After of 0 seconds for the first n requests, and with body after them.
+func unavailable(n int32, body string) http.HandlerFunc {
+	var served atomic.Int32
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if served.Add(1) <= n {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"code":100002,"message":"Service unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		unavailable  int32
+		call         func(c *Client) error
+		wantErr      bool
+		wantRequests int32
+	}{
+		{
+			name:        "read is retried",
+			unavailable: 2,
+			call: func(c *Client) error {
+				_, err := c.Users().Get(context.Background(), "alice")
+				return err
+			},
+			wantRequests: 3,
+		},
+		{
+			name:        "read fails after the retries",
+			unavailable: DefaultMaxRetries + 1,
+			call: func(c *Client) error {
+				_, err := c.Users().Get(context.Background(), "alice")
+				return err
+			},
+			wantErr:      true,
+			wantRequests: DefaultMaxRetries + 1,
+		},
+		{
+			name:        "creation is sent once",
+			unavailable: 1,
+			call: func(c *Client) error {
+				_, err := c.Groups().Create(context.Background(), &apiv1.Group{Name: "devs"})
+				return err
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:        "password change is sent once",
+			unavailable: 1,
+			call: func(c *Client) error {
+				return c.Users().ChangePassword(context.Background(), "alice", "password1", "password2")
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, requests := newTestClient(t, unavailable(tt.unavailable, `{"name":"alice"}`))
+			err := tt.call(c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("call error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"absent", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"negative seconds", "-1", 0, false},
+		{"date", now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"malformed", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfter(header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":110001,"message":"User not found","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736",` +
+			`"details":[{"@type":"ResourceInfo","resourceType":"users","resourceName":"alice"},` +
+			`{"@type":"DebugInfo","stack":"main.main()"}]}`))
+	})
+
+	_, err := c.Users().Get(context.Background(), "alice")
+	if !serrors.IsCode(err, apiv1.ErrUserNotFound) {
+		t.Fatalf("Get() error = %v, want code %d", err, apiv1.ErrUserNotFound)
+	}
+	if want := "User not found (code: 110001)"; err.Error() != want {
+		t.Errorf("Get() error = %q, want %q", err.Error(), want)
+	}
+	var status *StatusError
+	if !errors.As(err, &status) {
+		t.Fatalf("Get() error = %v, want a StatusError in the chain", err)
+	}
+	if status.HTTPStatus != http.StatusNotFound || status.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("StatusError = %+v, want the status and the trace of the response", status)
+	}
+	// the details of the unknown types are skipped
+	details := serrors.DetailsOf[*serrors.ResourceInfo](err)
+	if len(serrors.Details(err)) != 1 || len(details) != 1 || details[0].ResourceName != "alice" {
+		t.Errorf("Details() = %v, want the resource info of alice", serrors.Details(err))
+	}
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+	c.maxRetries = -1
+
+	_, err := c.Users().Get(context.Background(), "alice")
+	if err == nil || serrors.IsCode(err, apiv1.ErrUserNotFound) {
+		t.Fatalf("Get() error = %v, want an error of the unexpected status", err)
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		t.Errorf("Get() error = %v, want no StatusError of a response which is not an error of the server", err)
+	}
+}
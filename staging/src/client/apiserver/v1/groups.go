@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// GroupClient manages the groups and their members, the changes are allowed to the admins of the tenant only.
+type GroupClient struct {
+	c *Client
+}
+
+// Create creates the group with its name and description.
+func (g *GroupClient) Create(ctx context.Context, group *apiv1.Group) (*apiv1.Group, error) {
+	body := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{group.Name, group.Description}
+	out := &apiv1.Group{}
+	if err := g.c.do(ctx, once, http.MethodPost, g.c.path("groups"), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the group by name.
+func (g *GroupClient) Get(ctx context.Context, name string) (*apiv1.Group, error) {
+	out := &apiv1.Group{}
+	if err := g.c.do(ctx, retried, http.MethodGet, g.c.path("groups", name), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the groups, opts may be nil.
+func (g *GroupClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.GroupList, error) {
+	out := &apiv1.GroupList{}
+	if err := g.c.do(ctx, retried, http.MethodGet, g.c.path("groups"), listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the description of the group.
+func (g *GroupClient) Update(ctx context.Context, group *apiv1.Group) (*apiv1.Group, error) {
+	body := struct {
+		Description string `json:"description"`
+	}{group.Description}
+	out := &apiv1.Group{}
+	if err := g.c.do(ctx, retried, http.MethodPut, g.c.path("groups", group.Name), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the group by name with its memberships and attachments.
+func (g *GroupClient) Delete(ctx context.Context, name string) error {
+	return g.c.do(ctx, retried, http.MethodDelete, g.c.path("groups", name), nil, nil, nil)
+}
+
+// AddMember adds the user to the group.
+func (g *GroupClient) AddMember(ctx context.Context, group, username string) (*apiv1.GroupMember, error) {
+	body := struct {
+		Username string `json:"username"`
+	}{username}
+	out := &apiv1.GroupMember{}
+	if err := g.c.do(ctx, once, http.MethodPost, g.c.path("groups", group, "members"), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMembers lists the members of the group, opts may be nil.
+func (g *GroupClient) ListMembers(ctx context.Context, group string, opts *metav1.ListOptions,
+) (*apiv1.GroupMemberList, error) {
+	out := &apiv1.GroupMemberList{}
+	path := g.c.path("groups", group, "members")
+	if err := g.c.do(ctx, retried, http.MethodGet, path, listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RemoveMember removes the user from the group.
+func (g *GroupClient) RemoveMember(ctx context.Context, group, username string) error {
+	return g.c.do(ctx, retried, http.MethodDelete, g.c.path("groups", group, "members", username), nil, nil, nil)
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+)
+
+// Login exchanges the password of the user in the tenant of the client for a bearer token, which
+// authenticates the clients created with it as Config.Token. The client itself needs no credentials.
+func (c *Client) Login(ctx context.Context, username, password string) (*apiv1.Token, error) {
+	body := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{username, password}
+	out := &apiv1.Token{}
+	if err := c.do(ctx, once, http.MethodPost, c.path("login"), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
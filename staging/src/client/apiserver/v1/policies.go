@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// PolicyClient manages the policies of a user.
+type PolicyClient struct {
+	c        *Client
+	username string
+}
+
+// Create creates the policy, the policy is created as it is given except the metadata assigned by the server.
+func (p *PolicyClient) Create(ctx context.Context, pol *apiv1.Policy) (*apiv1.Policy, error) {
+	out := &apiv1.Policy{}
+	if err := p.c.do(ctx, once, http.MethodPost, p.c.path("users", p.username, "policies"), nil, pol, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the policy by name.
+func (p *PolicyClient) Get(ctx context.Context, name string) (*apiv1.Policy, error) {
+	out := &apiv1.Policy{}
+	path := p.c.path("users", p.username, "policies", name)
+	if err := p.c.do(ctx, retried, http.MethodGet, path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the policies of the user, opts may be nil.
+func (p *PolicyClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.PolicyList, error) {
+	out := &apiv1.PolicyList{}
+	path := p.c.path("users", p.username, "policies")
+	if err := p.c.do(ctx, retried, http.MethodGet, path, listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the description, the document and the metadata of the policy.
+// It fails with apiv1.ErrConflict if the policy has been changed since its resource version, which is
+// not checked if the resource version is not set.
+func (p *PolicyClient) Update(ctx context.Context, pol *apiv1.Policy) (*apiv1.Policy, error) {
+	out := &apiv1.Policy{}
+	path := p.c.path("users", p.username, "policies", pol.Name)
+	if err := p.c.do(ctx, retried, http.MethodPut, path, nil, pol, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the policy by name.
+func (p *PolicyClient) Delete(ctx context.Context, name string) error {
+	return p.c.do(ctx, retried, http.MethodDelete, p.c.path("users", p.username, "policies", name), nil, nil, nil)
+}
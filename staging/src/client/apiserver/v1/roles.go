@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// RoleClient manages and assumes the roles, the changes are allowed to the admins of the tenant only.
+type RoleClient struct {
+	c *Client
+}
+
+// roleBody is the request body of the creation and the update of a role.
+type roleBody struct {
+	Name          string   `json:"name,omitempty"`
+	Description   string   `json:"description"`
+	TrustedUsers  []string `json:"trustedUsers"`
+	TrustedGroups []string `json:"trustedGroups"`
+}
+
+// newRoleBody returns the body of the role, the nil trusted principals are sent as empty ones
+// since the server leaves the trusted principals of the null ones unchanged.
+func newRoleBody(role *apiv1.Role) roleBody {
+	b := roleBody{Name: role.Name, Description: role.Description, TrustedUsers: role.TrustedUsers,
+		TrustedGroups: role.TrustedGroups}
+	if b.TrustedUsers == nil {
+		b.TrustedUsers = []string{}
+	}
+	if b.TrustedGroups == nil {
+		b.TrustedGroups = []string{}
+	}
+	return b
+}
+
+// Create creates the role with its name, description and trusted principals.
+func (r *RoleClient) Create(ctx context.Context, role *apiv1.Role) (*apiv1.Role, error) {
+	out := &apiv1.Role{}
+	if err := r.c.do(ctx, once, http.MethodPost, r.c.path("roles"), nil, newRoleBody(role), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the role by name.
+func (r *RoleClient) Get(ctx context.Context, name string) (*apiv1.Role, error) {
+	out := &apiv1.Role{}
+	if err := r.c.do(ctx, retried, http.MethodGet, r.c.path("roles", name), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the roles, opts may be nil.
+func (r *RoleClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.RoleList, error) {
+	out := &apiv1.RoleList{}
+	if err := r.c.do(ctx, retried, http.MethodGet, r.c.path("roles"), listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the description and the trusted principals of the role.
+func (r *RoleClient) Update(ctx context.Context, role *apiv1.Role) (*apiv1.Role, error) {
+	body := newRoleBody(role)
+	body.Name = ""
+	out := &apiv1.Role{}
+	if err := r.c.do(ctx, retried, http.MethodPut, r.c.path("roles", role.Name), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the role by name with its attachments.
+func (r *RoleClient) Delete(ctx context.Context, name string) error {
+	return r.c.do(ctx, retried, http.MethodDelete, r.c.path("roles", name), nil, nil, nil)
+}
+
+// Assume issues a token of a session of the role to the user of the client, who must be trusted by the role.
+// The session lasts for the duration, which is rounded down to seconds, 0 is the duration of the server.
+func (r *RoleClient) Assume(ctx context.Context, name string, duration time.Duration) (*apiv1.RoleSession, error) {
+	body := struct {
+		DurationSeconds int `json:"durationSeconds"`
+	}{int(duration / time.Second)}
+	out := &apiv1.RoleSession{}
+	if err := r.c.do(ctx, once, http.MethodPost, r.c.path("roles", name, "assume"), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
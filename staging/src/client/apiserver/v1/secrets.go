@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// SecretClient manages the secrets of a user.
+type SecretClient struct {
+	c        *Client
+	username string
+}
+
+// Create creates a secret with the optional name, description, expiration and metadata of the secret.
+// The generated secret key is returned only once.
+func (s *SecretClient) Create(ctx context.Context, secret *apiv1.Secret) (*apiv1.SecretWithKey, error) {
+	out := &apiv1.SecretWithKey{}
+	if err := s.c.do(ctx, once, http.MethodPost, s.c.path("users", s.username, "secrets"), nil, secret, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the secret by the access key.
+func (s *SecretClient) Get(ctx context.Context, accessKey string) (*apiv1.Secret, error) {
+	out := &apiv1.Secret{}
+	path := s.c.path("users", s.username, "secrets", accessKey)
+	if err := s.c.do(ctx, retried, http.MethodGet, path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the secrets of the user, opts may be nil.
+func (s *SecretClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.SecretList, error) {
+	out := &apiv1.SecretList{}
+	path := s.c.path("users", s.username, "secrets")
+	if err := s.c.do(ctx, retried, http.MethodGet, path, listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the description, the expiration and the metadata of the secret. The expiration is left
+// unchanged if it is not set or has passed, the server accepts the future ones only.
+// It fails with apiv1.ErrConflict if the secret has been changed since its resource version, which is
+// not checked if the resource version is not set.
+func (s *SecretClient) Update(ctx context.Context, secret *apiv1.Secret) (*apiv1.Secret, error) {
+	body := struct {
+		Description     string            `json:"description"`
+		ExpiresAt       *time.Time        `json:"expiresAt,omitempty"`
+		Labels          map[string]string `json:"labels"`
+		Annotations     map[string]string `json:"annotations"`
+		ResourceVersion uint64            `json:"resourceVersion,string,omitempty"`
+	}{secret.Description, nil, secret.Labels, secret.Annotations, secret.ResourceVersion}
+	if !secret.Expired(time.Now()) {
+		body.ExpiresAt = secret.ExpiresAt
+	}
+	out := &apiv1.Secret{}
+	path := s.c.path("users", s.username, "secrets", secret.AccessKey)
+	if err := s.c.do(ctx, retried, http.MethodPut, path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the secret by the access key.
+func (s *SecretClient) Delete(ctx context.Context, accessKey string) error {
+	return s.c.do(ctx, retried, http.MethodDelete, s.c.path("users", s.username, "secrets", accessKey), nil, nil, nil)
+}
+
+// Rotate replaces the secret key of the secret, the new secret key is returned only once.
+// It is not retried, a lost response leaves the secret with a secret key nobody knows.
+func (s *SecretClient) Rotate(ctx context.Context, accessKey string) (*apiv1.SecretWithKey, error) {
+	out := &apiv1.SecretWithKey{}
+	path := s.c.path("users", s.username, "secrets", accessKey, "rotate")
+	if err := s.c.do(ctx, once, http.MethodPost, path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Expire expires the secret immediately.
+func (s *SecretClient) Expire(ctx context.Context, accessKey string) (*apiv1.Secret, error) {
+	out := &apiv1.Secret{}
+	path := s.c.path("users", s.username, "secrets", accessKey, "expire")
+	if err := s.c.do(ctx, once, http.MethodPost, path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
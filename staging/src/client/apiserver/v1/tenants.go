@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// TenantClient manages the tenants, it is allowed to the system admins only. The paths of the tenants
+// are not scoped to the tenant of the client.
+type TenantClient struct {
+	c *Client
+}
+
+// Create creates the tenant with its name and description.
+func (t *TenantClient) Create(ctx context.Context, tenant *apiv1.Tenant) (*apiv1.Tenant, error) {
+	body := struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}{tenant.Name, tenant.Description}
+	out := &apiv1.Tenant{}
+	if err := t.c.do(ctx, once, http.MethodPost, t.c.pathOf(false, "tenants"), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the tenant by name.
+func (t *TenantClient) Get(ctx context.Context, name string) (*apiv1.Tenant, error) {
+	out := &apiv1.Tenant{}
+	if err := t.c.do(ctx, retried, http.MethodGet, t.c.pathOf(false, "tenants", name), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the tenants, opts may be nil.
+func (t *TenantClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.TenantList, error) {
+	out := &apiv1.TenantList{}
+	if err := t.c.do(ctx, retried, http.MethodGet, t.c.pathOf(false, "tenants"), listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the description of the tenant.
+func (t *TenantClient) Update(ctx context.Context, tenant *apiv1.Tenant) (*apiv1.Tenant, error) {
+	body := struct {
+		Description string `json:"description"`
+	}{tenant.Description}
+	out := &apiv1.Tenant{}
+	if err := t.c.do(ctx, retried, http.MethodPut, t.c.pathOf(false, "tenants", tenant.Name), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the tenant by name, only the empty tenants are deleted.
+func (t *TenantClient) Delete(ctx context.Context, name string) error {
+	return t.c.do(ctx, retried, http.MethodDelete, t.c.pathOf(false, "tenants", name), nil, nil, nil)
+}
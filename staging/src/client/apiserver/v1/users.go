@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	apiv1 "github.com/strayca7/siam/staging/src/api/apiserver/v1"
+	metav1 "github.com/strayca7/siam/staging/src/apimachinery/meta/v1"
+)
+
+// UserClient manages the users.
+type UserClient struct {
+	c *Client
+}
+
+// Create creates the user with the password, the user is created as it is given except the metadata
+// assigned by the server. The admins are created by the admins of the tenant only, the other users
+// register themselves in the default tenant.
+func (u *UserClient) Create(ctx context.Context, user *apiv1.User, password string) (*apiv1.User, error) {
+	body := struct {
+		*apiv1.User
+		Password string `json:"password"`
+	}{user, password}
+	path := u.c.path("users")
+	if user.IsAdmin {
+		// the registration of the default tenant ignores the admin flag
+		path = u.c.tenantPath("users")
+	}
+	out := &apiv1.User{}
+	if err := u.c.do(ctx, once, http.MethodPost, path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Get gets the user by name.
+func (u *UserClient) Get(ctx context.Context, name string) (*apiv1.User, error) {
+	out := &apiv1.User{}
+	if err := u.c.do(ctx, retried, http.MethodGet, u.c.path("users", name), nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// List lists the users, opts may be nil.
+func (u *UserClient) List(ctx context.Context, opts *metav1.ListOptions) (*apiv1.UserList, error) {
+	out := &apiv1.UserList{}
+	if err := u.c.do(ctx, retried, http.MethodGet, u.c.path("users"), listQuery(opts), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces the nickname, the admin flag and the metadata of the user, and the email and the phone
+// if they are not empty. The admin flag is changed by the admins of the tenant only.
+// It fails with apiv1.ErrConflict if the user has been changed since its resource version, which is
+// not checked if the resource version is not set.
+func (u *UserClient) Update(ctx context.Context, user *apiv1.User) (*apiv1.User, error) {
+	// the server validates the email and the phone if they are given, so the empty ones are left out
+	body := struct {
+		Nickname        string            `json:"nickname"`
+		Email           string            `json:"email,omitempty"`
+		Phone           string            `json:"phone,omitempty"`
+		IsAdmin         bool              `json:"isAdmin"`
+		Labels          map[string]string `json:"labels"`
+		Annotations     map[string]string `json:"annotations"`
+		ResourceVersion uint64            `json:"resourceVersion,string,omitempty"`
+	}{user.Nickname, user.Email, user.Phone, user.IsAdmin, user.Labels, user.Annotations, user.ResourceVersion}
+	out := &apiv1.User{}
+	if err := u.c.do(ctx, retried, http.MethodPut, u.c.path("users", user.Name), nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete deletes the user by name with its secrets and policies.
+func (u *UserClient) Delete(ctx context.Context, name string) error {
+	return u.c.do(ctx, retried, http.MethodDelete, u.c.path("users", name), nil, nil, nil)
+}
+
+// ChangePassword changes the password of the user, the old password must be correct. It is not retried, since
+// a retry of a change which succeeded would be rejected with the old password which is no longer correct.
+func (u *UserClient) ChangePassword(ctx context.Context, name, oldPassword, newPassword string) error {
+	body := map[string]string{"oldPassword": oldPassword, "newPassword": newPassword}
+	return u.c.do(ctx, once, http.MethodPut, u.c.path("users", name, "change-password"), nil, body, nil)
+}
@@ -10,12 +10,13 @@ import (
 	"github.com/spf13/viper"
 )
 
-const configFlagName = "config"
+// ConfigFlagName is the name of the flag to specify the configuration file.
+const ConfigFlagName = "config"
 
 var cfgFile string
 
 func init() {
-	pflag.StringVarP(&cfgFile, configFlagName, "c", "", "Path to the configuration file, support only YAML.")
+	pflag.StringVarP(&cfgFile, ConfigFlagName, "c", "", "Path to the configuration file, support only YAML.")
 }
 
 // AddConfigFlag adds a config flag to the specified FlagSet object and binds it to viper.
@@ -23,7 +24,7 @@ func init() {
 // It will be used as the prefix of environment variables and the name of configuration file by viper.
 func AddConfigFlag(basename string, fs *pflag.FlagSet) {
 	// register the config flag to the specified FlagSet object
-	fs.AddFlag(pflag.Lookup(configFlagName))
+	fs.AddFlag(pflag.Lookup(ConfigFlagName))
 
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix(strings.ReplaceAll(strings.ToUpper(basename), "-", "_"))
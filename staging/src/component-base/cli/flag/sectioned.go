@@ -10,10 +10,6 @@ import (
 	"github.com/spf13/pflag"
 )
 
-const (
-	usageFmt = "Usage:\n  %s\n"
-)
-
 // PrintSections prints the given names flag sets in sections, with the maximal given column number.
 // If cols is zero, lines are not wrapped. From k8s.io/component-base/cli/flag/sectioned.go
 func PrintSections(w io.Writer, fss NamedFlagSets, cols int) {
@@ -50,12 +46,36 @@ func PrintSections(w io.Writer, fss NamedFlagSets, cols int) {
 // Print the flag sets we need instead of all of them. From k8s.io/component-base/cli/flag/sectioned.go
 func SetUsageAndHelpFunc(cmd *cobra.Command, fss NamedFlagSets, cols int) {
 	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
-		fmt.Fprintf(cmd.OutOrStderr(), usageFmt, cmd.UseLine())
+		printUsage(cmd.OutOrStderr(), cmd)
 		PrintSections(cmd.OutOrStderr(), fss, cols)
 		return nil
 	})
 	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
-		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
+		desc := cmd.Long
+		if desc == "" {
+			desc = cmd.Short
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n", desc)
+		printUsage(cmd.OutOrStdout(), cmd)
 		PrintSections(cmd.OutOrStdout(), fss, cols)
 	})
 }
+
+// printUsage prints the usage lines and the available sub commands of the command.
+func printUsage(w io.Writer, cmd *cobra.Command) {
+	fmt.Fprintf(w, "Usage:\n")
+	if cmd.Runnable() || !cmd.HasAvailableSubCommands() {
+		fmt.Fprintf(w, "  %s\n", cmd.UseLine())
+	}
+	if !cmd.HasAvailableSubCommands() {
+		return
+	}
+	fmt.Fprintf(w, "  %s [command]\n", cmd.CommandPath())
+
+	fmt.Fprintf(w, "\nAvailable commands:\n")
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() {
+			fmt.Fprintf(w, "  %-*s %s\n", cmd.NamePadding(), c.Name(), c.Short)
+		}
+	}
+}
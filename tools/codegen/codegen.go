@@ -0,0 +1,533 @@
+// Codegen generates the registrations of the error codes of a package and their Markdown reference document.
+//
+// Every exported integer constant named Err* of the package must be documented by the comment convention
+// of package github.com/strayca7/siam/internal/pkg/code:
+//
+//	// ErrUserNotFound - 404: User not found.
+//	ErrUserNotFound
+//
+// The HTTP status and the external message of the comment are registered with the value of the constant
+// by the init function of the generated file, so that the codes are compiled into the binaries.
+// The comment may be followed by the translations of the message, one locale per line:
+//
+//	// ErrUserNotFound - 404: User not found.
+//	// zh-CN: 用户不存在。
+//	ErrUserNotFound
+//
+// The codes of a file belong to the service of the file name, like `apiserver` of apiserver.go. They are
+// validated by the serrors.Rules of the flags -statuses and -range, both when they are generated and when
+// they are registered, and all of the violations are reported at once. If the package doc lists the allowed
+// HTTP statuses in the form of `StatusNotFound = 404`, the list must be the one of -statuses.
+//
+// Usage:
+//
+//	codegen [-statuses list] [-range service=min-max]... [-output file] [-doc file] [-public file] [-check] [dir]
+//
+// With -public, the codes are also generated as the constants of the package of the other Go files in the
+// directory of the file, so that the clients out of the module, which cannot import an internal package,
+// tell the codes apart.
+//
+// It is run by go generate in the directory of the package, see `make gen`. With -check, or if the
+// environment variable CODEGEN_CHECK is set, it writes nothing but fails if the codes are invalid or
+// the generated files are out of date, see `make gen.check`.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/strayca7/siam/pkg/serrors"
+)
+
+// header is the first line of the generated files, see https://go.dev/s/generatedcode.
+const header = `Code generated by "codegen"; DO NOT EDIT.`
+
+var (
+	output = flag.String("output", "code_generated.go", "the generated Go file, relative to the package directory")
+	doc    = flag.String("doc", "", "the generated Markdown document, relative to the package directory")
+	public = flag.String("public", "",
+		"the generated Go file of the constants of the codes in another package, relative to the package directory")
+	check = flag.Bool("check", os.Getenv("CODEGEN_CHECK") != "",
+		"check the codes and the generated files without writing them")
+
+	rules = serrors.Rules{Ranges: map[string]serrors.Range{}}
+)
+
+func init() {
+	flag.Func("statuses", "the comma separated list of the allowed HTTP statuses, like 200,400,500",
+		func(v string) error {
+			for s := range strings.SplitSeq(v, ",") {
+				status, err := strconv.Atoi(strings.TrimSpace(s))
+				if err != nil {
+					return fmt.Errorf("invalid HTTP status %q", s)
+				}
+				rules.HTTPStatuses = append(rules.HTTPStatuses, status)
+			}
+			return nil
+		})
+	flag.Func("range", "the code range of a service, like apiserver=110000-119999, it can be repeated",
+		func(v string) error {
+			service, bounds, ok := strings.Cut(v, "=")
+			lo, hi, ok2 := strings.Cut(bounds, "-")
+			minCode, err1 := strconv.Atoi(lo)
+			maxCode, err2 := strconv.Atoi(hi)
+			if !ok || !ok2 || service == "" || err1 != nil || err2 != nil || minCode > maxCode {
+				return fmt.Errorf("invalid code range %q, want service=min-max", v)
+			}
+			rules.Ranges[service] = serrors.Range{Min: minCode, Max: maxCode}
+			return nil
+		})
+}
+
+var (
+	// commentPattern matches the comment of an error code, like `ErrUserNotFound - 404: User not found.`.
+	commentPattern = regexp.MustCompile(`^(Err\w+) - (\d{3}): (.+?)\.?$`)
+	// translationPattern matches a translation of the message of an error code, like `zh-CN: 用户不存在。`.
+	translationPattern = regexp.MustCompile(`^([A-Za-z]{2,3}(?:-[A-Za-z0-9]+)*): (.+?)[.。]?$`)
+	// statusPattern matches an HTTP status listed by the package doc, like `StatusNotFound = 404 // RFC 7231`.
+	statusPattern = regexp.MustCompile(`^\s*Status\w+\s*=\s*(\d{3})\b`)
+)
+
+// errorCode is an error code defined in the package.
+type errorCode struct {
+	name    string
+	value   int64
+	status  int
+	message string
+	// translations are the messages by their locales.
+	translations map[string]string
+	// group is the comment of the const declaration of the code, like `common: basic errors.`.
+	group string
+	// service is the name of the file of the code without the extension, like `apiserver`.
+	service string
+	pos     token.Position
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("codegen: ")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr,
+			"Usage: codegen [-statuses list] [-range service=min-max]... [-output file] [-doc file] [-public file] "+
+				"[-check] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	pkg, codes, err := parse(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	files := map[string][]byte{filepath.Join(dir, *output): generate(pkg, codes)}
+	if *doc != "" {
+		files[filepath.Join(dir, *doc)] = document(codes)
+	}
+	if *public != "" {
+		path := filepath.Join(dir, *public)
+		publicPkg, err := packageOf(filepath.Dir(path))
+		if err != nil {
+			log.Fatal(err)
+		}
+		files[path] = constants(publicPkg, codes)
+	}
+
+	if *check {
+		if stale := outdated(files); len(stale) > 0 {
+			log.Fatalf("generated files are out of date, run `make gen`:\n\t%s", strings.Join(stale, "\n\t"))
+		}
+		return
+	}
+	for path, data := range files {
+		if err := write(path, data); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// outdated returns the sorted paths of the files whose contents are not their generated data,
+// including the ones which do not exist.
+func outdated(files map[string][]byte) []string {
+	var stale []string
+	for path, data := range files {
+		if current, err := os.ReadFile(path); err != nil || !bytes.Equal(current, data) {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// parse parses the package in dir and returns its name and its error codes in the order of their values.
+// The generated files are skipped, so the package is parsed the same before and after the generation.
+func parse(dir string) (string, []errorCode, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		if !ast.IsGenerated(f) {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	// the constants are evaluated by the type checker, the code packages have no imports
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	if _, err := new(types.Config).Check(files[0].Name.Name, fset, files, info); err != nil {
+		return "", nil, err
+	}
+
+	var (
+		codes []errorCode
+		errs  []string
+	)
+	if err := checkStatuses(files); err != nil {
+		errs = append(errs, err.Error())
+	}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, name := range vs.Names {
+					if !name.IsExported() || !strings.HasPrefix(name.Name, "Err") {
+						continue
+					}
+					pos := fset.Position(name.Pos())
+					code, err := parseCode(name, vs, gen, info)
+					if err != nil {
+						errs = append(errs, fmt.Sprintf("%s: %v", pos, err))
+						continue
+					}
+					code.pos = pos
+					code.service = strings.TrimSuffix(filepath.Base(pos.Filename), ".go")
+					// the invalid codes are still checked for the duplicates below
+					if err := rules.Validate(code.service, code.coder()); err != nil {
+						errs = append(errs, fmt.Sprintf("%s: %s: %v", pos, name.Name, err))
+					}
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+
+	sort.Slice(codes, func(i, j int) bool { return codes[i].value < codes[j].value })
+	for i := 1; i < len(codes); i++ {
+		if codes[i].value == codes[i-1].value {
+			errs = append(errs, fmt.Sprintf("%s: %s: code %d is also defined by %s at %s", codes[i].pos,
+				codes[i].name, codes[i].value, codes[i-1].name, codes[i-1].pos))
+		}
+	}
+	if len(errs) > 0 {
+		return "", nil, fmt.Errorf("%d violations of the error codes:\n\t%s", len(errs), strings.Join(errs, "\n\t"))
+	}
+	return files[0].Name.Name, codes, nil
+}
+
+// checkStatuses checks that the HTTP statuses listed by the package doc of the files are the ones allowed by
+// -statuses, so that the documented statuses cannot drift from the enforced ones. Nothing is checked if the
+// package doc lists no status or -statuses is not set.
+func checkStatuses(files []*ast.File) error {
+	var documented []int
+	for _, f := range files {
+		for line := range strings.SplitSeq(f.Doc.Text(), "\n") {
+			if m := statusPattern.FindStringSubmatch(line); m != nil {
+				status, _ := strconv.Atoi(m[1])
+				documented = append(documented, status)
+			}
+		}
+	}
+	if len(documented) == 0 || len(rules.HTTPStatuses) == 0 {
+		return nil
+	}
+	slices.Sort(documented)
+	allowed := slices.Sorted(slices.Values(rules.HTTPStatuses))
+	if !slices.Equal(documented, allowed) {
+		return fmt.Errorf("package doc lists the HTTP statuses %v, but -statuses allows %v", documented, allowed)
+	}
+	return nil
+}
+
+// parseCode returns the error code of the constant name declared by the spec vs of the declaration gen.
+func parseCode(name *ast.Ident, vs *ast.ValueSpec, gen *ast.GenDecl, info *types.Info) (errorCode, error) {
+	c, ok := info.Defs[name].(*types.Const)
+	if !ok || c.Val().Kind() != constant.Int {
+		return errorCode{}, fmt.Errorf("%s is not an integer constant", name.Name)
+	}
+	value, ok := constant.Int64Val(c.Val())
+	if !ok {
+		return errorCode{}, fmt.Errorf("value of %s overflows int64", name.Name)
+	}
+
+	group, comment := gen.Doc.Text(), vs.Doc.Text()
+	if !gen.Lparen.IsValid() {
+		// the comment of a single constant is the one of its declaration
+		group, comment = "", gen.Doc.Text()
+	}
+	lines := strings.Split(strings.TrimSpace(comment), "\n")
+	m := commentPattern.FindStringSubmatch(lines[0])
+	if m == nil {
+		return errorCode{}, fmt.Errorf("comment %q of %s does not match `// %s - <status>: <message>.`",
+			lines[0], name.Name, name.Name)
+	}
+	if m[1] != name.Name {
+		return errorCode{}, fmt.Errorf("comment of %s names %s", name.Name, m[1])
+	}
+
+	translations := map[string]string{}
+	for _, line := range lines[1:] {
+		t := translationPattern.FindStringSubmatch(line)
+		if t == nil {
+			return errorCode{}, fmt.Errorf("translation %q of %s does not match `// <locale>: <message>.`",
+				line, name.Name)
+		}
+		tag, err := language.Parse(t[1])
+		if err != nil {
+			return errorCode{}, fmt.Errorf("translation of %s has invalid locale %q", name.Name, t[1])
+		}
+		locale := tag.String()
+		if locale == serrors.DefaultLocale {
+			return errorCode{}, fmt.Errorf("translation of %s is in the default locale %s", name.Name, locale)
+		}
+		if _, ok := translations[locale]; ok {
+			return errorCode{}, fmt.Errorf("%s has more than one translation in %s", name.Name, locale)
+		}
+		translations[locale] = t[2]
+	}
+
+	status, _ := strconv.Atoi(m[2])
+	return errorCode{
+		name:         name.Name,
+		value:        value,
+		status:       status,
+		message:      m[3],
+		translations: translations,
+		group:        strings.TrimSuffix(strings.TrimSpace(group), "."),
+	}, nil
+}
+
+// coder returns the serrors.Coder of the code.
+func (c errorCode) coder() serrors.Coder {
+	return serrors.Code{C: int(c.value), HTTP: c.status, Ext: c.message}
+}
+
+// services returns the services of the codes in the order of their first codes.
+func services(codes []errorCode) []string {
+	var names []string
+	for _, c := range codes {
+		if !slices.Contains(names, c.service) {
+			names = append(names, c.service)
+		}
+	}
+	return names
+}
+
+// localesOf returns the sorted locales of the translations of the codes.
+func localesOf(codes []errorCode) []string {
+	var locales []string
+	for _, c := range codes {
+		for locale := range c.translations {
+			if !slices.Contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// generate returns the Go file of the package pkg which registers the codes with the rules.
+func generate(pkg string, codes []errorCode) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\npackage %s\n\n", header, pkg)
+	b.WriteString("import \"github.com/strayca7/siam/pkg/serrors\"\n\n")
+
+	b.WriteString("// rules are the rules the error codes are validated by when they are registered.\n")
+	b.WriteString("var rules = &serrors.Rules{\n")
+	if len(rules.HTTPStatuses) > 0 {
+		statuses := make([]string, len(rules.HTTPStatuses))
+		for i, status := range rules.HTTPStatuses {
+			statuses[i] = strconv.Itoa(status)
+		}
+		fmt.Fprintf(&b, "HTTPStatuses: []int{%s},\n", strings.Join(statuses, ", "))
+	}
+	if len(rules.Ranges) > 0 {
+		b.WriteString("Ranges: map[string]serrors.Range{\n")
+		for _, service := range slices.Sorted(maps.Keys(rules.Ranges)) {
+			r := rules.Ranges[service]
+			fmt.Fprintf(&b, "%q: {Min: %d, Max: %d},\n", service, r.Min, r.Max)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// init registers the error codes defined in this package to package serrors.\n")
+	b.WriteString("func init() {\n")
+	for _, service := range services(codes) {
+		fmt.Fprintf(&b, "serrors.MustRegisterAll(rules, %q,\n", service)
+		for _, c := range codes {
+			if c.service == service {
+				fmt.Fprintf(&b, "serrors.Code{C: %s, HTTP: %d, Ext: %q},\n", c.name, c.status, c.message)
+			}
+		}
+		b.WriteString(")\n")
+	}
+	for _, locale := range localesOf(codes) {
+		fmt.Fprintf(&b, "serrors.MustRegisterMessages(%q, map[int]string{\n", locale)
+		for _, c := range codes {
+			if msg, ok := c.translations[locale]; ok {
+				fmt.Fprintf(&b, "%s: %q,\n", c.name, msg)
+			}
+		}
+		b.WriteString("})\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		// the source is generated from the validated codes, it is a bug of the generator if it does not compile
+		log.Fatalf("format generated code: %v\n%s", err, b.Bytes())
+	}
+	return src
+}
+
+// packageOf returns the name of the package of the Go files in dir, except the generated ones.
+func packageOf(dir string) (string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil {
+			return "", err
+		}
+		if !ast.IsGenerated(f) {
+			return f.Name.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no Go files in %s", dir)
+}
+
+// constants returns the Go file of the package pkg which defines the codes as its constants,
+// with the comments of the codes except the translations.
+func constants(pkg string, codes []errorCode) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// %s\n\npackage %s\n", header, pkg)
+	for _, service := range services(codes) {
+		fmt.Fprintf(&b, "\n// The error codes of %s, they are the `code` fields of the error responses.\n", service)
+		b.WriteString("const (\n")
+		for _, c := range codes {
+			if c.service == service {
+				fmt.Fprintf(&b, "// %s - %d: %s.\n%s = %d\n", c.name, c.status, c.message, c.name, c.value)
+			}
+		}
+		b.WriteString(")\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("format generated code: %v\n%s", err, b.Bytes())
+	}
+	return src
+}
+
+// document returns the Markdown reference of the codes, which are listed in the tables of their groups.
+func document(codes []errorCode) []byte {
+	var b bytes.Buffer
+	b.WriteString("# Error codes\n\n")
+	fmt.Fprintf(&b, "<!-- %s -->\n\n", header)
+	b.WriteString("The error responses of siam carry one of the following codes in the `code` field, ")
+	b.WriteString("the response has the HTTP status and the message of the code.\n")
+	if len(rules.HTTPStatuses) > 0 {
+		statuses := make([]string, len(rules.HTTPStatuses))
+		for i, status := range rules.HTTPStatuses {
+			statuses[i] = strconv.Itoa(status)
+		}
+		fmt.Fprintf(&b, "\nThe codes use only the HTTP statuses %s.\n", strings.Join(statuses, ", "))
+	}
+	if len(rules.Ranges) > 0 {
+		b.WriteString("\nThe codes of every service are in the range of the service:\n\n")
+		b.WriteString("| Service | Codes |\n")
+		b.WriteString("| ------- | ----- |\n")
+		for _, service := range services(codes) {
+			fmt.Fprintf(&b, "| %s | %s |\n", service, rules.Ranges[service])
+		}
+	}
+
+	// the messages are translated into the locales of the columns after the message of DefaultLocale
+	locales := localesOf(codes)
+	escape := strings.NewReplacer("|", `\|`)
+	group := ""
+	for i, c := range codes {
+		if i == 0 || c.group != group {
+			group = c.group
+			title := group
+			if title == "" {
+				title = "Other errors"
+			}
+			fmt.Fprintf(&b, "\n## %s\n\n", title)
+			b.WriteString("| Identifier | Code | HTTP status | Message |")
+			for _, locale := range locales {
+				fmt.Fprintf(&b, " %s |", locale)
+			}
+			b.WriteString("\n| ---------- | ---- | ----------- | ------- |")
+			b.WriteString(strings.Repeat(" ----- |", len(locales)))
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %s |", c.name, c.value, c.status, escape.Replace(c.message))
+		for _, locale := range locales {
+			fmt.Fprintf(&b, " %s |", escape.Replace(c.translations[locale]))
+		}
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// write writes the data to the file, creating its directory if needed.
+func write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
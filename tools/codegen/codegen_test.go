@@ -0,0 +1,109 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// parseDoc parses a file of the package doc.
+func parseDoc(t *testing.T, doc string) *ast.File {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "doc.go", doc+"\npackage code\n", parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse doc: %v", err)
+	}
+	return f
+}
+
+func TestCheckStatuses(t *testing.T) {
+	const listed = `/*
+Package code defines the error codes.
+
+	StatusOK                  = 200 // RFC 7231, 6.3.
+	StatusNotFound            = 404 // RFC 7231, 6.5.4
+	StatusInternalServerError = 500 // RFC 7231, 6.6.1
+*/`
+	tests := []struct {
+		name     string
+		doc      string
+		statuses []int
+		wantErr  bool
+	}{
+		{"same statuses", listed, []int{500, 200, 404}, false},
+		{"status not documented", listed, []int{200, 404, 409, 500}, true},
+		{"status not allowed", listed, []int{200, 500}, true},
+		{"no documented statuses", "// Package code defines the error codes.", []int{200}, false},
+		{"no allowed statuses", listed, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules.HTTPStatuses = tt.statuses
+			t.Cleanup(func() { rules.HTTPStatuses = nil })
+			if err := checkStatuses([]*ast.File{parseDoc(t, tt.doc)}); (err != nil) != tt.wantErr {
+				t.Errorf("checkStatuses() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// codeFile is a code package of a single code, whose message is replaced by the tests.
+const codeFile = `package code
+
+// user errors.
+const (
+	// ErrUserNotFound - 404: User not found.
+	// zh-CN: 用户不存在。
+	ErrUserNotFound = iota + 110001
+)
+`
+
+// generateFiles generates the files of the code package in dir like main, and returns them by their paths.
+func generateFiles(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	pkg, codes, err := parse(dir)
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	return map[string][]byte{
+		filepath.Join(dir, "code_generated.go"): generate(pkg, codes),
+		filepath.Join(dir, "error_code.md"):     document(codes),
+	}
+}
+
+func TestOutdated(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "apiserver.go")
+	if err := os.WriteFile(source, []byte(codeFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files := generateFiles(t, dir)
+	paths := slices.Sorted(maps.Keys(files))
+	if got := outdated(files); !slices.Equal(got, paths) {
+		t.Errorf("outdated() before the generation = %v, want %v", got, paths)
+	}
+	for path, data := range files {
+		if err := write(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the generated files are skipped by parse, so they are up to date after they are written
+	if got := outdated(generateFiles(t, dir)); len(got) != 0 {
+		t.Errorf("outdated() after the generation = %v, want none", got)
+	}
+
+	changed := strings.Replace(codeFile, "User not found", "User does not exist", 1)
+	if err := os.WriteFile(source, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := outdated(generateFiles(t, dir)); !slices.Equal(got, paths) {
+		t.Errorf("outdated() after the change = %v, want %v", got, paths)
+	}
+}